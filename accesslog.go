@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+// responseRecorder records the status and the size of the response for the access log and the metrics
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// accessLogEntry holds the values known only by the inner handlers, e.g. the UID known by AuthMiddle
+type accessLogEntry struct {
+	uid string
+}
+
+type accessLogEntryKey struct{}
+
+// setAccessLogUid sets the authenticated user to the access log of the request
+func setAccessLogUid(ctx context.Context, uid string) {
+	if entry, ok := ctx.Value(accessLogEntryKey{}).(*accessLogEntry); ok {
+		entry.uid = uid
+	}
+}
+
+// AccessLogMiddle writes one entry per request with the method, the route template, the status,
+// the duration, the response size and the authenticated user.
+// The route template is logged instead of the raw path not to leak UIDs in the path.
+func AccessLogMiddle(l logger.Logger, router *mux.Router) MFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			entry := &accessLogEntry{}
+			rec := &responseRecorder{ResponseWriter: w}
+			ctx := context.WithValue(r.Context(), accessLogEntryKey{}, entry)
+			// The route is logged by all the entries of the request
+			ctx = logger.WithRoute(ctx, routeTemplate(router, r))
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			l.LogFields(ctx, logger.Info, "access", logger.Fields{
+				"method":      r.Method,
+				"status":      rec.status,
+				"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"bytes":       rec.bytes,
+				"uid":         entry.uid,
+			})
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route or empty if no route matches
+func routeTemplate(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return ""
+	}
+	tpl, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	return tpl
+}
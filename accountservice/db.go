@@ -0,0 +1,14 @@
+package accountservice
+
+import (
+	"database/sql"
+)
+
+type SqlDb struct {
+	*sql.DB
+}
+
+// ProvideDB provides the DB pool shared by the services
+func ProvideDB(db *sql.DB) SqlDb {
+	return SqlDb{db}
+}
@@ -0,0 +1,311 @@
+package accountservice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/momotaro98/stew"
+
+	"github.com/momotaro98/mixlunch-service-api/partyservice"
+	"github.com/momotaro98/mixlunch-service-api/tagservice"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+	"github.com/momotaro98/mixlunch-service-api/userservice"
+	"github.com/momotaro98/mixlunch-service-api/utils"
+)
+
+// AccountArchive is all the personal data of a user stored by the service.
+// The identities of the other users which the service never shows to the user are not in the archive,
+// i.e. the reviewers of ReviewsReceived and the users blocking the user, whose blocks aren't exported at all.
+// Exporting them would tell who has reviewed or blocked the user.
+type AccountArchive struct {
+	UserId          string                    `json:"user_id"`
+	ExportedAt      time.Time                 `json:"exported_at"`
+	Profile         *ProfileArchive           `json:"profile"`
+	Location        *LocationArchive          `json:"location"`
+	Languages       []string                  `json:"languages"`
+	OccupationIDs   []uint16                  `json:"occupation_ids"`
+	Tags            []*TagArchive             `json:"tags"`
+	Schedules       []*ScheduleArchive        `json:"schedules"`
+	Parties         []*PartyMembershipArchive `json:"parties"`
+	ReviewsGiven    []*ReviewArchive          `json:"reviews_given"`
+	ReviewsReceived []*ReviewArchive          `json:"reviews_received"`
+	Blocks          []*BlockArchive           `json:"blocks"`
+}
+
+type ProfileArchive struct {
+	Name               string    `json:"name"`
+	Email              string    `json:"email"`
+	NickName           string    `json:"nick_name"`
+	Sex                string    `json:"sex"`
+	Birthday           string    `json:"birthday"`
+	PhotoUrl           string    `json:"photo_url"`
+	Position           string    `json:"position"`
+	AcademicBackground string    `json:"academic_background"`
+	Company            string    `json:"company"`
+	SelfIntroduction   string    `json:"self_introduction"`
+	Role               string    `json:"role"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type LocationArchive struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type TagArchive struct {
+	TagId   uint16 `json:"id"`
+	Name    string `json:"name"`
+	TagType string `json:"tag_type"`
+}
+
+type ScheduleArchive struct {
+	UserScheduleId int64            `json:"user_schedule_id"`
+	FromDateTime   time.Time        `json:"from_date_time"`
+	ToDateTime     time.Time        `json:"to_date_time"`
+	LocationTypeID int8             `json:"location_type_id"`
+	Location       *LocationArchive `json:"location"`
+	TagIds         []uint16         `json:"tag_ids"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+type PartyMembershipArchive struct {
+	PartyID    int64     `json:"party_id"`
+	StartFrom  time.Time `json:"start_from"`
+	EndTo      time.Time `json:"end_to"`
+	ChatRoomId string    `json:"chat_room_id"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+// ReviewArchive is a review given or received by the user, whose Reviewee is only for the given one
+type ReviewArchive struct {
+	PartyID   int64     `json:"party_id"`
+	Reviewee  string    `json:"reviewee,omitempty"`
+	Score     float64   `json:"score"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BlockArchive struct {
+	Blockee   string    `json:"blockee"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AccountErasure is the result of the erasure of a user
+type AccountErasure struct {
+	UserId   string    `json:"user_id"`
+	ErasedAt time.Time `json:"erased_at"`
+	// PendingChatRoomIds are the chat rooms where the user is not anonymized yet,
+	// which AnonymizePendingChatRooms retries later
+	PendingChatRoomIds []string `json:"pending_chat_room_ids"`
+}
+
+type AccountServer interface {
+	ExportAccount(ctx context.Context, userId string) (*AccountArchive, error)
+	DeleteAccount(ctx context.Context, userId, requestedBy string) (*AccountErasure, error)
+	AnonymizePendingChatRooms(ctx context.Context) error
+}
+
+const (
+	// anonymizeAttempts is the number of the attempts to anonymize a chat room on the erasure
+	anonymizeAttempts = 3
+	// anonymizeRetryInterval is the interval between the attempts
+	anonymizeRetryInterval = time.Second
+)
+
+type realAccountServer struct {
+	accountQueryRepository   IAccountQueryRepository
+	accountCommandRepository IAccountCommandRepository
+	partyServer              partyservice.PartyServer
+	retryInterval            time.Duration
+}
+
+func ProvideAccountServer(accountQueryRepository IAccountQueryRepository,
+	accountCommandRepository IAccountCommandRepository,
+	partyServer partyservice.PartyServer) AccountServer {
+	return &realAccountServer{
+		accountQueryRepository:   accountQueryRepository,
+		accountCommandRepository: accountCommandRepository,
+		partyServer:              partyServer,
+		retryInterval:            anonymizeRetryInterval,
+	}
+}
+
+// ExportAccount returns all the personal data of the user.
+// If the user is not in DB, return UserNotFoundError
+func (s *realAccountServer) ExportAccount(ctx context.Context, userId string) (*AccountArchive, error) {
+	ctx, span := tracing.Start(ctx, "accountservice.ExportAccount")
+	defer span.End()
+	aDto, err := s.accountQueryRepository.QueryAccountArchive(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, userservice.NewUserNotFoundError(userId)
+		}
+		return nil, stew.Wrap(err)
+	}
+
+	// Map from DTO to Service Model, whose empty lists are exported as empty arrays not null
+	u := aDto.user
+	archive := &AccountArchive{
+		UserId:     u.userId,
+		ExportedAt: time.Now().UTC(),
+		Profile: &ProfileArchive{
+			Name:               u.name,
+			Email:              u.email,
+			NickName:           u.nickName.String,
+			Sex:                u.sex,
+			Birthday:           u.birthday.Format("2006-01-02"),
+			PhotoUrl:           u.photoUrl.String,
+			Position:           u.positionName.String,
+			AcademicBackground: u.academicBackground.String,
+			Company:            u.company.String,
+			SelfIntroduction:   u.selfIntroduction.String,
+			Role:               u.role.String,
+			CreatedAt:          u.createdAt,
+			UpdatedAt:          u.updatedAt,
+		},
+		Location:        newLocationArchive(u.latitude, u.longitude),
+		Languages:       append(make([]string, 0, len(aDto.langs)), aDto.langs...),
+		OccupationIDs:   append(make([]uint16, 0, len(aDto.occupationIDs)), aDto.occupationIDs...),
+		Tags:            make([]*TagArchive, 0, len(aDto.tags)),
+		Schedules:       make([]*ScheduleArchive, 0, len(aDto.schedules)),
+		Parties:         make([]*PartyMembershipArchive, 0, len(aDto.parties)),
+		ReviewsGiven:    make([]*ReviewArchive, 0, len(aDto.reviewsGiven)),
+		ReviewsReceived: make([]*ReviewArchive, 0, len(aDto.reviewsReceived)),
+		Blocks:          make([]*BlockArchive, 0, len(aDto.blocks)),
+	}
+	for _, t := range aDto.tags {
+		archive.Tags = append(archive.Tags, &TagArchive{
+			TagId:   t.tagId,
+			Name:    t.name,
+			TagType: tagservice.TagType(t.tagTypeId).String(),
+		})
+	}
+	for _, sDto := range aDto.schedules {
+		archive.Schedules = append(archive.Schedules, &ScheduleArchive{
+			UserScheduleId: sDto.userScheduleId,
+			FromDateTime:   sDto.fromDateTime,
+			ToDateTime:     sDto.toDateTime,
+			LocationTypeID: sDto.locationTypeId,
+			Location:       newLocationArchive(sDto.latitude, sDto.longitude),
+			TagIds:         append(make([]uint16, 0, len(sDto.tagIds)), sDto.tagIds...),
+			CreatedAt:      sDto.createdAt,
+		})
+	}
+	for _, pDto := range aDto.parties {
+		archive.Parties = append(archive.Parties, &PartyMembershipArchive{
+			PartyID:    pDto.partyId,
+			StartFrom:  pDto.startFrom,
+			EndTo:      pDto.endTo,
+			ChatRoomId: pDto.chatRoomId.String,
+			JoinedAt:   pDto.joinedAt,
+		})
+	}
+	for _, rDto := range aDto.reviewsGiven {
+		archive.ReviewsGiven = append(archive.ReviewsGiven, newReviewArchive(rDto))
+	}
+	for _, rDto := range aDto.reviewsReceived {
+		archive.ReviewsReceived = append(archive.ReviewsReceived, newReviewArchive(rDto))
+	}
+	for _, bDto := range aDto.blocks {
+		archive.Blocks = append(archive.Blocks, &BlockArchive{
+			Blockee:   bDto.blockee,
+			CreatedAt: bDto.createdAt,
+		})
+	}
+
+	return archive, nil
+}
+
+func newLocationArchive(latitude, longitude sql.NullFloat64) *LocationArchive {
+	if !latitude.Valid || !longitude.Valid {
+		return nil
+	}
+	return &LocationArchive{Latitude: latitude.Float64, Longitude: longitude.Float64}
+}
+
+func newReviewArchive(rDto *ReviewArchiveDto) *ReviewArchive {
+	return &ReviewArchive{
+		PartyID:   rDto.partyId,
+		Reviewee:  rDto.reviewee,
+		Score:     rDto.score,
+		Comment:   rDto.comments.String,
+		CreatedAt: rDto.createdAt,
+	}
+}
+
+// DeleteAccount erases the user from all the tables and records the erasure requested by requestedBy
+// in the audit trail, and then anonymizes the user in the chat rooms of the parties of the user.
+// The membership of a chat room is the one of the party, which is removed with the user from partymembers table,
+// as the chat room in Firestore has no members but the messages. The messages of the user are kept anonymized
+// not to break the conversations of the other members.
+// The chat rooms are anonymized after the erasure is committed not to lock the user while calling Firestore,
+// and the ones failed are left pending in the audit trail for AnonymizePendingChatRooms.
+// If the user is not in DB, return UserNotFoundError
+func (s *realAccountServer) DeleteAccount(ctx context.Context, userId, requestedBy string) (*AccountErasure, error) {
+	ctx, span := tracing.Start(ctx, "accountservice.DeleteAccount")
+	defer span.End()
+	chatRooms, err := s.accountCommandRepository.EraseUser(ctx, &UserErasureDto{
+		userId:      userId,
+		requestedBy: utils.NewNullString(requestedBy),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, userservice.NewUserNotFoundError(userId)
+		}
+		return nil, stew.Wrap(err)
+	}
+
+	erasure := &AccountErasure{
+		UserId:             userId,
+		ErasedAt:           time.Now().UTC(),
+		PendingChatRoomIds: []string{},
+	}
+	for _, c := range chatRooms {
+		if err := s.anonymizeChatRoom(ctx, c, anonymizeAttempts); err != nil {
+			erasure.PendingChatRoomIds = append(erasure.PendingChatRoomIds, c.chatRoomId)
+		}
+	}
+	return erasure, nil
+}
+
+// AnonymizePendingChatRooms anonymizes the erased users in the chat rooms left pending by DeleteAccount.
+// The rest of the chat rooms are tried even if one fails, and the first error is returned.
+func (s *realAccountServer) AnonymizePendingChatRooms(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "accountservice.AnonymizePendingChatRooms")
+	defer span.End()
+	chatRooms, err := s.accountQueryRepository.QueryPendingChatRooms(ctx)
+	if err != nil {
+		return stew.Wrap(err)
+	}
+	var firstErr error
+	for _, c := range chatRooms {
+		if err := s.anonymizeChatRoom(ctx, c, 1); err != nil && firstErr == nil {
+			firstErr = stew.Wrap(err)
+		}
+	}
+	return firstErr
+}
+
+// anonymizeChatRoom anonymizes the erased user in the chat room in attempts, and records it in the audit trail.
+// Anonymizing a chat room twice does nothing so that it can be retried.
+func (s *realAccountServer) anonymizeChatRoom(ctx context.Context, c *ErasedChatRoomDto, attempts int) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			t := time.NewTimer(s.retryInterval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+		if err = s.partyServer.AnonymizeChatRooms(ctx, c.userId, []string{c.chatRoomId}); err == nil {
+			return s.accountCommandRepository.MarkChatRoomAnonymized(ctx, c)
+		}
+	}
+	return err
+}
@@ -0,0 +1,199 @@
+package accountservice
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/momotaro98/stew"
+
+	"github.com/momotaro98/mixlunch-service-api/cmd/grpc/testmock"
+	"github.com/momotaro98/mixlunch-service-api/userservice"
+)
+
+//go:generate mockgen -source=repositories.go -destination=repositories_mock.go -package=accountservice -self_package=github.com/momotaro98/mixlunch-service-api/accountservice
+// The mock of PartyServer is the one generated for the gRPC server
+
+const (
+	uid   = "USER_ID"
+	admin = "ADMIN_ID"
+)
+
+func TestExportAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		createdAt := time.Date(2020, 7, 17, 1, 2, 3, 0, time.UTC)
+		accountQueryRepositoryMock := NewMockIAccountQueryRepository(mockCtrl)
+		accountQueryRepositoryMock.EXPECT().
+			QueryAccountArchive(gomock.Any(), uid).
+			Return(&AccountArchiveDto{
+				user: &UserArchiveDto{
+					userId:    uid,
+					name:      "David John",
+					email:     "a@a.com",
+					birthday:  time.Date(1992, 4, 4, 0, 0, 0, 0, time.UTC),
+					latitude:  sql.NullFloat64{Float64: 35.681236, Valid: true},
+					longitude: sql.NullFloat64{Float64: 139.767125, Valid: true},
+					createdAt: createdAt,
+				},
+				langs: []string{"en"},
+				tags:  []*TagArchiveDto{{tagId: 8, name: "Go", tagTypeId: 2}},
+				schedules: []*ScheduleArchiveDto{
+					{userScheduleId: 1, locationTypeId: 1, tagIds: []uint16{8}},
+				},
+				reviewsGiven:    []*ReviewArchiveDto{{partyId: 3, reviewee: "other", score: 4.5}},
+				reviewsReceived: []*ReviewArchiveDto{{partyId: 3, score: 5, comments: sql.NullString{String: "Nice", Valid: true}}},
+			}, nil)
+		accountServer := ProvideAccountServer(accountQueryRepositoryMock, NewMockIAccountCommandRepository(mockCtrl), testmock.NewMockPartyServer(mockCtrl))
+		// Act
+		archive, err := accountServer.ExportAccount(context.Background(), uid)
+		// Assert
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if archive.Profile.Birthday != "1992-04-04" || archive.Location.Latitude != 35.681236 {
+			t.Errorf("unexpected profile %+v %+v", archive.Profile, archive.Location)
+		}
+		if archive.Tags[0].TagType != "Skill" || archive.Schedules[0].Location != nil {
+			t.Errorf("unexpected tags %+v and schedules %+v", archive.Tags[0], archive.Schedules[0])
+		}
+		res, err := json.Marshal(archive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The empty lists are exported as empty arrays
+		for _, expected := range []string{`"occupation_ids":[]`, `"parties":[]`, `"blocks":[]`, `"reviewee":"other"`} {
+			if !strings.Contains(string(res), expected) {
+				t.Errorf("expected %s in %s", expected, res)
+			}
+		}
+		// The reviewers of the received reviews are not exported
+		if strings.Contains(string(res), `"reviews_received":[{"party_id":3,"reviewee"`) {
+			t.Errorf("unexpected reviewee of the received review in %s", res)
+		}
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		accountQueryRepositoryMock := NewMockIAccountQueryRepository(mockCtrl)
+		accountQueryRepositoryMock.EXPECT().
+			QueryAccountArchive(gomock.Any(), uid).
+			Return(nil, stew.Wrap(sql.ErrNoRows))
+		accountServer := ProvideAccountServer(accountQueryRepositoryMock, NewMockIAccountCommandRepository(mockCtrl), testmock.NewMockPartyServer(mockCtrl))
+		_, err := accountServer.ExportAccount(context.Background(), uid)
+		if _, ok := err.(*userservice.UserNotFoundError); !ok {
+			t.Errorf("expected: UserNotFoundError, actual: %v", err)
+		}
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	erasedChatRooms := func(chatRoomIds ...string) []*ErasedChatRoomDto {
+		chatRooms := make([]*ErasedChatRoomDto, 0, len(chatRoomIds))
+		for _, chatRoomId := range chatRoomIds {
+			chatRooms = append(chatRooms, &ErasedChatRoomDto{userErasureId: 1, userId: uid, chatRoomId: chatRoomId})
+		}
+		return chatRooms
+	}
+
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		chatRooms := erasedChatRooms("room1", "room3")
+		accountCommandRepositoryMock := NewMockIAccountCommandRepository(mockCtrl)
+		partyServerMock := testmock.NewMockPartyServer(mockCtrl)
+		// The chat rooms are anonymized after the erasure
+		gomock.InOrder(
+			accountCommandRepositoryMock.EXPECT().
+				EraseUser(gomock.Any(), &UserErasureDto{
+					userId:      uid,
+					requestedBy: sql.NullString{String: admin, Valid: true},
+				}).
+				Return(chatRooms, nil),
+			partyServerMock.EXPECT().AnonymizeChatRooms(gomock.Any(), uid, []string{"room1"}).Return(nil),
+			accountCommandRepositoryMock.EXPECT().MarkChatRoomAnonymized(gomock.Any(), chatRooms[0]).Return(nil),
+			partyServerMock.EXPECT().AnonymizeChatRooms(gomock.Any(), uid, []string{"room3"}).Return(nil),
+			accountCommandRepositoryMock.EXPECT().MarkChatRoomAnonymized(gomock.Any(), chatRooms[1]).Return(nil),
+		)
+		accountServer := ProvideAccountServer(NewMockIAccountQueryRepository(mockCtrl), accountCommandRepositoryMock, partyServerMock)
+		// Act
+		erasure, err := accountServer.DeleteAccount(context.Background(), uid, admin)
+		// Assert
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if erasure.UserId != uid {
+			t.Errorf("expected: %s, actual: %s", uid, erasure.UserId)
+		}
+		if len(erasure.PendingChatRoomIds) != 0 {
+			t.Errorf("expected: no pending chat room, actual: %v", erasure.PendingChatRoomIds)
+		}
+	})
+
+	t.Run("the chat room is retried and left pending after the erasure", func(t *testing.T) {
+		chatRooms := erasedChatRooms("room1", "room3")
+		accountCommandRepositoryMock := NewMockIAccountCommandRepository(mockCtrl)
+		accountCommandRepositoryMock.EXPECT().EraseUser(gomock.Any(), gomock.Any()).Return(chatRooms, nil)
+		partyServerMock := testmock.NewMockPartyServer(mockCtrl)
+		partyServerMock.EXPECT().AnonymizeChatRooms(gomock.Any(), uid, []string{"room1"}).
+			Return(errors.New("unavailable")).
+			Times(anonymizeAttempts)
+		// The failed chat room doesn't stop the others
+		partyServerMock.EXPECT().AnonymizeChatRooms(gomock.Any(), uid, []string{"room3"}).Return(nil)
+		accountCommandRepositoryMock.EXPECT().MarkChatRoomAnonymized(gomock.Any(), chatRooms[1]).Return(nil)
+		accountServer := ProvideAccountServer(NewMockIAccountQueryRepository(mockCtrl), accountCommandRepositoryMock, partyServerMock)
+		accountServer.(*realAccountServer).retryInterval = 0
+		erasure, err := accountServer.DeleteAccount(context.Background(), uid, uid)
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if expected := []string{"room1"}; !reflect.DeepEqual(erasure.PendingChatRoomIds, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, erasure.PendingChatRoomIds)
+		}
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		// The chat rooms aren't anonymized for the user not in DB
+		partyServerMock := testmock.NewMockPartyServer(mockCtrl)
+		accountCommandRepositoryMock := NewMockIAccountCommandRepository(mockCtrl)
+		accountCommandRepositoryMock.EXPECT().
+			EraseUser(gomock.Any(), gomock.Any()).
+			Return(nil, stew.Wrap(sql.ErrNoRows))
+		accountServer := ProvideAccountServer(NewMockIAccountQueryRepository(mockCtrl), accountCommandRepositoryMock, partyServerMock)
+		_, err := accountServer.DeleteAccount(context.Background(), uid, uid)
+		if _, ok := err.(*userservice.UserNotFoundError); !ok {
+			t.Errorf("expected: UserNotFoundError, actual: %v", err)
+		}
+	})
+}
+
+func TestAnonymizePendingChatRooms(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	chatRooms := []*ErasedChatRoomDto{
+		{userErasureId: 1, userId: uid, chatRoomId: "room1"},
+		{userErasureId: 2, userId: admin, chatRoomId: "room2"},
+	}
+	accountQueryRepositoryMock := NewMockIAccountQueryRepository(mockCtrl)
+	accountQueryRepositoryMock.EXPECT().QueryPendingChatRooms(gomock.Any()).Return(chatRooms, nil)
+	accountCommandRepositoryMock := NewMockIAccountCommandRepository(mockCtrl)
+	partyServerMock := testmock.NewMockPartyServer(mockCtrl)
+	// Each chat room is tried once, and the failed one is kept pending
+	partyServerMock.EXPECT().AnonymizeChatRooms(gomock.Any(), uid, []string{"room1"}).Return(errors.New("unavailable"))
+	partyServerMock.EXPECT().AnonymizeChatRooms(gomock.Any(), admin, []string{"room2"}).Return(nil)
+	accountCommandRepositoryMock.EXPECT().MarkChatRoomAnonymized(gomock.Any(), chatRooms[1]).Return(nil)
+	accountServer := ProvideAccountServer(accountQueryRepositoryMock, accountCommandRepositoryMock, partyServerMock)
+
+	if err := accountServer.AnonymizePendingChatRooms(context.Background()); err == nil {
+		t.Error("expected the error")
+	}
+}
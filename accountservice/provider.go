@@ -0,0 +1,33 @@
+package accountservice
+
+import (
+	"github.com/google/wire"
+
+	"github.com/momotaro98/mixlunch-service-api/partyservice"
+	"github.com/momotaro98/mixlunch-service-api/tagservice"
+	"github.com/momotaro98/mixlunch-service-api/userservice"
+)
+
+var SuperSet = wire.NewSet(
+	// Tag service
+	tagservice.ProvideDB,
+	tagservice.ProvideTagQueryRepository,
+	tagservice.ProvideTagServer,
+	// User service, which uses Tag service
+	userservice.ProvideDB,
+	userservice.ProvideUserQueryRepository,
+	userservice.ProvideUserCommandRepository,
+	userservice.ProvideUserServer,
+	// Party service, which uses User service
+	partyservice.ProvideDB,
+	partyservice.ProvideApp,
+	partyservice.ProvidePartyQueryRepository,
+	partyservice.ProvidePartyCommandRepository,
+	partyservice.ProvideChatRoomRepository,
+	partyservice.ProvidePartyServer,
+	// Account service, which uses Party service
+	ProvideDB,
+	ProvideAccountQueryRepository,
+	ProvideAccountCommandRepository,
+	ProvideAccountServer,
+)
@@ -0,0 +1,491 @@
+package accountservice
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/momotaro98/stew"
+)
+
+// AccountArchiveDto is all the rows of the user's personal data
+type AccountArchiveDto struct {
+	user            *UserArchiveDto
+	langs           []string
+	occupationIDs   []uint16
+	tags            []*TagArchiveDto
+	schedules       []*ScheduleArchiveDto
+	parties         []*PartyArchiveDto
+	reviewsGiven    []*ReviewArchiveDto
+	reviewsReceived []*ReviewArchiveDto
+	blocks          []*BlockArchiveDto
+}
+
+type UserArchiveDto struct {
+	userId             string
+	name               string
+	email              string
+	nickName           sql.NullString
+	sex                string
+	birthday           time.Time
+	photoUrl           sql.NullString
+	positionName       sql.NullString
+	academicBackground sql.NullString
+	company            sql.NullString
+	selfIntroduction   sql.NullString
+	latitude           sql.NullFloat64
+	longitude          sql.NullFloat64
+	role               sql.NullString
+	createdAt          time.Time
+	updatedAt          time.Time
+}
+
+type TagArchiveDto struct {
+	tagId     uint16
+	name      string
+	tagTypeId int8
+}
+
+type ScheduleArchiveDto struct {
+	userScheduleId int64
+	fromDateTime   time.Time
+	toDateTime     time.Time
+	locationTypeId int8
+	latitude       sql.NullFloat64
+	longitude      sql.NullFloat64
+	tagIds         []uint16
+	createdAt      time.Time
+}
+
+type PartyArchiveDto struct {
+	partyId    int64
+	startFrom  time.Time
+	endTo      time.Time
+	chatRoomId sql.NullString
+	joinedAt   time.Time
+}
+
+type ReviewArchiveDto struct {
+	partyId   int64
+	reviewee  string
+	score     float64
+	comments  sql.NullString
+	createdAt time.Time
+}
+
+type BlockArchiveDto struct {
+	blockee   string
+	createdAt time.Time
+}
+
+type IAccountQueryRepository interface {
+	QueryAccountArchive(ctx context.Context, userId string) (*AccountArchiveDto, error)
+	QueryPendingChatRooms(ctx context.Context) ([]*ErasedChatRoomDto, error)
+}
+
+var _ IAccountQueryRepository = (*realAccountQueryRepository)(nil)
+
+type realAccountQueryRepository struct {
+	db SqlDb
+}
+
+func ProvideAccountQueryRepository(db SqlDb) IAccountQueryRepository {
+	return &realAccountQueryRepository{
+		db: db,
+	}
+}
+
+// QueryAccountArchive queries the rows of the user in all the tables.
+// The queries share a read only transaction to see a snapshot of the tables.
+// It returns sql.ErrNoRows when the user isn't registered.
+func (r *realAccountQueryRepository) QueryAccountArchive(ctx context.Context, userId string) (*AccountArchiveDto, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	// Nothing is written in the transaction so that it is always rolled back
+	defer tx.Rollback()
+
+	var a AccountArchiveDto
+	if a.user, err = queryUserArchive(ctx, tx, userId); err != nil {
+		return nil, err
+	}
+
+	// userlangs table
+	err = queryRows(ctx, tx, `
+		SELECT lang FROM userlangs
+		WHERE userId = ? ORDER BY lang
+		`, userId, func(rows *sql.Rows) error {
+		var lang string
+		if err := rows.Scan(&lang); err != nil {
+			return err
+		}
+		a.langs = append(a.langs, lang)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// useroccupations table
+	err = queryRows(ctx, tx, `
+		SELECT occupationId FROM useroccupations
+		WHERE userId = ? ORDER BY occupationId
+		`, userId, func(rows *sql.Rows) error {
+		var occupationID uint16
+		if err := rows.Scan(&occupationID); err != nil {
+			return err
+		}
+		a.occupationIDs = append(a.occupationIDs, occupationID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// usertags table
+	err = queryRows(ctx, tx, `
+		SELECT t.tagId, t.name, t.tagTypeId
+		FROM usertags AS ut
+		INNER JOIN tags AS t ON ut.tagId = t.tagId
+		WHERE ut.userId = ? ORDER BY t.tagId
+		`, userId, func(rows *sql.Rows) error {
+		var t TagArchiveDto
+		if err := rows.Scan(&t.tagId, &t.name, &t.tagTypeId); err != nil {
+			return err
+		}
+		a.tags = append(a.tags, &t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if a.schedules, err = queryScheduleArchives(ctx, tx, userId); err != nil {
+		return nil, err
+	}
+
+	// partymembers table
+	err = queryRows(ctx, tx, `
+		SELECT p.id, p.startFrom, p.endTo, p.chatRoomId, pm.createdAt
+		FROM partymembers AS pm
+		INNER JOIN parties AS p ON pm.partyId = p.id
+		WHERE pm.userId = ? ORDER BY p.startFrom
+		`, userId, func(rows *sql.Rows) error {
+		var p PartyArchiveDto
+		if err := rows.Scan(&p.partyId, &p.startFrom, &p.endTo, &p.chatRoomId, &p.joinedAt); err != nil {
+			return err
+		}
+		a.parties = append(a.parties, &p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// partymemberreviews table, whose reviewers of the received reviews are kept from the user
+	err = queryRows(ctx, tx, `
+		SELECT partyId, reviewee, score, comments, createdAt FROM partymemberreviews
+		WHERE reviewer = ? ORDER BY createdAt
+		`, userId, func(rows *sql.Rows) error {
+		var rv ReviewArchiveDto
+		if err := rows.Scan(&rv.partyId, &rv.reviewee, &rv.score, &rv.comments, &rv.createdAt); err != nil {
+			return err
+		}
+		a.reviewsGiven = append(a.reviewsGiven, &rv)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = queryRows(ctx, tx, `
+		SELECT partyId, score, comments, createdAt FROM partymemberreviews
+		WHERE reviewee = ? ORDER BY createdAt
+		`, userId, func(rows *sql.Rows) error {
+		var rv ReviewArchiveDto
+		if err := rows.Scan(&rv.partyId, &rv.score, &rv.comments, &rv.createdAt); err != nil {
+			return err
+		}
+		a.reviewsReceived = append(a.reviewsReceived, &rv)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// userblocklists table, only the blocks by the user not to tell who blocks the user
+	err = queryRows(ctx, tx, `
+		SELECT blockee, createdAt FROM userblocklists
+		WHERE blocker = ? ORDER BY createdAt
+		`, userId, func(rows *sql.Rows) error {
+		var b BlockArchiveDto
+		if err := rows.Scan(&b.blockee, &b.createdAt); err != nil {
+			return err
+		}
+		a.blocks = append(a.blocks, &b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func queryUserArchive(ctx context.Context, tx *sql.Tx, userId string) (*UserArchiveDto, error) {
+	var u UserArchiveDto
+	err := tx.QueryRowContext(ctx, `
+		SELECT u.userId
+			,u.name
+			,u.email
+			,u.nickName
+			,u.sex
+			,u.birthday
+			,u.photoUrl
+			,p.name AS positionName
+			,u.academicBackground
+			,u.company
+			,u.selfIntroduction
+			,ul.latitude
+			,ul.longitude
+			,ur.role
+			,u.createdAt
+			,u.updatedAt
+		FROM users AS u
+		LEFT JOIN positions AS p ON u.positionId = p.positionId
+		LEFT JOIN userlocations AS ul ON u.userId = ul.userId
+		LEFT JOIN userroles AS ur ON u.userId = ur.userId
+		WHERE u.userId = ?
+		`, userId).Scan(&u.userId, &u.name, &u.email, &u.nickName, &u.sex, &u.birthday, &u.photoUrl,
+		&u.positionName, &u.academicBackground, &u.company, &u.selfIntroduction,
+		&u.latitude, &u.longitude, &u.role, &u.createdAt, &u.updatedAt)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	return &u, nil
+}
+
+func queryScheduleArchives(ctx context.Context, tx *sql.Tx, userId string) ([]*ScheduleArchiveDto, error) {
+	var schedules []*ScheduleArchiveDto
+	schedulesById := make(map[int64]*ScheduleArchiveDto)
+	// userschedules and userschedulelocations tables
+	err := queryRows(ctx, tx, `
+		SELECT us.userScheduleId, us.fromDateTime, us.toDateTime, us.locationTypeId, usl.latitude, usl.longitude, us.createdAt
+		FROM userschedules AS us
+		LEFT JOIN userschedulelocations AS usl ON us.userScheduleId = usl.userScheduleId
+		WHERE us.userId = ? ORDER BY us.fromDateTime
+		`, userId, func(rows *sql.Rows) error {
+		var s ScheduleArchiveDto
+		if err := rows.Scan(&s.userScheduleId, &s.fromDateTime, &s.toDateTime, &s.locationTypeId,
+			&s.latitude, &s.longitude, &s.createdAt); err != nil {
+			return err
+		}
+		schedules = append(schedules, &s)
+		schedulesById[s.userScheduleId] = &s
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// userscheduletags table
+	err = queryRows(ctx, tx, `
+		SELECT ust.userScheduleId, ust.tagId
+		FROM userscheduletags AS ust
+		INNER JOIN userschedules AS us ON ust.userScheduleId = us.userScheduleId
+		WHERE us.userId = ? ORDER BY ust.userScheduleId, ust.tagId
+		`, userId, func(rows *sql.Rows) error {
+		var (
+			userScheduleId int64
+			tagId          uint16
+		)
+		if err := rows.Scan(&userScheduleId, &tagId); err != nil {
+			return err
+		}
+		if s, ok := schedulesById[userScheduleId]; ok {
+			s.tagIds = append(s.tagIds, tagId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// QueryPendingChatRooms queries the chat rooms of the erased users which are not anonymized yet
+func (r *realAccountQueryRepository) QueryPendingChatRooms(ctx context.Context) ([]*ErasedChatRoomDto, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ec.userErasureId, e.userId, ec.chatRoomId
+		FROM usererasurechatrooms AS ec
+		INNER JOIN usererasures AS e ON ec.userErasureId = e.id
+		WHERE ec.anonymizedAt IS NULL
+		ORDER BY ec.userErasureId, ec.chatRoomId
+		`)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	defer rows.Close()
+	var chatRooms []*ErasedChatRoomDto
+	for rows.Next() {
+		var c ErasedChatRoomDto
+		if err := rows.Scan(&c.userErasureId, &c.userId, &c.chatRoomId); err != nil {
+			return nil, stew.Wrap(err)
+		}
+		chatRooms = append(chatRooms, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, stew.Wrap(err)
+	}
+	return chatRooms, nil
+}
+
+// queryRows runs the query of the user and calls scan per row
+func queryRows(ctx context.Context, tx *sql.Tx, query string, userId string, scan func(rows *sql.Rows) error) error {
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return stew.Wrap(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return stew.Wrap(err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return stew.Wrap(err)
+	}
+	return nil
+}
+
+type UserErasureDto struct {
+	userId      string
+	requestedBy sql.NullString
+}
+
+// ErasedChatRoomDto is a chat room of a party of the erased user, where the user is to be anonymized
+type ErasedChatRoomDto struct {
+	userErasureId int64
+	userId        string
+	chatRoomId    string
+}
+
+type IAccountCommandRepository interface {
+	EraseUser(ctx context.Context, erasure *UserErasureDto) ([]*ErasedChatRoomDto, error)
+	MarkChatRoomAnonymized(ctx context.Context, chatRoom *ErasedChatRoomDto) error
+}
+
+var _ IAccountCommandRepository = (*realAccountCommandRepository)(nil)
+
+type realAccountCommandRepository struct {
+	db SqlDb
+}
+
+func ProvideAccountCommandRepository(db SqlDb) IAccountCommandRepository {
+	return &realAccountCommandRepository{
+		db: db,
+	}
+}
+
+// EraseUser deletes the user, whose rows in the other tables are deleted by the cascade foreign keys,
+// and records the erasure in usererasures table in a transaction.
+// The chat rooms of the parties of the user are recorded with the erasure and returned,
+// so that the user is anonymized in them after the commit.
+// It returns sql.ErrNoRows when the user isn't registered.
+func (r *realAccountCommandRepository) EraseUser(ctx context.Context, e *UserErasureDto) ([]*ErasedChatRoomDto, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+
+	chatRooms, err := eraseUser(ctx, tx, e)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return nil, stew.Wrap(err)
+		}
+		return nil, err
+	}
+
+	// A failed commit has already finished the transaction so that it needs no rollback
+	if err := tx.Commit(); err != nil {
+		return nil, stew.Wrap(err)
+	}
+	return chatRooms, nil
+}
+
+func eraseUser(ctx context.Context, tx *sql.Tx, e *UserErasureDto) ([]*ErasedChatRoomDto, error) {
+	var userId string
+	if err := tx.QueryRowContext(ctx, `
+		SELECT userId FROM users
+		WHERE userId = ? FOR UPDATE
+		`, e.userId).Scan(&userId); err != nil {
+		return nil, stew.Wrap(err)
+	}
+
+	// The chat rooms are taken before the parties of the user are deleted with the user
+	var chatRoomIds []string
+	err := queryRows(ctx, tx, `
+		SELECT DISTINCT p.chatRoomId
+		FROM partymembers AS pm
+		INNER JOIN parties AS p ON pm.partyId = p.id
+		WHERE pm.userId = ? AND p.chatRoomId IS NOT NULL AND p.chatRoomId <> ''
+		`, e.userId, func(rows *sql.Rows) error {
+		var chatRoomId string
+		if err := rows.Scan(&chatRoomId); err != nil {
+			return err
+		}
+		chatRoomIds = append(chatRoomIds, chatRoomId)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The audit trail keeps only the IDs
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO usererasures (userId, requestedBy)
+		VALUES (?, ?)
+		`, e.userId, e.requestedBy)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	userErasureId, err := res.LastInsertId()
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+
+	// The chat rooms are kept pending until the user is anonymized in them
+	chatRooms := make([]*ErasedChatRoomDto, 0, len(chatRoomIds))
+	for _, chatRoomId := range chatRoomIds {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO usererasurechatrooms (userErasureId, chatRoomId)
+			VALUES (?, ?)
+			`, userErasureId, chatRoomId); err != nil {
+			return nil, stew.Wrap(err)
+		}
+		chatRooms = append(chatRooms, &ErasedChatRoomDto{
+			userErasureId: userErasureId,
+			userId:        e.userId,
+			chatRoomId:    chatRoomId,
+		})
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM users
+		WHERE userId = ?
+		`, e.userId); err != nil {
+		return nil, stew.Wrap(err)
+	}
+	return chatRooms, nil
+}
+
+// MarkChatRoomAnonymized records that the erased user has been anonymized in the chat room
+func (r *realAccountCommandRepository) MarkChatRoomAnonymized(ctx context.Context, c *ErasedChatRoomDto) error {
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE usererasurechatrooms SET anonymizedAt = CURRENT_TIMESTAMP
+		WHERE userErasureId = ? AND chatRoomId = ?
+		`, c.userErasureId, c.chatRoomId); err != nil {
+		return stew.Wrap(err)
+	}
+	return nil
+}
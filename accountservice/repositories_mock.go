@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repositories.go
+
+// Package accountservice is a generated GoMock package.
+package accountservice
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockIAccountQueryRepository is a mock of IAccountQueryRepository interface
+type MockIAccountQueryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccountQueryRepositoryMockRecorder
+}
+
+// MockIAccountQueryRepositoryMockRecorder is the mock recorder for MockIAccountQueryRepository
+type MockIAccountQueryRepositoryMockRecorder struct {
+	mock *MockIAccountQueryRepository
+}
+
+// NewMockIAccountQueryRepository creates a new mock instance
+func NewMockIAccountQueryRepository(ctrl *gomock.Controller) *MockIAccountQueryRepository {
+	mock := &MockIAccountQueryRepository{ctrl: ctrl}
+	mock.recorder = &MockIAccountQueryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIAccountQueryRepository) EXPECT() *MockIAccountQueryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// QueryAccountArchive mocks base method
+func (m *MockIAccountQueryRepository) QueryAccountArchive(ctx context.Context, userId string) (*AccountArchiveDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryAccountArchive", ctx, userId)
+	ret0, _ := ret[0].(*AccountArchiveDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryAccountArchive indicates an expected call of QueryAccountArchive
+func (mr *MockIAccountQueryRepositoryMockRecorder) QueryAccountArchive(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAccountArchive", reflect.TypeOf((*MockIAccountQueryRepository)(nil).QueryAccountArchive), ctx, userId)
+}
+
+// QueryPendingChatRooms mocks base method
+func (m *MockIAccountQueryRepository) QueryPendingChatRooms(ctx context.Context) ([]*ErasedChatRoomDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPendingChatRooms", ctx)
+	ret0, _ := ret[0].([]*ErasedChatRoomDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryPendingChatRooms indicates an expected call of QueryPendingChatRooms
+func (mr *MockIAccountQueryRepositoryMockRecorder) QueryPendingChatRooms(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPendingChatRooms", reflect.TypeOf((*MockIAccountQueryRepository)(nil).QueryPendingChatRooms), ctx)
+}
+
+// MockIAccountCommandRepository is a mock of IAccountCommandRepository interface
+type MockIAccountCommandRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAccountCommandRepositoryMockRecorder
+}
+
+// MockIAccountCommandRepositoryMockRecorder is the mock recorder for MockIAccountCommandRepository
+type MockIAccountCommandRepositoryMockRecorder struct {
+	mock *MockIAccountCommandRepository
+}
+
+// NewMockIAccountCommandRepository creates a new mock instance
+func NewMockIAccountCommandRepository(ctrl *gomock.Controller) *MockIAccountCommandRepository {
+	mock := &MockIAccountCommandRepository{ctrl: ctrl}
+	mock.recorder = &MockIAccountCommandRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockIAccountCommandRepository) EXPECT() *MockIAccountCommandRepositoryMockRecorder {
+	return m.recorder
+}
+
+// EraseUser mocks base method
+func (m *MockIAccountCommandRepository) EraseUser(ctx context.Context, erasure *UserErasureDto) ([]*ErasedChatRoomDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseUser", ctx, erasure)
+	ret0, _ := ret[0].([]*ErasedChatRoomDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EraseUser indicates an expected call of EraseUser
+func (mr *MockIAccountCommandRepositoryMockRecorder) EraseUser(ctx, erasure interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseUser", reflect.TypeOf((*MockIAccountCommandRepository)(nil).EraseUser), ctx, erasure)
+}
+
+// MarkChatRoomAnonymized mocks base method
+func (m *MockIAccountCommandRepository) MarkChatRoomAnonymized(ctx context.Context, chatRoom *ErasedChatRoomDto) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkChatRoomAnonymized", ctx, chatRoom)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkChatRoomAnonymized indicates an expected call of MarkChatRoomAnonymized
+func (mr *MockIAccountCommandRepositoryMockRecorder) MarkChatRoomAnonymized(ctx, chatRoom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkChatRoomAnonymized", reflect.TypeOf((*MockIAccountCommandRepository)(nil).MarkChatRoomAnonymized), ctx, chatRoom)
+}
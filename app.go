@@ -0,0 +1,98 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/google/wire"
+
+	"github.com/momotaro98/mixlunch-service-api/accountservice"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+	"github.com/momotaro98/mixlunch-service-api/partyservice"
+	"github.com/momotaro98/mixlunch-service-api/tagservice"
+	usService "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
+	"github.com/momotaro98/mixlunch-service-api/userservice"
+)
+
+// application is the dependency container of the REST server.
+// All the handlers share the single DB pool.
+type application struct {
+	DB     *sql.DB
+	Logger logger.Logger
+	Levels *logger.Levels
+
+	UserScheduleHandler          *UserScheduleHandler
+	UpdateUserScheduleHandler    *UpdateUserScheduleHandler
+	DeleteUserScheduleHandler    *DeleteUserScheduleHandler
+	AddUserScheduleHandler       *AddUserScheduleHandler
+	PartyHandler                 *PartyHandler
+	AdminPartiesHandler          *AdminPartiesHandler
+	PartyReviewMemberHandler     *PartyReviewMemberHandler
+	PartyReviewMemberDoneHandler *PartyReviewMemberDoneHandler
+	TagsHandler                  *TagsHandler
+	UserHandler                  *UserHandler
+	UserPublicHandler            *UserPublicHandler
+	UserRegisterHandler          *UserRegisterHandler
+	UserUpdateHandler            *UserUpdateHandler
+	UserPatchHandler             *UserPatchHandler
+	UserBlockRegisterHandler     *UserBlockRegisterHandler
+	UserBlockDeleteHandler       *UserBlockDeleteHandler
+	UserBlocksHandler            *UserBlocksHandler
+	AccountExportHandler         *AccountExportHandler
+	AccountDeleteHandler         *AccountDeleteHandler
+	ErrorsHandler                *ErrorsHandler
+
+	// AccountServer anonymizes the chat rooms left pending by the erasures in background
+	AccountServer accountservice.AccountServer
+}
+
+var serviceSet = wire.NewSet(
+	// Tag service
+	tagservice.ProvideDB,
+	tagservice.ProvideTagQueryRepository,
+	tagservice.ProvideTagServer,
+	// User Schedule service, which uses Tag service
+	usService.ProvideDB,
+	usService.ProvideUserScheduleRepository,
+	usService.ProvideRealUserScheduleUpdateRepository,
+	usService.ProvideUserScheduleServer,
+	// User service, which uses Tag service
+	userservice.ProvideDB,
+	userservice.ProvideUserQueryRepository,
+	userservice.ProvideUserCommandRepository,
+	userservice.ProvideUserServer,
+	// Party service, which uses User service
+	partyservice.ProvideDB,
+	partyservice.ProvideApp,
+	partyservice.ProvidePartyQueryRepository,
+	partyservice.ProvidePartyCommandRepository,
+	partyservice.ProvideChatRoomRepository,
+	partyservice.ProvidePartyServer,
+	// Account service, which uses Party service
+	accountservice.ProvideDB,
+	accountservice.ProvideAccountQueryRepository,
+	accountservice.ProvideAccountCommandRepository,
+	accountservice.ProvideAccountServer,
+)
+
+var handlerSet = wire.NewSet(
+	provideUserScheduleHandler,
+	provideUpdateUserScheduleHandler,
+	provideDeleteUserScheduleHandler,
+	provideAddUserScheduleHandler,
+	providePartyHandler,
+	provideAdminPartiesHandler,
+	providePartyReviewMemberHandler,
+	providePartyReviewMemberDoneHandler,
+	provideTagsHandler,
+	provideUserHandler,
+	provideUserPublicHandler,
+	provideUserRegisterHandler,
+	provideUserUpdateHandler,
+	provideUserPatchHandler,
+	provideUserBlockRegisterHandler,
+	provideUserBlockDeleteHandler,
+	provideUserBlocksHandler,
+	provideAccountExportHandler,
+	provideAccountDeleteHandler,
+	provideErrorsHandler,
+)
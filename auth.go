@@ -2,17 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
-	firebase "firebase.google.com/go"
-	"google.golang.org/api/option"
-
+	"github.com/momotaro98/mixlunch-service-api/auth"
+	"github.com/momotaro98/mixlunch-service-api/config"
 	"github.com/momotaro98/mixlunch-service-api/logger"
 )
 
-func AuthMiddle(activate bool, loggerConfig *logger.Config, appCredentialFilePath string) MFunc {
+// keysFetchTimeout bounds fetching the signing keys of Firebase
+const keysFetchTimeout = 10 * time.Second
+
+// firebaseProjectID returns the configured project ID or the one of the credential file
+func firebaseProjectID(cfg *config.Config) (string, error) {
+	if cfg.Firebase.ProjectID != "" {
+		return cfg.Firebase.ProjectID, nil
+	}
+	return auth.ProjectIDFromCredentialFile(cfg.Firebase.CredentialFile)
+}
+
+// newTokenVerifier returns the verifier of Firebase tokens which keeps the signing keys fresh in background
+func newTokenVerifier(projectID string) auth.TokenVerifier {
+	verifier := auth.NewFirebaseVerifier(projectID, &http.Client{Timeout: keysFetchTimeout})
+	// The keys are refreshed as long as the process lives
+	verifier.Run(context.Background())
+	return verifier
+}
+
+func AuthMiddle(activate bool, l logger.Logger, verifier auth.TokenVerifier, revocation *auth.RevocationChecker) MFunc {
 	if !activate {
 		// Do nothing and just pass to next http handler
 		return func(next http.Handler) http.Handler {
@@ -22,51 +42,68 @@ func AuthMiddle(activate bool, loggerConfig *logger.Config, appCredentialFilePat
 		}
 	}
 
-	opt := option.WithCredentialsFile(appCredentialFilePath)
-	app, err := firebase.NewApp(context.Background(), nil, opt)
-	if err != nil {
-		panic(err)
-	}
-
 	return func(next http.Handler) http.Handler {
 		return &authHandler{
-			next:   next,
-			logger: logger.ProvideLogger(loggerConfig),
-			app:    app,
+			next:       next,
+			logger:     l,
+			verifier:   verifier,
+			revocation: revocation,
 		}
 	}
 }
 
 type authHandler struct {
-	next   http.Handler
-	logger logger.Logger
-	app    *firebase.App
+	next       http.Handler
+	logger     logger.Logger
+	verifier   auth.TokenVerifier
+	revocation *auth.RevocationChecker
+}
+
+// tokenOf returns the bearer token of the Authorization header, or the session cookie without the header
+func tokenOf(r *http.Request) (string, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		authorization := strings.SplitN(header, " ", 2)
+		if len(authorization) != 2 || authorization[0] != "Bearer" {
+			return "", false
+		}
+		return authorization[1], true
+	}
+	if cookie, err := r.Cookie(sessionCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value, true
+	}
+	return "", false
 }
 
 func (h *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	reqId := r.Header.Get(XRequestId)
 
-	client, err := h.app.Auth(ctx)
-	if err != nil {
-		h.logger.Log(logger.Error, reqId, fmt.Sprintf("Firebase app.Auth error. Error: %+v\n", err.Error()))
-		http.Error(w, "Internal error", http.StatusInternalServerError)
+	token, ok := tokenOf(r)
+	if !ok {
+		h.logger.LogContext(ctx, logger.Warn, fmt.Sprintf("Request header Authorization is nothing or invalid.\n"))
+		http.Error(w, "Authorization header is not valid.", http.StatusBadRequest)
 		return
 	}
 
-	auth := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
-	if len(auth) != 2 || auth[0] != "Bearer" {
-		h.logger.Log(logger.Warn, reqId, fmt.Sprintf("Request header Authorization is nothing or invalid.\n"))
-		http.Error(w, "Authorization header is not valid.", http.StatusBadRequest)
+	// The token, either an ID token or a session cookie, is verified locally with the cached keys of Firebase
+	verified, err := h.verifier.Verify(ctx, token)
+	if errors.Is(err, auth.ErrKeysUnavailable) {
+		h.logger.WithError(err).LogContext(ctx, logger.Error, "error verifying token")
+		http.Error(w, "Authentication is unavailable.", http.StatusServiceUnavailable)
 		return
 	}
-	token := auth[1]
-
-	if _, err := client.VerifySessionCookie(ctx, token); err != nil { // VerifySessionCookie is too slow because it connects to Firebase online
-		h.logger.Log(logger.Warn, reqId, fmt.Sprintf("error verifying token: %+v\n", err.Error()))
+	if err != nil {
+		h.logger.WithError(err).LogContext(ctx, logger.Warn, "error verifying token")
 		http.Error(w, "Token is not valid.", http.StatusUnauthorized)
 		return
 	}
+	// The revocation needs Firebase, which is asked once in the cache TTL by user
+	if revoked, err := h.revocation.Revoked(ctx, verified); err != nil || revoked {
+		rejectRevoked(w, r, h.logger, verified, err)
+		return
+	}
+	setAccessLogUid(ctx, verified.UID)
+	ctx = logger.WithUID(ctx, verified.UID)
 
-	h.next.ServeHTTP(w, r)
+	// The claims are kept for the authorization in the handlers
+	h.next.ServeHTTP(w, r.WithContext(auth.WithToken(ctx, verified)))
 }
@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type tokenKey struct{}
+
+// WithToken returns the context holding the verified token of the caller
+func WithToken(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFrom returns the verified token of the caller, which is false if the request is not authenticated
+func TokenFrom(ctx context.Context) (*Token, bool) {
+	token, ok := ctx.Value(tokenKey{}).(*Token)
+	return token, ok
+}
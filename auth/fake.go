@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const fakeKeyID = "fake"
+
+// Fake is a Verifier with a locally generated key, which also issues the tokens
+// and manages the sessions, to test the authentication without Firebase.
+type Fake struct {
+	*Verifier
+	key  *rsa.PrivateKey
+	keys StaticKeySource
+
+	mu         sync.Mutex
+	validAfter map[string]time.Time
+}
+
+var _ SessionManager = (*Fake)(nil)
+
+func NewFake(projectID string) *Fake {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	keys := StaticKeySource{fakeKeyID: &key.PublicKey}
+	return &Fake{
+		Verifier:   NewVerifier(projectID, keys, keys),
+		key:        key,
+		keys:       keys,
+		validAfter: make(map[string]time.Time),
+	}
+}
+
+// IDToken issues the ID token of the user valid for an hour
+func (f *Fake) IDToken(uid string, claims map[string]interface{}) string {
+	return f.Sign(idTokenIssuerPrefix+f.projectID, uid, claims, time.Hour)
+}
+
+// SessionCookie issues the session cookie of the user valid for an hour
+func (f *Fake) SessionCookie(uid string, claims map[string]interface{}) string {
+	return f.Sign(sessionCookieIssuerPrefix+f.projectID, uid, claims, time.Hour)
+}
+
+// CreateSessionCookie issues the session cookie with the claims of the ID token
+func (f *Fake) CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error) {
+	t, err := f.VerifyIDToken(ctx, idToken)
+	if err != nil {
+		return "", err
+	}
+	return f.Sign(sessionCookieIssuerPrefix+f.projectID, t.UID, t.Claims, expiresIn), nil
+}
+
+// RevokeRefreshTokens revokes the tokens of the user issued before the current second as Firebase does
+func (f *Fake) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.validAfter[uid] = f.now().Truncate(time.Second)
+	return nil
+}
+
+func (f *Fake) TokensValidAfter(ctx context.Context, uid string) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.validAfter[uid], nil
+}
+
+// Keys returns the key source verifying the tokens of the Fake
+func (f *Fake) Keys() KeySource {
+	return f.keys
+}
+
+// ServiceAccountJWT issues the JWT of the service account for the audience valid for an hour.
+// Give Keys as the keys of the service account to ServiceAccountVerifier.
+func (f *Fake) ServiceAccountJWT(email, audience string) string {
+	now := f.now()
+	return f.sign(map[string]interface{}{
+		"iss": email,
+		"sub": email,
+		"aud": audience,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	})
+}
+
+// Sign issues the token with the issuer, valid for the duration from now.
+// A negative duration makes the expired token.
+func (f *Fake) Sign(issuer, uid string, claims map[string]interface{}, valid time.Duration) string {
+	now := f.now()
+	payload := map[string]interface{}{
+		"iss":       issuer,
+		"aud":       f.projectID,
+		"sub":       uid,
+		"iat":       now.Unix(),
+		"exp":       now.Add(valid).Unix(),
+		"auth_time": now.Unix(),
+	}
+	for k, v := range claims {
+		payload[k] = v
+	}
+	return f.sign(payload)
+}
+
+func (f *Fake) sign(payload map[string]interface{}) string {
+	content := encodeSegment(header{Algorithm: "RS256", KeyID: fakeKeyID}) + "." + encodeSegment(payload)
+	hashed := sha256.Sum256([]byte(content))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, hashed[:])
+	if err != nil {
+		panic(err)
+	}
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// IDTokenKeysURL serves the certificates of the keys signing ID tokens
+	IDTokenKeysURL = "https://www.googleapis.com/robot/v1/metadata/x509/securetoken@system.gserviceaccount.com"
+	// SessionCookieKeysURL serves the certificates of the keys signing session cookies
+	SessionCookieKeysURL = "https://www.googleapis.com/identitytoolkit/v3/relyingparty/publicKeys"
+)
+
+// KeySource provides the public keys by key ID
+type KeySource interface {
+	Keys(ctx context.Context) (map[string]*rsa.PublicKey, error)
+}
+
+// StaticKeySource is the keys which never change, e.g. the keys of Fake
+type StaticKeySource map[string]*rsa.PublicKey
+
+func (s StaticKeySource) Keys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	return s, nil
+}
+
+const (
+	// defaultMaxAge is used when the response has no max-age
+	defaultMaxAge = time.Hour
+	// retryInterval is the interval of Run after a failed refresh
+	retryInterval = 30 * time.Second
+)
+
+// HTTPKeySource fetches the certificates in PEM by key ID and caches the keys
+// for max-age of the Cache-Control of the response.
+// Run refreshes the cached keys in the background, and the expired ones are kept used until it succeeds.
+type HTTPKeySource struct {
+	url    string
+	client *http.Client
+	now    func() time.Time
+
+	// mu guards keys and expires, which is never held while fetching
+	mu      sync.RWMutex
+	keys    map[string]*rsa.PublicKey
+	expires time.Time
+	// fetching serializes the fetches so that the first keys are fetched once
+	fetching sync.Mutex
+}
+
+func NewHTTPKeySource(url string, client *http.Client) *HTTPKeySource {
+	return &HTTPKeySource{
+		url:    url,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Keys returns the cached keys even if they are expired, so that no request waits for a failing refresh
+// while Run retries it. It fetches the keys only when none has been fetched yet.
+func (s *HTTPKeySource) Keys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	if keys := s.cached(); keys != nil {
+		return keys, nil
+	}
+
+	s.fetching.Lock()
+	defer s.fetching.Unlock()
+	// The keys may have been fetched while waiting for the other fetch
+	if keys := s.cached(); keys != nil {
+		return keys, nil
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.cached(), nil
+}
+
+// cached returns the cached keys, or nil if none has been fetched
+func (s *HTTPKeySource) cached() map[string]*rsa.PublicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys
+}
+
+// Run refreshes the keys before they expire until ctx is done,
+// so that no request waits for fetching the keys.
+func (s *HTTPKeySource) Run(ctx context.Context) {
+	for {
+		s.fetching.Lock()
+		err := s.refresh(ctx)
+		s.fetching.Unlock()
+		s.mu.RLock()
+		wait := s.expires.Sub(s.now())
+		s.mu.RUnlock()
+		if err != nil || wait <= 0 {
+			wait = retryInterval
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// refresh fetches the keys and swaps them with the cached ones.
+// It must be called with s.fetching locked, and locks s.mu only to swap.
+func (s *HTTPKeySource) refresh(ctx context.Context) error {
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth: %s responded %d", s.url, res.StatusCode)
+	}
+	keys, err := parseCertificates(b)
+	if err != nil {
+		return fmt.Errorf("auth: %s: %v", s.url, err)
+	}
+	expires := s.now().Add(maxAge(res.Header.Get("Cache-Control")))
+
+	s.mu.Lock()
+	s.keys = keys
+	s.expires = expires
+	s.mu.Unlock()
+	return nil
+}
+
+func parseCertificates(b []byte) (map[string]*rsa.PublicKey, error) {
+	var certs map[string]string
+	if err := json.Unmarshal(b, &certs); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey, len(certs))
+	for kid, cert := range certs {
+		block, _ := pem.Decode([]byte(cert))
+		if block == nil {
+			return nil, fmt.Errorf("the certificate of %s is not PEM", kid)
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("the certificate of %s: %v", kid, err)
+		}
+		key, ok := c.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("the certificate of %s is not RSA", kid)
+		}
+		keys[kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no certificates")
+	}
+	return keys, nil
+}
+
+// maxAge returns max-age of the Cache-Control header or defaultMaxAge if none
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil || seconds < 0 {
+			break
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultMaxAge
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func genCertificate(t *testing.T) (string, *rsa.PublicKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), &key.PublicKey
+}
+
+func TestHTTPKeySource_CachesUntilRefreshed(t *testing.T) {
+	// Arrange
+	cert, key := genCertificate(t)
+	var fetched int32
+	var fail int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetched, 1)
+		if atomic.LoadInt32(&fail) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=100, must-revalidate, no-transform")
+		json.NewEncoder(w).Encode(map[string]string{"kid1": cert})
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	s := NewHTTPKeySource(srv.URL, srv.Client())
+	s.now = func() time.Time { return now }
+
+	keys, err := s.Keys(context.Background())
+	if err != nil {
+		t.Fatalf("Test failed. Expected: no error, Actual: %v", err)
+	}
+	if keys["kid1"].N.Cmp(key.N) != 0 {
+		t.Errorf("Test failed. The key of kid1 is not the one of the certificate")
+	}
+
+	t.Run("Cached within max-age", func(t *testing.T) {
+		now = now.Add(99 * time.Second)
+		s.Keys(context.Background())
+		if n := atomic.LoadInt32(&fetched); n != 1 {
+			t.Errorf("Test failed. Expected: 1 fetch, Actual: %d", n)
+		}
+	})
+	t.Run("Stale keys are returned without fetching", func(t *testing.T) {
+		now = now.Add(2 * time.Second)
+		keys, err := s.Keys(context.Background())
+		if err != nil || keys["kid1"] == nil {
+			t.Errorf("Test failed. Expected: the stale keys, Actual: %v, %v", keys, err)
+		}
+		if n := atomic.LoadInt32(&fetched); n != 1 {
+			t.Errorf("Test failed. Expected: 1 fetch, Actual: %d", n)
+		}
+	})
+	t.Run("Stale keys are used while refreshing fails", func(t *testing.T) {
+		atomic.StoreInt32(&fail, 1)
+		// Refresh as Run does
+		s.fetching.Lock()
+		err := s.refresh(context.Background())
+		s.fetching.Unlock()
+		if err == nil {
+			t.Fatalf("Test failed. Expected: error, Actual: no error")
+		}
+		keys, err := s.Keys(context.Background())
+		if err != nil || keys["kid1"] == nil {
+			t.Errorf("Test failed. Expected: the stale keys, Actual: %v, %v", keys, err)
+		}
+		if n := atomic.LoadInt32(&fetched); n != 2 {
+			t.Errorf("Test failed. Expected: 2 fetches, Actual: %d", n)
+		}
+	})
+}
+
+func TestHTTPKeySource_FreshKeysWhileRefreshing(t *testing.T) {
+	// Arrange
+	cert, _ := genCertificate(t)
+	var fetched int32
+	refreshing := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&fetched, 1) > 1 {
+			// The refresh by Run hangs until the end of the test
+			close(refreshing)
+			<-release
+		}
+		json.NewEncoder(w).Encode(map[string]string{"kid1": cert})
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	s := NewHTTPKeySource(srv.URL, srv.Client())
+	if _, err := s.Keys(context.Background()); err != nil {
+		t.Fatalf("Test failed. Expected: no error, Actual: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	<-refreshing
+
+	// Act
+	done := make(chan struct{})
+	go func() {
+		s.Keys(context.Background())
+		close(done)
+	}()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Test failed. Keys waited for the refresh in progress")
+	}
+}
+
+func TestHTTPKeySource_Unavailable_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if _, err := NewHTTPKeySource(srv.URL, srv.Client()).Keys(context.Background()); err == nil {
+		t.Errorf("Test failed. Expected: error, Actual: no error")
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	testCases := []struct {
+		cacheControl string
+		expected     time.Duration
+	}{
+		{"public, max-age=19204, must-revalidate, no-transform", 19204 * time.Second},
+		{"max-age=0", 0},
+		{"", defaultMaxAge},
+		{"max-age=abc", defaultMaxAge},
+	}
+	for _, tc := range testCases {
+		if actual := maxAge(tc.cacheControl); actual != tc.expected {
+			t.Errorf("Test failed. %q Expected: %v, Actual: %v", tc.cacheControl, tc.expected, actual)
+		}
+	}
+}
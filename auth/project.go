@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// ProjectIDFromCredentialFile reads the project ID of the Firebase service account key file
+func ProjectIDFromCredentialFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("auth: %v", err)
+	}
+	var cred struct {
+		ProjectID string `json:"project_id"`
+	}
+	if err := json.Unmarshal(b, &cred); err != nil {
+		return "", fmt.Errorf("auth: %s: %v", path, err)
+	}
+	if cred.ProjectID == "" {
+		return "", errors.New("auth: " + path + " has no project_id")
+	}
+	return cred.ProjectID, nil
+}
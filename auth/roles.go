@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Role is the role of a user. A higher role includes the permissions of the lower ones.
+type Role string
+
+const (
+	RoleUser     Role = "user"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleUser:     1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Includes reports whether the role has the permissions of the required role
+func (r Role) Includes(required Role) bool {
+	level, ok := roleLevels[r]
+	return ok && level >= roleLevels[required]
+}
+
+func higher(a, b Role) Role {
+	if roleLevels[b] > roleLevels[a] {
+		return b
+	}
+	return a
+}
+
+// RoleClaim is the Firebase custom claim of the role, set by the Admin SDK like {"role": "admin"}
+const RoleClaim = "role"
+
+// RoleStore gives the roles granted locally. It returns empty if the user has no role.
+type RoleStore interface {
+	Role(ctx context.Context, uid string) (Role, error)
+}
+
+// SQLRoleStore reads the roles in the userroles table
+type SQLRoleStore struct {
+	db *sql.DB
+}
+
+func NewSQLRoleStore(db *sql.DB) *SQLRoleStore {
+	return &SQLRoleStore{db: db}
+}
+
+func (s *SQLRoleStore) Role(ctx context.Context, uid string) (Role, error) {
+	var role string
+	err := s.db.QueryRowContext(ctx, "SELECT role FROM userroles WHERE userId = ?", uid).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return Role(role), nil
+}
+
+// RoleResolver resolves the role of the caller as the higher of the custom claim and the RoleStore.
+// The caller without any role is RoleUser.
+type RoleResolver struct {
+	store RoleStore
+}
+
+func NewRoleResolver(store RoleStore) *RoleResolver {
+	return &RoleResolver{store: store}
+}
+
+func (r *RoleResolver) Resolve(ctx context.Context, token *Token) (Role, error) {
+	role := RoleUser
+	if claimed, ok := token.Claims[RoleClaim].(string); ok {
+		role = higher(role, Role(claimed))
+	}
+	if r.store != nil {
+		stored, err := r.store.Role(ctx, token.UID)
+		if err != nil {
+			return "", err
+		}
+		role = higher(role, stored)
+	}
+	return role, nil
+}
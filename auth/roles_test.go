@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type mapRoleStore map[string]Role
+
+func (s mapRoleStore) Role(ctx context.Context, uid string) (Role, error) {
+	if uid == "broken" {
+		return "", errors.New("db is down")
+	}
+	return s[uid], nil
+}
+
+func TestRole_Includes(t *testing.T) {
+	testCases := []struct {
+		role     Role
+		required Role
+		expected bool
+	}{
+		{RoleAdmin, RoleOperator, true},
+		{RoleAdmin, RoleAdmin, true},
+		{RoleOperator, RoleUser, true},
+		{RoleOperator, RoleAdmin, false},
+		{RoleUser, RoleOperator, false},
+		{Role("root"), RoleUser, false},
+	}
+	for _, tc := range testCases {
+		if actual := tc.role.Includes(tc.required); actual != tc.expected {
+			t.Errorf("Test failed. %s includes %s, Expected: %v, Actual: %v", tc.role, tc.required, tc.expected, actual)
+		}
+	}
+}
+
+func TestRoleResolver_Resolve(t *testing.T) {
+	resolver := NewRoleResolver(mapRoleStore{"op": RoleOperator, "admin": RoleAdmin})
+
+	testCases := []struct {
+		name     string
+		token    *Token
+		expected Role
+	}{
+		{"No role", &Token{UID: "u"}, RoleUser},
+		{"Claim", &Token{UID: "u", Claims: map[string]interface{}{RoleClaim: "admin"}}, RoleAdmin},
+		{"Unknown claim", &Token{UID: "u", Claims: map[string]interface{}{RoleClaim: "root"}}, RoleUser},
+		{"Store", &Token{UID: "op"}, RoleOperator},
+		{"Higher of claim and store", &Token{UID: "op", Claims: map[string]interface{}{RoleClaim: "admin"}}, RoleAdmin},
+		{"Store is higher than claim", &Token{UID: "admin", Claims: map[string]interface{}{RoleClaim: "operator"}}, RoleAdmin},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolver.Resolve(context.Background(), tc.token)
+			if err != nil {
+				t.Fatalf("Test failed. Expected: no error, Actual: %v", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("Test failed. Expected: %s, Actual: %s", tc.expected, actual)
+			}
+		})
+	}
+
+	if _, err := resolver.Resolve(context.Background(), &Token{UID: "broken"}); err == nil {
+		t.Errorf("Test failed. Expected: error of the store, Actual: no error")
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// serviceAccountKeysURL serves the certificates of the keys of a Google service account
+const serviceAccountKeysURL = "https://www.googleapis.com/robot/v1/metadata/x509/"
+
+// maxServiceAccountJWTLifetime bounds exp - iat as Google APIs do
+const maxServiceAccountJWTLifetime = time.Hour
+
+// ServiceAccountKeys returns the key source of the Google service account
+func ServiceAccountKeys(email string, client *http.Client) *HTTPKeySource {
+	return NewHTTPKeySource(serviceAccountKeysURL+url.PathEscape(email), client)
+}
+
+// ServiceAccountVerifier verifies the JWTs self-signed by the Google service accounts of the services calling us.
+// The JWT has the service account email in iss and sub, and the audience in aud.
+type ServiceAccountVerifier struct {
+	audience string
+	accounts map[string]KeySource
+	now      func() time.Time
+}
+
+// NewServiceAccountVerifier returns the verifier accepting the service accounts of the keys by email
+func NewServiceAccountVerifier(audience string, accounts map[string]KeySource) *ServiceAccountVerifier {
+	return &ServiceAccountVerifier{
+		audience: audience,
+		accounts: accounts,
+		now:      time.Now,
+	}
+}
+
+// Run refreshes the keys of the HTTPKeySources until ctx is done
+func (v *ServiceAccountVerifier) Run(ctx context.Context) {
+	for _, keys := range v.accounts {
+		if s, ok := keys.(*HTTPKeySource); ok {
+			go s.Run(ctx)
+		}
+	}
+}
+
+// Verify returns the email of the service account signing the JWT
+func (v *ServiceAccountVerifier) Verify(ctx context.Context, token string) (string, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return "", errors.New("auth: the token is not a JWT")
+	}
+	var h header
+	if err := decodeSegment(segments[0], &h); err != nil {
+		return "", fmt.Errorf("auth: the header: %v", err)
+	}
+	var t Token
+	if err := decodeSegment(segments[1], &t); err != nil {
+		return "", fmt.Errorf("auth: the payload: %v", err)
+	}
+
+	keys, ok := v.accounts[t.Issuer]
+	if !ok {
+		return "", fmt.Errorf("auth: unknown service account %q", t.Issuer)
+	}
+	if t.Subject != t.Issuer {
+		return "", errors.New("auth: the subject is not the issuer")
+	}
+	if h.Algorithm != "RS256" {
+		return "", fmt.Errorf("auth: invalid algorithm %q", h.Algorithm)
+	}
+	if t.Audience != v.audience {
+		return "", fmt.Errorf("auth: invalid audience %q", t.Audience)
+	}
+	now := v.now()
+	if time.Unix(t.IssuedAt, 0).After(now.Add(clockSkew)) {
+		return "", fmt.Errorf("auth: issued in the future at %d", t.IssuedAt)
+	}
+	if time.Unix(t.Expires, 0).Before(now.Add(-clockSkew)) {
+		return "", fmt.Errorf("auth: expired at %d", t.Expires)
+	}
+	if time.Duration(t.Expires-t.IssuedAt)*time.Second > maxServiceAccountJWTLifetime {
+		return "", errors.New("auth: the lifetime is too long")
+	}
+
+	ks, err := keys.Keys(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+	}
+	key, ok := ks[h.KeyID]
+	if !ok {
+		return "", fmt.Errorf("auth: unknown key ID %q", h.KeyID)
+	}
+	if err := verifySignature(segments, key); err != nil {
+		return "", errors.New("auth: invalid signature")
+	}
+	return t.Issuer, nil
+}
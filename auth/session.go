@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	firebase "firebase.google.com/go"
+	fbauth "firebase.google.com/go/auth"
+	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
+)
+
+const (
+	// MinSessionLifetime and MaxSessionLifetime are the bounds of session cookies Firebase allows
+	MinSessionLifetime = 5 * time.Minute
+	MaxSessionLifetime = 14 * 24 * time.Hour
+
+	// createSessionCookieURL is the Identity Toolkit API minting session cookies,
+	// which Firebase Admin SDK v3 doesn't wrap.
+	createSessionCookieURL = "https://identitytoolkit.googleapis.com/v1/projects/%s:createSessionCookie"
+)
+
+// SessionManager creates and revokes the sessions of the users
+type SessionManager interface {
+	// CreateSessionCookie exchanges the ID token for a session cookie valid for expiresIn
+	CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error)
+	// RevokeRefreshTokens revokes the refresh tokens and the session cookies of the user issued until now
+	RevokeRefreshTokens(ctx context.Context, uid string) error
+	// TokensValidAfter returns the time before which the tokens of the user are revoked
+	TokensValidAfter(ctx context.Context, uid string) (time.Time, error)
+}
+
+// FirebaseSessions is the SessionManager of Firebase Auth
+type FirebaseSessions struct {
+	projectID string
+	client    *fbauth.Client
+	// http is authorized as the service account
+	http *http.Client
+}
+
+var _ SessionManager = (*FirebaseSessions)(nil)
+
+func NewFirebaseSessions(ctx context.Context, projectID, credentialFile string) (*FirebaseSessions, error) {
+	opt := option.WithCredentialsFile(credentialFile)
+	app, err := firebase.NewApp(ctx, &firebase.Config{ProjectID: projectID}, opt)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %v", err)
+	}
+	client, err := app.Auth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %v", err)
+	}
+	hc, _, err := transport.NewHTTPClient(ctx, opt, option.WithScopes(
+		"https://www.googleapis.com/auth/cloud-platform",
+		"https://www.googleapis.com/auth/identitytoolkit",
+	))
+	if err != nil {
+		return nil, fmt.Errorf("auth: %v", err)
+	}
+	return &FirebaseSessions{
+		projectID: projectID,
+		client:    client,
+		http:      hc,
+	}, nil
+}
+
+func (s *FirebaseSessions) CreateSessionCookie(ctx context.Context, idToken string, expiresIn time.Duration) (string, error) {
+	if expiresIn < MinSessionLifetime || expiresIn > MaxSessionLifetime {
+		return "", fmt.Errorf("auth: session lifetime %s is out of %s to %s", expiresIn, MinSessionLifetime, MaxSessionLifetime)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"idToken":       idToken,
+		"validDuration": int64(expiresIn / time.Second),
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(createSessionCookieURL, s.projectID), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("auth: creating session cookie: %v", err)
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("auth: creating session cookie: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("auth: creating session cookie: responded %d: %s", res.StatusCode, b)
+	}
+	var created struct {
+		SessionCookie string `json:"sessionCookie"`
+	}
+	if err := json.Unmarshal(b, &created); err != nil {
+		return "", fmt.Errorf("auth: creating session cookie: %v", err)
+	}
+	return created.SessionCookie, nil
+}
+
+func (s *FirebaseSessions) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	if err := s.client.RevokeRefreshTokens(ctx, uid); err != nil {
+		return fmt.Errorf("auth: revoking tokens of %s: %v", uid, err)
+	}
+	return nil
+}
+
+func (s *FirebaseSessions) TokensValidAfter(ctx context.Context, uid string) (time.Time, error) {
+	user, err := s.client.GetUser(ctx, uid)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("auth: getting user %s: %v", uid, err)
+	}
+	return time.Unix(0, user.TokensValidAfterMillis*int64(time.Millisecond)), nil
+}
+
+// maxCachedUsers bounds the cache, which drops the least recently used user beyond it
+const maxCachedUsers = 10000
+
+// RevocationChecker tells the tokens issued before their sessions were revoked.
+// It caches the time of the revocation by user for the TTL,
+// so a revocation by another process takes up to the TTL to be effective.
+type RevocationChecker struct {
+	sessions SessionManager
+	ttl      time.Duration
+	now      func() time.Time
+	maxUsers int
+
+	mu         sync.Mutex
+	validAfter map[string]*list.Element // of *cachedTime in lru
+	lru        *list.List               // the most recently used is the front
+}
+
+type cachedTime struct {
+	uid     string
+	at      time.Time
+	expires time.Time
+}
+
+func NewRevocationChecker(sessions SessionManager, ttl time.Duration) *RevocationChecker {
+	return &RevocationChecker{
+		sessions:   sessions,
+		ttl:        ttl,
+		now:        time.Now,
+		maxUsers:   maxCachedUsers,
+		validAfter: make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Revoked returns whether the token was issued before the sessions of the user were revoked
+func (c *RevocationChecker) Revoked(ctx context.Context, t *Token) (bool, error) {
+	validAfter, err := c.tokensValidAfter(ctx, t.UID)
+	if err != nil {
+		return false, err
+	}
+	if validAfter.IsZero() {
+		return false, nil
+	}
+	// iat is in seconds, which is compared in milliseconds as Firebase Admin SDK does
+	return t.IssuedAt*1000 < validAfter.UnixNano()/int64(time.Millisecond), nil
+}
+
+// Revoke revokes the sessions of the user and drops the cache of the user
+// so that this process rejects them at once.
+func (c *RevocationChecker) Revoke(ctx context.Context, uid string) error {
+	if err := c.sessions.RevokeRefreshTokens(ctx, uid); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if e, ok := c.validAfter[uid]; ok {
+		c.lru.Remove(e)
+		delete(c.validAfter, uid)
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *RevocationChecker) tokensValidAfter(ctx context.Context, uid string) (time.Time, error) {
+	now := c.now()
+	if at, ok := c.cached(uid, now); ok {
+		return at, nil
+	}
+
+	// Not locked while calling Firebase not to block the other users
+	at, err := c.sessions.TokensValidAfter(ctx, uid)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if c.ttl <= 0 {
+		return at, nil
+	}
+	c.store(&cachedTime{uid: uid, at: at, expires: now.Add(c.ttl)})
+	return at, nil
+}
+
+// cached returns the time of the user cached within the TTL, marking the user used
+func (c *RevocationChecker) cached(uid string, now time.Time) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.validAfter[uid]
+	if !ok {
+		return time.Time{}, false
+	}
+	cached := e.Value.(*cachedTime)
+	if !now.Before(cached.expires) {
+		return time.Time{}, false
+	}
+	c.lru.MoveToFront(e)
+	return cached.at, true
+}
+
+// store caches the time of the user, dropping the least recently used users beyond maxUsers
+func (c *RevocationChecker) store(cached *cachedTime) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.validAfter[cached.uid]; ok {
+		e.Value = cached
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.validAfter[cached.uid] = c.lru.PushFront(cached)
+	for c.lru.Len() > c.maxUsers {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.validAfter, oldest.Value.(*cachedTime).uid)
+	}
+}
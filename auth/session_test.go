@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFake_CreateSessionCookie(t *testing.T) {
+	fake := NewFake(projectID)
+	ctx := context.Background()
+
+	cookie, err := fake.CreateSessionCookie(ctx, fake.IDToken("uid1", map[string]interface{}{"role": "admin"}), time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	token, err := fake.Verify(ctx, cookie)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if token.Issuer != sessionCookieIssuerPrefix+projectID || token.UID != "uid1" || token.Claims["role"] != "admin" {
+		t.Errorf("unexpected session cookie %+v", token)
+	}
+
+	// A session cookie can't make another one
+	if _, err := fake.CreateSessionCookie(ctx, cookie, time.Hour); err == nil {
+		t.Error("expected error for the session cookie, but nil")
+	}
+	if _, err := fake.VerifyIDToken(ctx, cookie); err == nil {
+		t.Error("VerifyIDToken: expected error for the session cookie, but nil")
+	}
+}
+
+type countingSessions struct {
+	SessionManager
+	calls int
+	err   error
+}
+
+func (s *countingSessions) TokensValidAfter(ctx context.Context, uid string) (time.Time, error) {
+	s.calls++
+	if s.err != nil {
+		return time.Time{}, s.err
+	}
+	return s.SessionManager.TokensValidAfter(ctx, uid)
+}
+
+func TestRevocationChecker_Revoked(t *testing.T) {
+	fake := NewFake(projectID)
+	ctx := context.Background()
+	now := time.Unix(1580000000, 0)
+	fake.now = func() time.Time { return now }
+
+	sessions := &countingSessions{SessionManager: fake}
+	checker := NewRevocationChecker(sessions, time.Minute)
+	checker.now = func() time.Time { return now }
+
+	issued, err := fake.Verify(ctx, fake.SessionCookie("uid1", nil))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if revoked, err := checker.Revoked(ctx, issued); err != nil || revoked {
+		t.Fatalf("expected not revoked, actual %v, %v", revoked, err)
+	}
+
+	// Revoked by this process is effective at once
+	now = now.Add(time.Second)
+	if err := checker.Revoke(ctx, "uid1"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if revoked, err := checker.Revoked(ctx, issued); err != nil || !revoked {
+		t.Errorf("expected revoked, actual %v, %v", revoked, err)
+	}
+	reissued, _ := fake.Verify(ctx, fake.SessionCookie("uid1", nil))
+	if revoked, err := checker.Revoked(ctx, reissued); err != nil || revoked {
+		t.Errorf("expected the token after the revocation not revoked, actual %v, %v", revoked, err)
+	}
+	if sessions.calls != 2 {
+		t.Errorf("expected Firebase called 2 times before and after the revocation, actual %d", sessions.calls)
+	}
+
+	// Revoked by another process is effective after the TTL
+	other, _ := fake.Verify(ctx, fake.SessionCookie("uid2", nil))
+	checker.Revoked(ctx, other)
+	now = now.Add(time.Second)
+	fake.RevokeRefreshTokens(ctx, "uid2")
+	if revoked, _ := checker.Revoked(ctx, other); revoked {
+		t.Error("expected the cache used within the TTL")
+	}
+	now = now.Add(time.Minute)
+	if revoked, _ := checker.Revoked(ctx, other); !revoked {
+		t.Error("expected revoked after the TTL")
+	}
+
+	sessions.err = errors.New("unavailable")
+	now = now.Add(time.Minute)
+	if _, err := checker.Revoked(ctx, other); err == nil {
+		t.Error("expected error when Firebase is unavailable, but nil")
+	}
+}
+
+func TestRevocationChecker_DropsLeastRecentlyUsed(t *testing.T) {
+	fake := NewFake(projectID)
+	ctx := context.Background()
+	sessions := &countingSessions{SessionManager: fake}
+	checker := NewRevocationChecker(sessions, time.Hour)
+	checker.maxUsers = 2
+
+	check := func(uid string) {
+		if _, err := checker.tokensValidAfter(ctx, uid); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	check("uid1")
+	check("uid2")
+	check("uid1") // uid2 becomes the least recently used
+	check("uid3") // drops uid2 though no entry is expired
+	if sessions.calls != 3 {
+		t.Fatalf("expected Firebase called 3 times, actual %d", sessions.calls)
+	}
+	if checker.lru.Len() != 2 || len(checker.validAfter) != 2 {
+		t.Errorf("expected 2 cached users, actual %d, %d", checker.lru.Len(), len(checker.validAfter))
+	}
+
+	check("uid1")
+	if sessions.calls != 3 {
+		t.Errorf("expected uid1 cached, actual %d calls", sessions.calls)
+	}
+	check("uid2")
+	if sessions.calls != 4 {
+		t.Errorf("expected uid2 dropped, actual %d calls", sessions.calls)
+	}
+}
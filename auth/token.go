@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// Token is a verified Firebase ID token or session cookie
+type Token struct {
+	UID      string `json:"-"`
+	Issuer   string `json:"iss"`
+	Audience string `json:"aud"`
+	Subject  string `json:"sub"`
+	IssuedAt int64  `json:"iat"`
+	Expires  int64  `json:"exp"`
+	AuthTime int64  `json:"auth_time"`
+	// Claims are the claims other than the standard ones above
+	Claims map[string]interface{} `json:"-"`
+}
+
+// TokenVerifier verifies Firebase ID tokens and session cookies
+type TokenVerifier interface {
+	// Verify accepts both ID tokens and session cookies
+	Verify(ctx context.Context, token string) (*Token, error)
+	VerifyIDToken(ctx context.Context, token string) (*Token, error)
+}
+
+// ErrKeysUnavailable is wrapped by the verification errors caused by failing to get the signing keys,
+// which mean that the service is unavailable rather than that the token is invalid.
+var ErrKeysUnavailable = errors.New("auth: signing keys are unavailable")
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	idTokenIssuerPrefix       = "https://securetoken.google.com/"
+	sessionCookieIssuerPrefix = "https://session.firebase.google.com/"
+
+	// clockSkew is allowed for iat and exp as well as Firebase Admin SDK does
+	clockSkew = 5 * time.Minute
+)
+
+// Verifier verifies Firebase ID tokens and session cookies of the project locally.
+// It tells them by the issuer and verifies their signatures with the keys of each.
+type Verifier struct {
+	projectID   string
+	idTokenKeys KeySource
+	sessionKeys KeySource
+	now         func() time.Time
+}
+
+var _ TokenVerifier = (*Verifier)(nil)
+
+func NewVerifier(projectID string, idTokenKeys, sessionKeys KeySource) *Verifier {
+	return &Verifier{
+		projectID:   projectID,
+		idTokenKeys: idTokenKeys,
+		sessionKeys: sessionKeys,
+		now:         time.Now,
+	}
+}
+
+// NewFirebaseVerifier returns the Verifier fetching the keys from Firebase.
+// Call Run to keep the keys fresh in background.
+func NewFirebaseVerifier(projectID string, client *http.Client) *Verifier {
+	return NewVerifier(projectID,
+		NewHTTPKeySource(IDTokenKeysURL, client),
+		NewHTTPKeySource(SessionCookieKeysURL, client))
+}
+
+// Run refreshes the keys of the HTTPKeySources until ctx is done
+func (v *Verifier) Run(ctx context.Context) {
+	for _, keys := range []KeySource{v.idTokenKeys, v.sessionKeys} {
+		if s, ok := keys.(*HTTPKeySource); ok {
+			go s.Run(ctx)
+		}
+	}
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (*Token, error) {
+	return v.verify(ctx, token, false)
+}
+
+// VerifyIDToken verifies the token is an ID token, not a session cookie
+func (v *Verifier) VerifyIDToken(ctx context.Context, token string) (*Token, error) {
+	return v.verify(ctx, token, true)
+}
+
+func (v *Verifier) verify(ctx context.Context, token string, idTokenOnly bool) (*Token, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, errors.New("auth: the token is not a JWT")
+	}
+	var h header
+	if err := decodeSegment(segments[0], &h); err != nil {
+		return nil, fmt.Errorf("auth: the header: %v", err)
+	}
+	var t Token
+	if err := decodeSegment(segments[1], &t); err != nil {
+		return nil, fmt.Errorf("auth: the payload: %v", err)
+	}
+
+	// Cheap checks first, and then the signature
+	var keys KeySource
+	switch t.Issuer {
+	case idTokenIssuerPrefix + v.projectID:
+		keys = v.idTokenKeys
+	case sessionCookieIssuerPrefix + v.projectID:
+		if idTokenOnly {
+			return nil, errors.New("auth: the token is a session cookie")
+		}
+		keys = v.sessionKeys
+	default:
+		return nil, fmt.Errorf("auth: invalid issuer %q", t.Issuer)
+	}
+	if h.Algorithm != "RS256" {
+		return nil, fmt.Errorf("auth: invalid algorithm %q", h.Algorithm)
+	}
+	if h.KeyID == "" {
+		return nil, errors.New("auth: no key ID")
+	}
+	if t.Audience != v.projectID {
+		return nil, fmt.Errorf("auth: invalid audience %q", t.Audience)
+	}
+	if t.Subject == "" || len(t.Subject) > 128 {
+		return nil, errors.New("auth: invalid subject")
+	}
+	now := v.now()
+	if time.Unix(t.IssuedAt, 0).After(now.Add(clockSkew)) {
+		return nil, fmt.Errorf("auth: issued in the future at %d", t.IssuedAt)
+	}
+	if time.Unix(t.Expires, 0).Before(now.Add(-clockSkew)) {
+		return nil, fmt.Errorf("auth: expired at %d", t.Expires)
+	}
+
+	ks, err := keys.Keys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+	}
+	key, ok := ks[h.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("auth: unknown key ID %q", h.KeyID)
+	}
+	if err := verifySignature(segments, key); err != nil {
+		return nil, errors.New("auth: invalid signature")
+	}
+
+	t.UID = t.Subject
+	if err := decodeSegment(segments[1], &t.Claims); err != nil {
+		return nil, fmt.Errorf("auth: the payload: %v", err)
+	}
+	for _, standard := range []string{"iss", "aud", "sub", "iat", "exp", "auth_time", "uid"} {
+		delete(t.Claims, standard)
+	}
+	return &t, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func verifySignature(segments []string, key *rsa.PublicKey) error {
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(segments[0] + "." + segments[1]))
+	return rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature)
+}
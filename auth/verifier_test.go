@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const projectID = "mixlunch-test"
+
+func TestVerify_IDTokenAndSessionCookie_Success(t *testing.T) {
+	fake := NewFake(projectID)
+
+	for name, token := range map[string]string{
+		"ID token":       fake.IDToken("uid1", map[string]interface{}{"email": "a@a.com"}),
+		"Session cookie": fake.SessionCookie("uid1", map[string]interface{}{"email": "a@a.com"}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			verified, err := fake.Verify(context.Background(), token)
+			// Assert
+			if err != nil {
+				t.Fatalf("Test failed. Expected: no error, Actual: %v", err)
+			}
+			if verified.UID != "uid1" {
+				t.Errorf("Test failed. Expected: uid1, Actual: %s", verified.UID)
+			}
+			if verified.Claims["email"] != "a@a.com" {
+				t.Errorf("Test failed. Expected: a@a.com, Actual: %v", verified.Claims["email"])
+			}
+			if _, ok := verified.Claims["iss"]; ok {
+				t.Errorf("Test failed. The standard claims must not be in Claims")
+			}
+		})
+	}
+}
+
+func TestVerify_InvalidTokens_Error(t *testing.T) {
+	fake := NewFake(projectID)
+	other := NewFake(projectID)
+	valid := fake.IDToken("uid1", nil)
+	segments := strings.Split(valid, ".")
+
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{"Not JWT", "abc"},
+		{"Expired", fake.Sign(idTokenIssuerPrefix+projectID, "uid1", nil, -time.Hour)},
+		{"Other project", NewFake("other").IDToken("uid1", nil)},
+		{"Unknown issuer", fake.Sign("https://example.com/"+projectID, "uid1", nil, time.Hour)},
+		{"No subject", fake.IDToken("", nil)},
+		{"Signed by another key", other.IDToken("uid1", nil)},
+		{"Tampered payload", segments[0] + "." + encodeSegment(map[string]interface{}{
+			"iss": idTokenIssuerPrefix + projectID, "aud": projectID, "sub": "admin",
+			"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+		}) + "." + segments[2]},
+		{"Algorithm none", encodeSegment(header{Algorithm: "none", KeyID: fakeKeyID}) + "." + segments[1] + "."},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			_, err := fake.Verify(context.Background(), tc.token)
+			// Assert
+			if err == nil {
+				t.Errorf("Test failed. Expected: error, Actual: no error")
+			}
+		})
+	}
+}
+
+type failingKeySource struct{}
+
+func (failingKeySource) Keys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	return nil, errors.New("network is down")
+}
+
+func TestVerify_KeysUnavailable_ErrKeysUnavailable(t *testing.T) {
+	// Arrange
+	fake := NewFake(projectID)
+	verifier := NewVerifier(projectID, failingKeySource{}, failingKeySource{})
+	// Act
+	_, err := verifier.Verify(context.Background(), fake.IDToken("uid1", nil))
+	// Assert
+	if !errors.Is(err, ErrKeysUnavailable) {
+		t.Errorf("Test failed. Expected: ErrKeysUnavailable, Actual: %v", err)
+	}
+}
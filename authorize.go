@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/momotaro98/mixlunch-service-api/auth"
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+// authorizeUser returns ForbiddenError unless the caller is the user.
+// It allows the requests not authenticated, which AuthMiddle passes only when the authentication is deactivated.
+func authorizeUser(ctx context.Context, uid string) error {
+	token, ok := auth.TokenFrom(ctx)
+	if !ok {
+		return nil
+	}
+	if token.UID != uid {
+		return domainerror.NewForbiddenError(token.UID, uid)
+	}
+	return nil
+}
+
+// OwnerMiddle authorizes the caller as the user of the path variable.
+// It must be inside AuthMiddle.
+func OwnerMiddle(l logger.Logger, pathVar string) MFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := authorizeUser(r.Context(), mux.Vars(r)[pathVar]); err != nil {
+				handleError(w, r, l, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RoleMiddle authorizes the caller having the required role or higher.
+// It must be inside AuthMiddle.
+func RoleMiddle(l logger.Logger, roles *auth.RoleResolver, required auth.Role) MFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			token, ok := auth.TokenFrom(ctx)
+			if !ok {
+				// The authentication is deactivated
+				next.ServeHTTP(w, r)
+				return
+			}
+			role, err := roles.Resolve(ctx, token)
+			if err != nil {
+				handleError(w, r, l, err)
+				return
+			}
+			if !role.Includes(required) {
+				handleError(w, r, l, domainerror.NewInsufficientRoleError(token.UID, string(required)))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
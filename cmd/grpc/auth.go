@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/momotaro98/mixlunch-service-api/auth"
+	"github.com/momotaro98/mixlunch-service-api/config"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+// healthService is called by the probes without any credentials
+const healthService = "/grpc.health.v1.Health/"
+
+// keysFetchTimeout bounds fetching the keys of the service accounts
+const keysFetchTimeout = 10 * time.Second
+
+// authenticator authenticates the service callers by the bearer token in the authorization metadata,
+// which is either a static token or a JWT signed by a service account,
+// and authorizes them by the allow list of the method. The methods not listed are denied.
+type authenticator struct {
+	tokens   map[string]string // caller by token
+	accounts map[string]string // caller by service account email
+	verifier *auth.ServiceAccountVerifier
+	allow    map[string][]string // callers by full method name
+}
+
+func newAuthenticator(cfg config.GRPCAuth) (*authenticator, error) {
+	tokens, err := cfg.TokenCallers()
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := cfg.ServiceAccountCallers()
+	if err != nil {
+		return nil, err
+	}
+	allow, err := cfg.AllowedCallers()
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: keysFetchTimeout}
+	keys := make(map[string]auth.KeySource, len(accounts))
+	for email := range accounts {
+		keys[email] = auth.ServiceAccountKeys(email, client)
+	}
+	return &authenticator{
+		tokens:   tokens,
+		accounts: accounts,
+		verifier: auth.NewServiceAccountVerifier(cfg.Audience, keys),
+		allow:    allow,
+	}, nil
+}
+
+// authenticate returns the status error of Unauthenticated or PermissionDenied unless the caller is allowed
+func (a *authenticator) authenticate(ctx context.Context, fullMethod string) error {
+	if strings.HasPrefix(fullMethod, healthService) {
+		return nil
+	}
+
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = strings.TrimPrefix(values[0], "Bearer ")
+		}
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	caller, err := a.caller(ctx, token)
+	if errors.Is(err, auth.ErrKeysUnavailable) {
+		return status.Error(codes.Unavailable, "authentication is unavailable")
+	}
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if !contains(a.allow[fullMethod], caller) {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", caller, fullMethod)
+	}
+	return nil
+}
+
+func (a *authenticator) caller(ctx context.Context, token string) (string, error) {
+	for t, caller := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return caller, nil
+		}
+	}
+	if len(a.accounts) == 0 {
+		return "", errors.New("unknown token")
+	}
+	email, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return a.accounts[email], nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// authUnaryInterceptor authenticates and authorizes the caller of each unary call
+func authUnaryInterceptor(a *authenticator, l logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authenticate(ctx, info.FullMethod); err != nil {
+			l.WithError(err).LogContext(ctx, logger.Warn, "authentication failed")
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor authenticates and authorizes the caller of each streaming call
+func authStreamInterceptor(a *authenticator, l logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authenticate(ss.Context(), info.FullMethod); err != nil {
+			l.WithError(err).LogContext(ss.Context(), logger.Warn, "authentication failed")
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// transportCredentials returns the TLS credentials, which also require the client certificates
+// signed by the client CA when it's given
+func transportCredentials(cfg config.GRPCTLS) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.ClientCAFile != "" {
+		b, err := ioutil.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("%s has no certificates", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/momotaro98/mixlunch-service-api/auth"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+const (
+	matcherEmail = "matcher@mixlunch.iam.gserviceaccount.com"
+	audience     = "mixlunch-grpc"
+)
+
+func TestAuthInterceptors(t *testing.T) {
+	fake := auth.NewFake("mixlunch")
+	a := &authenticator{
+		tokens:   map[string]string{"s3cr3t": "ops"},
+		accounts: map[string]string{matcherEmail: "matcher"},
+		verifier: auth.NewServiceAccountVerifier(audience, map[string]auth.KeySource{matcherEmail: fake.Keys()}),
+		allow: map[string][]string{
+			"/pb.MixLunch/CreateParties": {"matcher"},
+			"/pb.MixLunch/GetParties":    {"matcher", "ops"},
+		},
+	}
+	l := logger.Nop()
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	testCases := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		expected codes.Code
+	}{
+		{"No token", context.Background(), "/pb.MixLunch/GetParties", codes.Unauthenticated},
+		{"Static token", withToken("s3cr3t"), "/pb.MixLunch/GetParties", codes.OK},
+		{"Unknown token", withToken("guess"), "/pb.MixLunch/GetParties", codes.Unauthenticated},
+		{"Service account", withToken(fake.ServiceAccountJWT(matcherEmail, audience)), "/pb.MixLunch/CreateParties", codes.OK},
+		{"Service account for another audience", withToken(fake.ServiceAccountJWT(matcherEmail, "other")), "/pb.MixLunch/GetParties", codes.Unauthenticated},
+		{"Unknown service account", withToken(fake.ServiceAccountJWT("evil@example.com", audience)), "/pb.MixLunch/GetParties", codes.Unauthenticated},
+		{"Not in the allow list", withToken("s3cr3t"), "/pb.MixLunch/CreateParties", codes.PermissionDenied},
+		{"Not listed method", withToken("s3cr3t"), "/pb.MixLunch/GetUsersForMatching", codes.PermissionDenied},
+		{"Health without token", context.Background(), "/grpc.health.v1.Health/Check", codes.OK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Unary
+			_, err := authUnaryInterceptor(a, l)(tc.ctx, "req", &grpc.UnaryServerInfo{FullMethod: tc.method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return req, nil
+				})
+			if actual := status.Code(err); actual != tc.expected {
+				t.Errorf("unary: expected %s, actual %s (%v)", tc.expected, actual, err)
+			}
+
+			// Stream
+			err = authStreamInterceptor(a, l)(nil, &serverStreamWithContext{ctx: tc.ctx}, &grpc.StreamServerInfo{FullMethod: tc.method},
+				func(srv interface{}, ss grpc.ServerStream) error {
+					return nil
+				})
+			if actual := status.Code(err); actual != tc.expected {
+				t.Errorf("stream: expected %s, actual %s (%v)", tc.expected, actual, err)
+			}
+		})
+	}
+}
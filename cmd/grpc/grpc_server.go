@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/momotaro98/stew"
@@ -14,6 +16,7 @@ import (
 	"github.com/momotaro98/mixlunch-service-api/partyservice"
 	"github.com/momotaro98/mixlunch-service-api/pb"
 	"github.com/momotaro98/mixlunch-service-api/tagservice"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
 	usService "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
 	"github.com/momotaro98/mixlunch-service-api/userservice"
 )
@@ -54,26 +57,28 @@ func provideGRPCMixLunchServer(logger logger.Logger,
 }
 
 func (s *gRPCMixLunchServer) GetUsersForMatching(targetDate *pb.TargetDate, stream pb.MixLunch_GetUsersForMatchingServer) error {
-	s.logger.Log(logger.Info, "", fmt.Sprintf("Start GetUsersForMatching process with TargetDate, %v", *targetDate))
+	ctx := stream.Context()
+	l := s.logger.Named("userscheduleservice")
+	l.LogContext(ctx, logger.Info, fmt.Sprintf("Start GetUsersForMatching process with TargetDate, %v", *targetDate))
 	// Retrieve users from DB
 	beginDateTimeStr, endDateTimeStr := generateBeginEndOfTheDay(targetDate.Date)
-	eachUserSchedulesOfTheDate, err := s.usServer.GetEachUserSchedules(beginDateTimeStr, endDateTimeStr)
+	eachUserSchedulesOfTheDate, err := s.usServer.GetEachUserSchedules(ctx, beginDateTimeStr, endDateTimeStr)
 	if err != nil {
-		s.logger.Log(logger.Error, "", err.Error())
+		l.WithError(err).LogContext(ctx, logger.Error, "Getting the user schedules failed")
 		return stew.Wrap(err)
 	}
 
 	// Assign the data into pb.UserModelForMatching and send it to client with gRPC stream
 	for _, aUserSchedule := range eachUserSchedulesOfTheDate {
 		// Request to user service
-		user, err := s.userServer.GetUserByUserId(aUserSchedule.UserId)
+		user, err := s.userServer.GetUserByUserId(ctx, aUserSchedule.UserId)
 		if err != nil {
-			s.logger.Log(logger.Error, "", err.Error())
+			l.WithError(err).With("uid", aUserSchedule.UserId).LogContext(ctx, logger.Error, "Getting the user failed")
 			return stew.Wrap(err)
 		}
 
 		// [Business Logic] Assemble Blacklist User
-		blacklistOfTheUser, err := s.assembleBlacklist(user)
+		blacklistOfTheUser, err := s.assembleBlacklist(ctx, user)
 		if err != nil {
 			return stew.Wrap(err)
 		}
@@ -96,17 +101,23 @@ func (s *gRPCMixLunchServer) GetUsersForMatching(targetDate *pb.TargetDate, stre
 
 		// Send to Party service via gRPC
 		if err := stream.Send(&userModelForMatching); err != nil {
-			s.logger.Log(logger.Error, "", err.Error())
+			l.WithError(err).LogContext(ctx, logger.Error, "Sending the user failed")
 			return stew.Wrap(err)
 		}
 	}
 
-	s.logger.Log(logger.Info, "", "Process succeeded")
+	l.LogContext(ctx, logger.Info, "Process succeeded")
 	return nil
 }
 
-func (s *gRPCMixLunchServer) assembleBlacklist(user *userservice.User) (blacklistUsers []string, err error) {
-	// Pure black list
+func (s *gRPCMixLunchServer) assembleBlacklist(ctx context.Context, user *userservice.User) (blacklistUsers []string, err error) {
+	ctx, span := tracing.Start(ctx, "grpc.assembleBlacklist")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Pure black list, which is read from DB every matching so that the unblocked users are matched again
 	blacklistUsers = append(blacklistUsers, user.BlockingUsers...)
 
 	// Add black list by Avoiding Business Logic
@@ -116,7 +127,7 @@ func (s *gRPCMixLunchServer) assembleBlacklist(user *userservice.User) (blacklis
 	)
 
 	// [Business Logic] 最後のランチから ignoreTimes 回分のランチメイトは無視する。
-	pLastN, err := s.partyServer.GetLastNPartiesOfAUser(user.UserId, ignoreTimes)
+	pLastN, err := s.partyServer.GetLastNPartiesOfAUser(ctx, user.UserId, ignoreTimes)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -125,7 +136,7 @@ func (s *gRPCMixLunchServer) assembleBlacklist(user *userservice.User) (blacklis
 	var (
 		begin = time.Now().AddDate(0, 0, daysAgo)
 	)
-	pTimeRange, err := s.partyServer.GetPartyByUserIdAndTimeRange(
+	pTimeRange, err := s.partyServer.GetPartyByUserIdAndTimeRange(ctx,
 		user.UserId, begin.Format(time.RFC3339), "")
 	if err != nil {
 		return nil, stew.Wrap(err)
@@ -167,46 +178,56 @@ func convertSliceLangToString(langs []userservice.Language) []string {
 }
 
 func (s *gRPCMixLunchServer) CreateParties(stream pb.MixLunch_CreatePartiesServer) error {
-	s.logger.Log(logger.Info, "", "Start CreateParties process")
+	ctx := stream.Context()
+	l := s.logger.Named("partyservice")
+	l.LogContext(ctx, logger.Info, "Start CreateParties process")
 	partyChan := make(chan *partyservice.PartyForCommand)
 
 	// Receive parties via gRPC
 	recErr := make(chan error)
 	go receivePartiesFromMatchingModule(stream, partyChan, recErr)
 
-	// Insert parties into DB
+	// Collect parties to insert into DB
+	collected := make(chan []*partyservice.PartyForCommand, 1)
 	go func() {
+		var wg sync.WaitGroup
 		parties := make([]*partyservice.PartyForCommand, 0)
 		for party := range partyChan {
 			// Generate Chat room by using passed Chat Room ID
+			wg.Add(1)
 			go func(p *partyservice.PartyForCommand) {
-				if err := s.partyServer.GenerateChatRoom(p.ChatRoomId); err != nil {
-					s.logger.Log(logger.Error, "", fmt.Sprintf("Failed to generate chatroom ID: %v, err: %v", party, err))
+				defer wg.Done()
+				if err := s.partyServer.GenerateChatRoom(ctx, p.ChatRoomId); err != nil {
+					l.WithError(err).With("chat_room_id", p.ChatRoomId).LogContext(ctx, logger.Error, "Failed to generate chatroom")
 				}
 			}(party)
 			// Add the party to DB
 			parties = append(parties, party)
 		}
-
-		if err := s.partyServer.UpsertParties(parties); err != nil {
-			s.logger.Log(logger.Error, "", fmt.Sprintf("Upserting the parties failed. err: %+v", err))
-			panic(err)
-		} else {
-			s.logger.Log(logger.Info, "", "Upserting the parties succeeded")
-		}
+		// The chat rooms must be generated before the stream context is done
+		wg.Wait()
+		collected <- parties
 	}()
 
 	if err, open := <-recErr; open {
-		s.logger.Log(logger.Error, "", err.Error())
+		l.WithError(err).LogContext(ctx, logger.Error, "Receiving the parties failed")
 		return err
 	}
 
-	return nil
+	// [Note] Upserting is done within this method, not in background,
+	// since the stream context is cancelled after the method returns.
+	if err := s.partyServer.UpsertParties(ctx, <-collected); err != nil {
+		l.WithError(err).LogContext(ctx, logger.Error, "Upserting the parties failed")
+		return stew.Wrap(err)
+	}
+	l.LogContext(ctx, logger.Info, "Upserting the parties succeeded")
+
+	// The matching module is replied to only after the parties are stored
+	return stream.SendAndClose(&pb.Empty{})
 }
 
 func receivePartiesFromMatchingModule(stream pb.MixLunch_CreatePartiesServer, partyChan chan<- *partyservice.PartyForCommand, errChan chan<- error) {
 	defer func() {
-		_ = stream.SendAndClose(&pb.Empty{})
 		close(partyChan)
 		close(errChan)
 	}()
@@ -246,12 +267,14 @@ func receivePartiesFromMatchingModule(stream pb.MixLunch_CreatePartiesServer, pa
 }
 
 func (s *gRPCMixLunchServer) GetParties(targetDate *pb.TargetDate, stream pb.MixLunch_GetPartiesServer) error {
-	s.logger.Log(logger.Info, "", fmt.Sprintf("Start GetParties process with TargetDate, %v", *targetDate))
+	ctx := stream.Context()
+	l := s.logger.Named("partyservice")
+	l.LogContext(ctx, logger.Info, fmt.Sprintf("Start GetParties process with TargetDate, %v", *targetDate))
 	// Retrieve parties from DB
 	beginDateTimeStr, endDateTimeStr := generateBeginEndOfTheDay(targetDate.Date)
-	partiesOfTheDate, err := s.partyServer.GetParties(beginDateTimeStr, endDateTimeStr)
+	partiesOfTheDate, err := s.partyServer.GetParties(ctx, beginDateTimeStr, endDateTimeStr)
 	if err != nil {
-		s.logger.Log(logger.Error, "", err.Error())
+		l.WithError(err).LogContext(ctx, logger.Error, "Getting the parties failed")
 		return err
 	}
 	// Assign the data into pb.Party and send it to client with gRPC stream
@@ -280,10 +303,10 @@ func (s *gRPCMixLunchServer) GetParties(targetDate *pb.TargetDate, stream pb.Mix
 
 		// Send to client
 		if err := stream.Send(&partyToSend); err != nil {
-			s.logger.Log(logger.Error, "", err.Error())
+			l.WithError(err).LogContext(ctx, logger.Error, "Sending the party failed")
 			return err
 		}
 	}
-	s.logger.Log(logger.Info, "", "Process succeeded")
+	l.LogContext(ctx, logger.Info, "Process succeeded")
 	return nil
 }
@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
 	"reflect"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
 
 	"github.com/momotaro98/mixlunch-service-api/cmd/grpc/testmock"
 	"github.com/momotaro98/mixlunch-service-api/logger"
 	"github.com/momotaro98/mixlunch-service-api/partyservice"
+	"github.com/momotaro98/mixlunch-service-api/pb"
 	"github.com/momotaro98/mixlunch-service-api/userservice"
 )
 
@@ -34,7 +39,7 @@ func TestAssembleBlacklist(t *testing.T) {
 	// Input
 	partyMock := testmock.NewMockPartyServer(mockCtrl)
 	partyMock.EXPECT().
-		GetLastNPartiesOfAUser(userID, 3).
+		GetLastNPartiesOfAUser(gomock.Any(), userID, 3).
 		Return(&partyservice.Parties{
 			Parties: []*partyservice.Party{
 				{
@@ -74,7 +79,7 @@ func TestAssembleBlacklist(t *testing.T) {
 		begin = time.Now().AddDate(0, 0, -14)
 	)
 	partyMock.EXPECT().
-		GetPartyByUserIdAndTimeRange(userID, begin.Format(time.RFC3339), "").
+		GetPartyByUserIdAndTimeRange(gomock.Any(), userID, begin.Format(time.RFC3339), "").
 		Return(&partyservice.Parties{
 			Parties: []*partyservice.Party{
 				{
@@ -91,7 +96,7 @@ func TestAssembleBlacklist(t *testing.T) {
 		}, nil)
 
 	grpcServer := provideGRPCMixLunchServer(
-		logger.ProvideLogger(&logger.Config{ErrorLevel: "debug"}),
+		logger.NewLogger(logger.Debug),
 		testmock.NewMockUserScheduleServer(mockCtrl),
 		partyMock,
 		testmock.NewMockUserServer(mockCtrl),
@@ -104,7 +109,7 @@ func TestAssembleBlacklist(t *testing.T) {
 	}
 
 	// Act
-	blackList, _ := grpcServer.assembleBlacklist(user)
+	blackList, _ := grpcServer.assembleBlacklist(context.Background(), user)
 
 	// Assert
 	if len(blackList) != 5 {
@@ -115,3 +120,68 @@ func TestAssembleBlacklist(t *testing.T) {
 		t.Errorf("expected: 5 mates, got: %+v", blackList)
 	}
 }
+
+// fakeCreatePartiesServer streams the parties and records the reply
+type fakeCreatePartiesServer struct {
+	grpc.ServerStream
+	parties []*pb.Party
+	replied bool
+}
+
+func (s *fakeCreatePartiesServer) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeCreatePartiesServer) Recv() (*pb.Party, error) {
+	if len(s.parties) == 0 {
+		return nil, io.EOF
+	}
+	p := s.parties[0]
+	s.parties = s.parties[1:]
+	return p, nil
+}
+
+func (s *fakeCreatePartiesServer) SendAndClose(*pb.Empty) error {
+	s.replied = true
+	return nil
+}
+
+func TestCreateParties(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	newStream := func() *fakeCreatePartiesServer {
+		return &fakeCreatePartiesServer{parties: []*pb.Party{
+			{StartFrom: "2020-07-17T12:00:00Z", EndTo: "2020-07-17T13:00:00Z", RoomId: "room1"},
+		}}
+	}
+
+	t.Run("replied after upserting", func(t *testing.T) {
+		partyMock := testmock.NewMockPartyServer(mockCtrl)
+		partyMock.EXPECT().GenerateChatRoom(gomock.Any(), "room1").Return(nil)
+		partyMock.EXPECT().UpsertParties(gomock.Any(), gomock.Len(1)).Return(nil)
+		grpcServer := provideGRPCMixLunchServer(logger.NewLogger(logger.Debug),
+			testmock.NewMockUserScheduleServer(mockCtrl), partyMock, testmock.NewMockUserServer(mockCtrl))
+		stream := newStream()
+		if err := grpcServer.CreateParties(stream); err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if !stream.replied {
+			t.Error("expected the reply")
+		}
+	})
+
+	t.Run("not replied when upserting failed", func(t *testing.T) {
+		partyMock := testmock.NewMockPartyServer(mockCtrl)
+		partyMock.EXPECT().GenerateChatRoom(gomock.Any(), "room1").Return(nil)
+		partyMock.EXPECT().UpsertParties(gomock.Any(), gomock.Any()).Return(errors.New("deadlock"))
+		grpcServer := provideGRPCMixLunchServer(logger.NewLogger(logger.Debug),
+			testmock.NewMockUserScheduleServer(mockCtrl), partyMock, testmock.NewMockUserServer(mockCtrl))
+		stream := newStream()
+		if err := grpcServer.CreateParties(stream); err == nil {
+			t.Error("expected the error")
+		}
+		if stream.replied {
+			t.Error("expected no reply")
+		}
+	})
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"database/sql"
+
 	"github.com/google/wire"
 
+	"github.com/momotaro98/mixlunch-service-api/logger"
 	"github.com/momotaro98/mixlunch-service-api/partyservice"
 	"github.com/momotaro98/mixlunch-service-api/tagservice"
 	usService "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
@@ -32,3 +35,12 @@ var gRPCSuperSet = wire.NewSet(
 	partyservice.ProvideChatRoomRepository,
 	partyservice.ProvidePartyServer,
 )
+
+// application is the dependency container of the gRPC server.
+// All the services share the single DB pool.
+type application struct {
+	DB     *sql.DB
+	Logger logger.Logger
+	Levels *logger.Levels
+	Server *gRPCMixLunchServer
+}
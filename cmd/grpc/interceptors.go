@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+	"github.com/momotaro98/mixlunch-service-api/metrics"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+// serverStreamWithContext is a grpc.ServerStream whose context can be replaced by interceptors.
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context {
+	return s.ctx
+}
+
+// timeoutStreamInterceptor bounds the stream context of each call with the timeout.
+// A zero or negative timeout leaves the stream context as it is.
+// The health service is exempted since Watch streams the status as long as the prober keeps it.
+func timeoutStreamInterceptor(timeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if timeout <= 0 || strings.HasPrefix(info.FullMethod, healthService) {
+			return handler(srv, ss)
+		}
+		ctx, cancel := context.WithTimeout(ss.Context(), timeout)
+		defer cancel()
+		return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// chainUnaryInterceptors makes an interceptor running the interceptors in order, the first is the outermost.
+// grpc.ChainUnaryInterceptor isn't available in the gRPC version we use.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
+// chainStreamInterceptors makes an interceptor running the interceptors in order, the first is the outermost.
+// grpc.ChainStreamInterceptor isn't available in the gRPC version we use.
+func chainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+		return chained(srv, ss)
+	}
+}
+
+const xRequestId = "x-request-id"
+
+// withRequestId stores the request ID of the incoming metadata, or a new one when it's absent, in the context
+func withRequestId(ctx context.Context) (context.Context, string) {
+	var reqId string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(xRequestId); len(values) > 0 {
+			reqId = values[0]
+		}
+	}
+	reqId = logger.RequestIdOrNew(reqId)
+	return logger.WithRequestId(ctx, reqId), reqId
+}
+
+// requestIdUnaryInterceptor sets the request ID to the context for the logger and to the response header,
+// and the method to the context for the logger
+func requestIdUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, reqId := withRequestId(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(xRequestId, reqId))
+	return handler(logger.WithRoute(ctx, info.FullMethod), req)
+}
+
+// requestIdStreamInterceptor sets the request ID to the stream context for the logger and to the response header,
+// and the method to the stream context for the logger
+func requestIdStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, reqId := withRequestId(ss.Context())
+	_ = ss.SetHeader(metadata.Pairs(xRequestId, reqId))
+	return handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: logger.WithRoute(ctx, info.FullMethod)})
+}
+
+// startServerSpan starts the server span of the method as the child of the caller's span in the traceparent metadata if any
+func startServerSpan(ctx context.Context, method string) (context.Context, *tracing.Span) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = tracing.Extract(ctx, tracing.MetadataCarrier(md))
+	}
+	ctx, span := tracing.StartKind(ctx, method, tracing.Server)
+	span.SetAttribute("rpc.system", "grpc")
+	span.SetAttribute("rpc.method", method)
+	return ctx, span
+}
+
+// endServerSpan ends the span with the status code of the call
+func endServerSpan(span *tracing.Span, err error) {
+	span.SetAttribute("rpc.grpc.status_code", int(status.Code(err)))
+	span.RecordError(err)
+	span.End()
+}
+
+// traceUnaryInterceptor makes a span per call, whose children are the spans of the services and the SQL statements
+func traceUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startServerSpan(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	endServerSpan(span, err)
+	return resp, err
+}
+
+// traceStreamInterceptor makes a span per call, whose children are the spans of the services and the SQL statements
+func traceStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+	err := handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+	endServerSpan(span, err)
+	return err
+}
+
+// metricsUnaryInterceptor counts the calls and observes the latency by method and status code
+func metricsUnaryInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.ObserveGRPC(info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
+
+// metricsStreamInterceptor counts the calls and the streamed messages, and observes the latency
+func metricsStreamInterceptor(m *metrics.Metrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, &countingServerStream{ServerStream: ss, metrics: m, method: info.FullMethod})
+		m.ObserveGRPC(info.FullMethod, status.Code(err), time.Since(start))
+		return err
+	}
+}
+
+// countingServerStream counts the messages sent and received successfully
+type countingServerStream struct {
+	grpc.ServerStream
+	metrics *metrics.Metrics
+	method  string
+}
+
+func (s *countingServerStream) SendMsg(msg interface{}) error {
+	err := s.ServerStream.SendMsg(msg)
+	if err == nil {
+		s.metrics.IncGRPCMessage(s.method, metrics.Sent)
+	}
+	return err
+}
+
+func (s *countingServerStream) RecvMsg(msg interface{}) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if err == nil {
+		s.metrics.IncGRPCMessage(s.method, metrics.Received)
+	}
+	return err
+}
+
+// recoverUnaryInterceptor converts a panic in the call into a logged codes.Internal error
+// with the stack trace and the request ID instead of crashing the server.
+func recoverUnaryInterceptor(l logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ctx, l, info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// recoverStreamInterceptor converts a panic in the call into a logged codes.Internal error
+// with the stack trace and the request ID instead of crashing the server.
+func recoverStreamInterceptor(l logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ss.Context(), l, info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, l logger.Logger, method string, p interface{}) error {
+	l.LogFields(ctx, logger.Error, fmt.Sprintf("panic in %s: %v", method, p), logger.Fields{
+		"stack_trace": string(debug.Stack()),
+	})
+	return status.Error(codes.Internal, "internal error")
+}
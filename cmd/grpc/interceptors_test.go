@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+func TestChainUnaryInterceptors(t *testing.T) {
+	var order []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			order = append(order, name)
+			return handler(ctx, req)
+		}
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		order = append(order, "handler")
+		return req, nil
+	}
+
+	chained := chainUnaryInterceptors(interceptor("outer"), interceptor("inner"))
+	resp, err := chained(context.Background(), "req", &grpc.UnaryServerInfo{}, handler)
+	if err != nil || resp != "req" {
+		t.Fatalf("unexpected result %v, %v", resp, err)
+	}
+
+	expected := []string{"outer", "inner", "handler"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, actual %v", expected, order)
+	}
+}
+
+func TestTimeoutStreamInterceptor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		method   string
+		deadline bool
+	}{
+		{"Bounded", "/pb.MixLunch/CreateParties", true},
+		{"Health watch is not bounded", "/grpc.health.v1.Health/Watch", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := timeoutStreamInterceptor(time.Minute)(nil, &serverStreamWithContext{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: tc.method},
+				func(srv interface{}, ss grpc.ServerStream) error {
+					if _, ok := ss.Context().Deadline(); ok != tc.deadline {
+						t.Errorf("expected deadline %v, actual %v", tc.deadline, ok)
+					}
+					return nil
+				})
+			if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestWithRequestId(t *testing.T) {
+	// Given by the client
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(xRequestId, "req-from-client"))
+	ctx, reqId := withRequestId(ctx)
+	if reqId != "req-from-client" || logger.RequestIdFrom(ctx) != reqId {
+		t.Errorf("expected req-from-client, actual %s, %s", reqId, logger.RequestIdFrom(ctx))
+	}
+
+	// Generated when absent
+	ctx, reqId = withRequestId(context.Background())
+	if reqId == "" || logger.RequestIdFrom(ctx) != reqId {
+		t.Errorf("expected a generated ID, actual %q, %q", reqId, logger.RequestIdFrom(ctx))
+	}
+}
+
+func TestRecoverInterceptors(t *testing.T) {
+	l := logger.NewRecorder()
+
+	_, err := recoverUnaryInterceptor(l)(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/test/Unary"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("unary")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("unary: expected Internal, actual %v", err)
+	}
+
+	err = recoverStreamInterceptor(l)(nil, &serverStreamWithContext{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/test/Stream"},
+		func(srv interface{}, ss grpc.ServerStream) error {
+			panic("stream")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("stream: expected Internal, actual %v", err)
+	}
+
+	entries := l.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, actual %d", len(entries))
+	}
+	for _, e := range entries {
+		if e.Level != logger.Error || e.Fields["stack_trace"] == nil {
+			t.Errorf("expected the panic logged at Error with the stack trace, actual %+v", e)
+		}
+	}
+	if entries[0].Message != "panic in /test/Unary: unary" {
+		t.Errorf("unexpected message %q", entries[0].Message)
+	}
+}
+
+func TestTraceUnaryInterceptor(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracing.SetTracer(tracing.NewTracer(exporter, 1))
+	defer tracing.SetTracer(nil)
+
+	const caller = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tracing.TraceparentHeader, caller))
+	_, err := traceUnaryInterceptor(ctx, "req", &grpc.UnaryServerInfo{FullMethod: "/pb.MixLunch/GetParties"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			_, span := tracing.Start(ctx, "partyservice.GetParties")
+			span.End()
+			return nil, status.Error(codes.NotFound, "no parties")
+		})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected the error of the handler, actual %v", err)
+	}
+
+	server, child := exporter.Span("/pb.MixLunch/GetParties"), exporter.Span("partyservice.GetParties")
+	if server == nil || child == nil {
+		t.Fatalf("expected the server and the child spans, actual %+v", exporter.Spans())
+	}
+	if server.SpanContext.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || server.ParentSpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("expected the span in the trace of the caller, actual %+v", server)
+	}
+	if child.ParentSpanID != server.SpanContext.SpanID {
+		t.Error("expected the service span the child of the server span")
+	}
+	if server.Kind != tracing.Server || server.Attributes["rpc.grpc.status_code"] != int(codes.NotFound) || server.Error == "" {
+		t.Errorf("unexpected server span %+v", server)
+	}
+}
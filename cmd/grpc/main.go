@@ -1,33 +1,37 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/momotaro98/mixlunch-service-api/config"
+	"github.com/momotaro98/mixlunch-service-api/health"
 	"github.com/momotaro98/mixlunch-service-api/logger"
-	"github.com/momotaro98/mixlunch-service-api/partyservice"
+	"github.com/momotaro98/mixlunch-service-api/metrics"
 	"github.com/momotaro98/mixlunch-service-api/pb"
-	"github.com/momotaro98/mixlunch-service-api/tagservice"
-	usService "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
-	"github.com/momotaro98/mixlunch-service-api/userservice"
-)
-
-const (
-	ServiceAccountKeyPath = "./serviceAccount/serviceAccountKey.json"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
 )
 
 func main() {
-	var (
-		gRPCAddr = flag.String("grpc", ":8081", "gRPC listen address")
-	)
-	flag.Parse()
+	// Loading config from the file, environment variables and flags
+	cfg, err := config.Load(os.Args[0], os.Args[1:], os.LookupEnv)
+	if err == flag.ErrHelp {
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	errChan := make(chan error)
 	go func() {
@@ -36,45 +40,138 @@ func main() {
 		errChan <- fmt.Errorf("%s", <-c)
 	}()
 
-	// Initial loading config
-	var (
-		logConf = &logger.Config{
-			ErrorLevel: logger.Info, // Might need to be set from command argument
-		}
+	logConf := cfg.Logger()
+	app, cleanup, err := initializeApplication(logConf, cfg.Database(), cfg.PartyService())
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&charset=utf8mb4",
-			os.Getenv("DB_USER"), os.Getenv("DB_PASS"),
-			os.Getenv("DB_HOST"), os.Getenv("DB_PORT"),
-			os.Getenv("DB_DATABASE"),
-		)
-		usConf = &usService.Config{
-			DSN: dsn,
-		}
-		pConf = &partyservice.Config{
-			DSN:                   dsn,
-			AppCredentialFilePath: ServiceAccountKeyPath,
+	// Reload the log levels from the config sources on SIGHUP
+	go logger.ReloadOnHangup(context.Background(), app.Levels, func() (*logger.Config, error) {
+		cfg, err := config.Load(os.Args[0], os.Args[1:], os.LookupEnv)
+		if err != nil {
+			return nil, err
 		}
-		tConf = &tagservice.Config{
-			DSN: dsn,
+		return cfg.Logger(), nil
+	}, app.Logger)
+
+	// Tracing, which the spans of the services and the SQL statements use as well
+	tracer, err := tracing.New(cfg.Tracer("mixlunch-service-grpc"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	tracing.SetTracer(tracer)
+
+	// Metrics
+	m := metrics.New()
+	m.Register(metrics.NewDBStatsCollector(app.DB))
+
+	// Interceptors
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		requestIdUnaryInterceptor,
+		traceUnaryInterceptor,
+		metricsUnaryInterceptor(m),
+		recoverUnaryInterceptor(app.Logger),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		requestIdStreamInterceptor,
+		traceStreamInterceptor,
+		metricsStreamInterceptor(m),
+		recoverStreamInterceptor(app.Logger),
+	}
+	if cfg.GRPC.Auth.Activate {
+		a, err := newAuthenticator(cfg.GRPC.Auth)
+		if err != nil {
+			log.Fatal(err)
 		}
-		uConf = &userservice.Config{
-			DSN: dsn,
+		// The keys of the service accounts are refreshed as long as the process lives
+		a.verifier.Run(context.Background())
+		unaryInterceptors = append(unaryInterceptors, authUnaryInterceptor(a, app.Logger))
+		streamInterceptors = append(streamInterceptors, authStreamInterceptor(a, app.Logger))
+	}
+	streamInterceptors = append(streamInterceptors, timeoutStreamInterceptor(cfg.GRPC.CallTimeout))
+
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(chainUnaryInterceptors(unaryInterceptors...)),
+		grpc.StreamInterceptor(chainStreamInterceptors(streamInterceptors...)),
+	}
+	if cfg.GRPC.TLS.CertFile != "" {
+		creds, err := transportCredentials(cfg.GRPC.TLS)
+		if err != nil {
+			log.Fatal(err)
 		}
-	)
+		opts = append(opts, grpc.Creds(creds))
+	}
 
 	// gPRC transport
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterMixLunchServer(grpcServer, app.Server)
+
+	// Health service
+	hc := health.New("", cfg.Health.Timeout)
+	hc.Add("mysql", health.PingDB(app.DB))
+	if cfg.Health.Firebase {
+		hc.Add("firebase", health.Reachable(http.DefaultClient, health.FirebaseURL))
+	}
+	healthpb.RegisterHealthServer(grpcServer, health.NewGRPCServer(hc, 5*time.Second, "pb.MixLunch"))
+
 	go func() {
-		listener, err := net.Listen("tcp", *gRPCAddr)
+		listener, err := net.Listen("tcp", cfg.GRPC.Addr)
 		if err != nil {
 			errChan <- err
 			return
 		}
-		grpcServer := grpc.NewServer()
-		pb.RegisterMixLunchServer(grpcServer, initializeGRPCServer(logConf, usConf, pConf, uConf, tConf))
 		// Launch gRPC server
-		log.Println("grpc:", *gRPCAddr)
-		errChan <- grpcServer.Serve(listener)
+		log.Println("grpc:", cfg.GRPC.Addr)
+		if err := grpcServer.Serve(listener); err != nil {
+			errChan <- err
+		}
 	}()
 
-	log.Fatal(<-errChan)
+	// Metrics endpoint
+	var metricsServer *http.Server
+	if cfg.GRPC.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", m.Handler())
+		// The levels are changeable without authentication so that the metrics address must not be exposed,
+		// which is the loopback by default
+		metricsMux.Handle("/debug/log-levels", logger.LevelsHandler(app.Levels))
+		metricsServer = &http.Server{
+			Addr:    cfg.GRPC.MetricsAddr,
+			Handler: metricsMux,
+		}
+		go func() {
+			log.Println("metrics:", cfg.GRPC.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != http.ErrServerClosed {
+				errChan <- err
+			}
+		}()
+	}
+
+	log.Println("shutting down:", <-errChan)
+	hc.Drain()
+
+	// GracefulStop waits for the running calls such as CreateParties to finish.
+	// Force stopping after the drain timeout.
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(cfg.GRPC.DrainTimeout):
+		log.Println("grpc shutdown: drain timeout exceeded")
+		grpcServer.Stop()
+	}
+	if metricsServer != nil {
+		// Scraping the last metrics isn't waited for
+		_ = metricsServer.Close()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		log.Println("tracing shutdown:", err)
+	}
+	cleanup()
 }
@@ -5,6 +5,7 @@
 package testmock
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	partyservice "github.com/momotaro98/mixlunch-service-api/partyservice"
 	reflect "reflect"
@@ -34,103 +35,117 @@ func (m *MockPartyServer) EXPECT() *MockPartyServerMockRecorder {
 }
 
 // GetParties mocks base method
-func (m *MockPartyServer) GetParties(beginDateTimeStr, endDateTimeStr string) (*partyservice.Parties, error) {
+func (m *MockPartyServer) GetParties(ctx context.Context, beginDateTimeStr, endDateTimeStr string) (*partyservice.Parties, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetParties", beginDateTimeStr, endDateTimeStr)
+	ret := m.ctrl.Call(m, "GetParties", ctx, beginDateTimeStr, endDateTimeStr)
 	ret0, _ := ret[0].(*partyservice.Parties)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetParties indicates an expected call of GetParties
-func (mr *MockPartyServerMockRecorder) GetParties(beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
+func (mr *MockPartyServerMockRecorder) GetParties(ctx, beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParties", reflect.TypeOf((*MockPartyServer)(nil).GetParties), beginDateTimeStr, endDateTimeStr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParties", reflect.TypeOf((*MockPartyServer)(nil).GetParties), ctx, beginDateTimeStr, endDateTimeStr)
 }
 
 // GetPartyByUserIdAndTimeRange mocks base method
-func (m *MockPartyServer) GetPartyByUserIdAndTimeRange(userId, beginDateTimeStr, endDateTimeStr string) (*partyservice.Parties, error) {
+func (m *MockPartyServer) GetPartyByUserIdAndTimeRange(ctx context.Context, userId, beginDateTimeStr, endDateTimeStr string) (*partyservice.Parties, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPartyByUserIdAndTimeRange", userId, beginDateTimeStr, endDateTimeStr)
+	ret := m.ctrl.Call(m, "GetPartyByUserIdAndTimeRange", ctx, userId, beginDateTimeStr, endDateTimeStr)
 	ret0, _ := ret[0].(*partyservice.Parties)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPartyByUserIdAndTimeRange indicates an expected call of GetPartyByUserIdAndTimeRange
-func (mr *MockPartyServerMockRecorder) GetPartyByUserIdAndTimeRange(userId, beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
+func (mr *MockPartyServerMockRecorder) GetPartyByUserIdAndTimeRange(ctx, userId, beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartyByUserIdAndTimeRange", reflect.TypeOf((*MockPartyServer)(nil).GetPartyByUserIdAndTimeRange), userId, beginDateTimeStr, endDateTimeStr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartyByUserIdAndTimeRange", reflect.TypeOf((*MockPartyServer)(nil).GetPartyByUserIdAndTimeRange), ctx, userId, beginDateTimeStr, endDateTimeStr)
 }
 
 // GetIsLatestPartyReviewDone mocks base method
-func (m *MockPartyServer) GetIsLatestPartyReviewDone(userId string) (*partyservice.IsLatestReviewDone, error) {
+func (m *MockPartyServer) GetIsLatestPartyReviewDone(ctx context.Context, userId string) (*partyservice.IsLatestReviewDone, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIsLatestPartyReviewDone", userId)
+	ret := m.ctrl.Call(m, "GetIsLatestPartyReviewDone", ctx, userId)
 	ret0, _ := ret[0].(*partyservice.IsLatestReviewDone)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetIsLatestPartyReviewDone indicates an expected call of GetIsLatestPartyReviewDone
-func (mr *MockPartyServerMockRecorder) GetIsLatestPartyReviewDone(userId interface{}) *gomock.Call {
+func (mr *MockPartyServerMockRecorder) GetIsLatestPartyReviewDone(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIsLatestPartyReviewDone", reflect.TypeOf((*MockPartyServer)(nil).GetIsLatestPartyReviewDone), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIsLatestPartyReviewDone", reflect.TypeOf((*MockPartyServer)(nil).GetIsLatestPartyReviewDone), ctx, userId)
 }
 
 // GetLastNPartiesOfAUser mocks base method
-func (m *MockPartyServer) GetLastNPartiesOfAUser(userId string, n int) (*partyservice.Parties, error) {
+func (m *MockPartyServer) GetLastNPartiesOfAUser(ctx context.Context, userId string, n int) (*partyservice.Parties, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLastNPartiesOfAUser", userId, n)
+	ret := m.ctrl.Call(m, "GetLastNPartiesOfAUser", ctx, userId, n)
 	ret0, _ := ret[0].(*partyservice.Parties)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLastNPartiesOfAUser indicates an expected call of GetLastNPartiesOfAUser
-func (mr *MockPartyServerMockRecorder) GetLastNPartiesOfAUser(userId, n interface{}) *gomock.Call {
+func (mr *MockPartyServerMockRecorder) GetLastNPartiesOfAUser(ctx, userId, n interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastNPartiesOfAUser", reflect.TypeOf((*MockPartyServer)(nil).GetLastNPartiesOfAUser), userId, n)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastNPartiesOfAUser", reflect.TypeOf((*MockPartyServer)(nil).GetLastNPartiesOfAUser), ctx, userId, n)
 }
 
 // PostPartyReviewMember mocks base method
-func (m *MockPartyServer) PostPartyReviewMember(reviewMember *partyservice.PartyReviewMember) error {
+func (m *MockPartyServer) PostPartyReviewMember(ctx context.Context, reviewMember *partyservice.PartyReviewMember) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PostPartyReviewMember", reviewMember)
+	ret := m.ctrl.Call(m, "PostPartyReviewMember", ctx, reviewMember)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PostPartyReviewMember indicates an expected call of PostPartyReviewMember
-func (mr *MockPartyServerMockRecorder) PostPartyReviewMember(reviewMember interface{}) *gomock.Call {
+func (mr *MockPartyServerMockRecorder) PostPartyReviewMember(ctx, reviewMember interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostPartyReviewMember", reflect.TypeOf((*MockPartyServer)(nil).PostPartyReviewMember), reviewMember)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostPartyReviewMember", reflect.TypeOf((*MockPartyServer)(nil).PostPartyReviewMember), ctx, reviewMember)
 }
 
 // UpsertParties mocks base method
-func (m *MockPartyServer) UpsertParties(partyModel []*partyservice.PartyForCommand) error {
+func (m *MockPartyServer) UpsertParties(ctx context.Context, partyModel []*partyservice.PartyForCommand) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpsertParties", partyModel)
+	ret := m.ctrl.Call(m, "UpsertParties", ctx, partyModel)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpsertParties indicates an expected call of UpsertParties
-func (mr *MockPartyServerMockRecorder) UpsertParties(partyModel interface{}) *gomock.Call {
+func (mr *MockPartyServerMockRecorder) UpsertParties(ctx, partyModel interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertParties", reflect.TypeOf((*MockPartyServer)(nil).UpsertParties), partyModel)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertParties", reflect.TypeOf((*MockPartyServer)(nil).UpsertParties), ctx, partyModel)
 }
 
 // GenerateChatRoom mocks base method
-func (m *MockPartyServer) GenerateChatRoom(chatRoomId string) error {
+func (m *MockPartyServer) GenerateChatRoom(ctx context.Context, chatRoomId string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateChatRoom", chatRoomId)
+	ret := m.ctrl.Call(m, "GenerateChatRoom", ctx, chatRoomId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // GenerateChatRoom indicates an expected call of GenerateChatRoom
-func (mr *MockPartyServerMockRecorder) GenerateChatRoom(chatRoomId interface{}) *gomock.Call {
+func (mr *MockPartyServerMockRecorder) GenerateChatRoom(ctx, chatRoomId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChatRoom", reflect.TypeOf((*MockPartyServer)(nil).GenerateChatRoom), chatRoomId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateChatRoom", reflect.TypeOf((*MockPartyServer)(nil).GenerateChatRoom), ctx, chatRoomId)
+}
+
+// AnonymizeChatRooms mocks base method
+func (m *MockPartyServer) AnonymizeChatRooms(ctx context.Context, userId string, chatRoomIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeChatRooms", ctx, userId, chatRoomIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeChatRooms indicates an expected call of AnonymizeChatRooms
+func (mr *MockPartyServerMockRecorder) AnonymizeChatRooms(ctx, userId, chatRoomIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeChatRooms", reflect.TypeOf((*MockPartyServer)(nil).AnonymizeChatRooms), ctx, userId, chatRoomIds)
 }
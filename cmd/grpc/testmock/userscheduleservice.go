@@ -5,6 +5,7 @@
 package testmock
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	userscheduleservice "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
 	reflect "reflect"
@@ -35,76 +36,76 @@ func (m *MockUserScheduleServer) EXPECT() *MockUserScheduleServerMockRecorder {
 }
 
 // GetUserSchedulesByTimeRange mocks base method
-func (m *MockUserScheduleServer) GetUserSchedulesByTimeRange(userId, beginDateTimeStr, endDateTimeStr string) (*userscheduleservice.UserSchedules, error) {
+func (m *MockUserScheduleServer) GetUserSchedulesByTimeRange(ctx context.Context, userId, beginDateTimeStr, endDateTimeStr string) (*userscheduleservice.UserSchedules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserSchedulesByTimeRange", userId, beginDateTimeStr, endDateTimeStr)
+	ret := m.ctrl.Call(m, "GetUserSchedulesByTimeRange", ctx, userId, beginDateTimeStr, endDateTimeStr)
 	ret0, _ := ret[0].(*userscheduleservice.UserSchedules)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserSchedulesByTimeRange indicates an expected call of GetUserSchedulesByTimeRange
-func (mr *MockUserScheduleServerMockRecorder) GetUserSchedulesByTimeRange(userId, beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
+func (mr *MockUserScheduleServerMockRecorder) GetUserSchedulesByTimeRange(ctx, userId, beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSchedulesByTimeRange", reflect.TypeOf((*MockUserScheduleServer)(nil).GetUserSchedulesByTimeRange), userId, beginDateTimeStr, endDateTimeStr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSchedulesByTimeRange", reflect.TypeOf((*MockUserScheduleServer)(nil).GetUserSchedulesByTimeRange), ctx, userId, beginDateTimeStr, endDateTimeStr)
 }
 
 // GetEachUserSchedules mocks base method
-func (m *MockUserScheduleServer) GetEachUserSchedules(beginDateTimeStr, endDateTimeStr string) ([]*userscheduleservice.UserSchedules, error) {
+func (m *MockUserScheduleServer) GetEachUserSchedules(ctx context.Context, beginDateTimeStr, endDateTimeStr string) ([]*userscheduleservice.UserSchedules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEachUserSchedules", beginDateTimeStr, endDateTimeStr)
+	ret := m.ctrl.Call(m, "GetEachUserSchedules", ctx, beginDateTimeStr, endDateTimeStr)
 	ret0, _ := ret[0].([]*userscheduleservice.UserSchedules)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEachUserSchedules indicates an expected call of GetEachUserSchedules
-func (mr *MockUserScheduleServerMockRecorder) GetEachUserSchedules(beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
+func (mr *MockUserScheduleServerMockRecorder) GetEachUserSchedules(ctx, beginDateTimeStr, endDateTimeStr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEachUserSchedules", reflect.TypeOf((*MockUserScheduleServer)(nil).GetEachUserSchedules), beginDateTimeStr, endDateTimeStr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEachUserSchedules", reflect.TypeOf((*MockUserScheduleServer)(nil).GetEachUserSchedules), ctx, beginDateTimeStr, endDateTimeStr)
 }
 
 // AddUserSchedule mocks base method
-func (m *MockUserScheduleServer) AddUserSchedule(userId string, usComm *userscheduleservice.UserScheduleForCommand) (*userscheduleservice.UserSchedules, error) {
+func (m *MockUserScheduleServer) AddUserSchedule(ctx context.Context, userId string, usComm *userscheduleservice.UserScheduleForCommand) (*userscheduleservice.UserSchedules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddUserSchedule", userId, usComm)
+	ret := m.ctrl.Call(m, "AddUserSchedule", ctx, userId, usComm)
 	ret0, _ := ret[0].(*userscheduleservice.UserSchedules)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddUserSchedule indicates an expected call of AddUserSchedule
-func (mr *MockUserScheduleServerMockRecorder) AddUserSchedule(userId, usComm interface{}) *gomock.Call {
+func (mr *MockUserScheduleServerMockRecorder) AddUserSchedule(ctx, userId, usComm interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserSchedule", reflect.TypeOf((*MockUserScheduleServer)(nil).AddUserSchedule), userId, usComm)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserSchedule", reflect.TypeOf((*MockUserScheduleServer)(nil).AddUserSchedule), ctx, userId, usComm)
 }
 
 // UpdateUserSchedule mocks base method
-func (m *MockUserScheduleServer) UpdateUserSchedule(userId string, usComm *userscheduleservice.UserScheduleForCommand) (*userscheduleservice.UserSchedules, error) {
+func (m *MockUserScheduleServer) UpdateUserSchedule(ctx context.Context, userId string, usComm *userscheduleservice.UserScheduleForCommand) (*userscheduleservice.UserSchedules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUserSchedule", userId, usComm)
+	ret := m.ctrl.Call(m, "UpdateUserSchedule", ctx, userId, usComm)
 	ret0, _ := ret[0].(*userscheduleservice.UserSchedules)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateUserSchedule indicates an expected call of UpdateUserSchedule
-func (mr *MockUserScheduleServerMockRecorder) UpdateUserSchedule(userId, usComm interface{}) *gomock.Call {
+func (mr *MockUserScheduleServerMockRecorder) UpdateUserSchedule(ctx, userId, usComm interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSchedule", reflect.TypeOf((*MockUserScheduleServer)(nil).UpdateUserSchedule), userId, usComm)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSchedule", reflect.TypeOf((*MockUserScheduleServer)(nil).UpdateUserSchedule), ctx, userId, usComm)
 }
 
 // DeleteUserSchedule mocks base method
-func (m *MockUserScheduleServer) DeleteUserSchedule(userId string, targetDate time.Time) (*userscheduleservice.UserSchedules, error) {
+func (m *MockUserScheduleServer) DeleteUserSchedule(ctx context.Context, userId string, targetDate time.Time) (*userscheduleservice.UserSchedules, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUserSchedule", userId, targetDate)
+	ret := m.ctrl.Call(m, "DeleteUserSchedule", ctx, userId, targetDate)
 	ret0, _ := ret[0].(*userscheduleservice.UserSchedules)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteUserSchedule indicates an expected call of DeleteUserSchedule
-func (mr *MockUserScheduleServerMockRecorder) DeleteUserSchedule(userId, targetDate interface{}) *gomock.Call {
+func (mr *MockUserScheduleServerMockRecorder) DeleteUserSchedule(ctx, userId, targetDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSchedule", reflect.TypeOf((*MockUserScheduleServer)(nil).DeleteUserSchedule), userId, targetDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSchedule", reflect.TypeOf((*MockUserScheduleServer)(nil).DeleteUserSchedule), ctx, userId, targetDate)
 }
@@ -6,14 +6,12 @@ package main
 import (
 	"github.com/google/wire"
 
+	"github.com/momotaro98/mixlunch-service-api/database"
 	"github.com/momotaro98/mixlunch-service-api/logger"
 	"github.com/momotaro98/mixlunch-service-api/partyservice"
-	"github.com/momotaro98/mixlunch-service-api/tagservice"
-	usService "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
-	"github.com/momotaro98/mixlunch-service-api/userservice"
 )
 
-func initializeGRPCServer(loggerConfig *logger.Config, usServiceDbConfig *usService.Config, partyServiceConfig *partyservice.Config, userServiceConfig *userservice.Config, tagServiceConfig *tagservice.Config) *gRPCMixLunchServer {
-	wire.Build(logger.SuperSet, gRPCSuperSet, provideGRPCMixLunchServer)
-	return nil
+func initializeApplication(loggerConfig *logger.Config, dbConfig *database.Config, partyServiceConfig *partyservice.Config) (*application, func(), error) {
+	wire.Build(logger.SuperSet, database.SuperSet, gRPCSuperSet, provideGRPCMixLunchServer, wire.Struct(new(application), "*"))
+	return nil, nil, nil
 }
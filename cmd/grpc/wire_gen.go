@@ -6,6 +6,7 @@
 package main
 
 import (
+	"github.com/momotaro98/mixlunch-service-api/database"
 	"github.com/momotaro98/mixlunch-service-api/logger"
 	"github.com/momotaro98/mixlunch-service-api/partyservice"
 	"github.com/momotaro98/mixlunch-service-api/tagservice"
@@ -15,25 +16,44 @@ import (
 
 // Injectors from wire.go:
 
-func initializeGRPCServer(loggerConfig *logger.Config, usServiceDbConfig *userscheduleservice.Config, partyServiceConfig *partyservice.Config, userServiceConfig *userservice.Config, tagServiceConfig *tagservice.Config) *gRPCMixLunchServer {
-	loggerLogger := logger.ProvideLogger(loggerConfig)
-	sqlDb := userscheduleservice.ProvideDB(usServiceDbConfig)
+func initializeApplication(loggerConfig *logger.Config, dbConfig *database.Config, partyServiceConfig *partyservice.Config) (*application, func(), error) {
+	db, cleanup, err := database.ProvideDB(dbConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	levels := logger.ProvideLevels(loggerConfig)
+	redactor, err := logger.ProvideRedactor(loggerConfig)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	loggerLogger := logger.ProvideLogger(levels, redactor)
+	sqlDb := userscheduleservice.ProvideDB(db)
 	iUserScheduleQueryRepository := userscheduleservice.ProvideUserScheduleRepository(sqlDb)
 	iUserScheduleCommandRepository := userscheduleservice.ProvideRealUserScheduleUpdateRepository(sqlDb)
-	tagserviceSqlDb := tagservice.ProvideDB(tagServiceConfig)
+	tagserviceSqlDb := tagservice.ProvideDB(db)
 	iTagQueryRepository := tagservice.ProvideTagQueryRepository(tagserviceSqlDb)
 	tagServer := tagservice.ProvideTagServer(iTagQueryRepository)
 	userScheduleServer := userscheduleservice.ProvideUserScheduleServer(iUserScheduleQueryRepository, iUserScheduleCommandRepository, tagServer)
-	partyserviceSqlDb := partyservice.ProvideDB(partyServiceConfig)
+	partyserviceSqlDb := partyservice.ProvideDB(db)
 	iPartyQueryRepository := partyservice.ProvidePartyQueryRepository(partyserviceSqlDb)
 	iPartyCommandRepository := partyservice.ProvidePartyCommandRepository(partyserviceSqlDb)
-	userserviceSqlDb := userservice.ProvideDB(userServiceConfig)
+	userserviceSqlDb := userservice.ProvideDB(db)
 	iUserQueryRepository := userservice.ProvideUserQueryRepository(userserviceSqlDb)
 	iUserCommandRepository := userservice.ProvideUserCommandRepository(userserviceSqlDb)
 	userServer := userservice.ProvideUserServer(iUserQueryRepository, tagServer, iUserCommandRepository)
-	app := partyservice.ProvideApp(partyServiceConfig)
+	app, cleanup2 := partyservice.ProvideApp(partyServiceConfig)
 	iChatRoomRepository := partyservice.ProvideChatRoomRepository(app)
 	partyServer := partyservice.ProvidePartyServer(iPartyQueryRepository, iPartyCommandRepository, userServer, tagServer, iChatRoomRepository)
 	mainGRPCMixLunchServer := provideGRPCMixLunchServer(loggerLogger, userScheduleServer, partyServer, userServer)
-	return mainGRPCMixLunchServer
+	mainApplication := &application{
+		DB:     db,
+		Logger: loggerLogger,
+		Levels: levels,
+		Server: mainGRPCMixLunchServer,
+	}
+	return mainApplication, func() {
+		cleanup2()
+		cleanup()
+	}, nil
 }
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/momotaro98/mixlunch-service-api/database"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+	"github.com/momotaro98/mixlunch-service-api/partyservice"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+// Config is the configuration of both the REST and the gRPC servers.
+// The values are loaded by Load in the order of defaults, file, environment variables and flags.
+type Config struct {
+	HTTP     HTTP     `yaml:"http"`
+	GRPC     GRPC     `yaml:"grpc"`
+	Log      Log      `yaml:"log"`
+	DB       DB       `yaml:"db"`
+	Firebase Firebase `yaml:"firebase"`
+	Auth     Auth     `yaml:"auth"`
+	Health   Health   `yaml:"health"`
+	Tracing  Tracing  `yaml:"tracing"`
+}
+
+type HTTP struct {
+	Addr           string        `yaml:"addr"`
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	DrainTimeout   time.Duration `yaml:"drain_timeout"`
+	// MetricsAddr is the HTTP listen address of /metrics, which is the loopback by default not to be public.
+	// Empty disables it.
+	MetricsAddr string `yaml:"metrics_addr"`
+}
+
+type GRPC struct {
+	Addr         string        `yaml:"addr"`
+	CallTimeout  time.Duration `yaml:"call_timeout"`
+	DrainTimeout time.Duration `yaml:"drain_timeout"`
+	// MetricsAddr is the HTTP listen address of /metrics and /debug/log-levels, which have no authentication.
+	// It's the loopback by default. Empty disables it.
+	MetricsAddr string   `yaml:"metrics_addr"`
+	Auth        GRPCAuth `yaml:"auth"`
+	TLS         GRPCTLS  `yaml:"tls"`
+}
+
+// GRPCAuth authenticates the service callers of the gRPC server.
+// The lists are comma separated "name:value" pairs to be given by environment variables and flags as well.
+type GRPCAuth struct {
+	Activate bool `yaml:"activate"`
+	// Tokens are the bearer tokens of the callers, e.g. "matcher:s3cr3t"
+	Tokens string `yaml:"tokens"`
+	// ServiceAccounts are the Google service accounts of the callers signing JWTs,
+	// e.g. "matcher:matcher@project.iam.gserviceaccount.com"
+	ServiceAccounts string `yaml:"service_accounts"`
+	// Audience is the aud claim the service account JWTs must have
+	Audience string `yaml:"audience"`
+	// Allow lists the callers allowed by method, e.g. "/pb.MixLunch/CreateParties:matcher|ops".
+	// The methods not listed are denied for all the callers.
+	Allow string `yaml:"allow"`
+}
+
+// GRPCTLS enables TLS with the certificate, and mTLS with the client CA as well
+type GRPCTLS struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"`
+}
+
+type Log struct {
+	Level string `yaml:"level"`
+	// Packages override the level by package, e.g. "partyservice:Debug,userservice:Warn"
+	Packages string `yaml:"packages"`
+	// Redact lists the rules masking the personal information, e.g. "email,token". Empty disables it.
+	Redact string `yaml:"redact"`
+}
+
+type DB struct {
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Database string `yaml:"database"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+type Firebase struct {
+	CredentialFile string `yaml:"credential_file"`
+	// ProjectID is the audience of the tokens. Empty reads it from CredentialFile.
+	ProjectID string `yaml:"project_id"`
+}
+
+type Auth struct {
+	Activate bool `yaml:"activate"`
+	// SessionLifetime is the lifetime of the session cookies, 5m to 336h (2 weeks)
+	SessionLifetime time.Duration `yaml:"session_lifetime"`
+	// SessionCookieSecure sends the session cookie only over HTTPS
+	SessionCookieSecure bool `yaml:"session_cookie_secure"`
+	// RevocationCacheTTL is how long the revocation of a user is cached.
+	// The revocations by other processes take up to it to be effective. 0 asks Firebase on every request.
+	RevocationCacheTTL time.Duration `yaml:"revocation_cache_ttl"`
+}
+
+type Health struct {
+	Timeout  time.Duration `yaml:"timeout"`
+	Firebase bool          `yaml:"firebase"`
+}
+
+// Tracing exports the spans of the requests, the service methods and the SQL statements
+type Tracing struct {
+	// Exporter is one of none, stdout and otlp
+	Exporter string `yaml:"exporter"`
+	// OTLPEndpoint is the base URL of the OTLP/HTTP collector, to which /v1/traces is posted
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	// SampleRatio is the ratio of the traces started by the server to be sampled, 0 to 1.
+	// The traces started by the callers follow their traceparent.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Default returns the config used when no source sets the values
+func Default() *Config {
+	return &Config{
+		HTTP: HTTP{
+			Addr:           ":5000",
+			RequestTimeout: 10 * time.Second,
+			DrainTimeout:   30 * time.Second,
+			MetricsAddr:    "127.0.0.1:5001",
+		},
+		GRPC: GRPC{
+			Addr:         ":8081",
+			CallTimeout:  time.Minute,
+			DrainTimeout: 2 * time.Minute,
+			MetricsAddr:  "127.0.0.1:8082",
+		},
+		Log: Log{
+			Level:  string(logger.Info),
+			Redact: strings.Join(logger.DefaultRedactRules, ","),
+		},
+		DB: DB{
+			Port:            "3306",
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 5 * time.Minute,
+			DialTimeout:     5 * time.Second,
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+		},
+		Firebase: Firebase{
+			CredentialFile: "./serviceAccount/serviceAccountKey.json",
+		},
+		Auth: Auth{
+			SessionLifetime:     5 * 24 * time.Hour,
+			SessionCookieSecure: true,
+			RevocationCacheTTL:  time.Minute,
+		},
+		Health: Health{
+			Timeout: 3 * time.Second,
+		},
+		Tracing: Tracing{
+			Exporter:     tracing.ExporterNone,
+			OTLPEndpoint: "http://localhost:4318",
+			SampleRatio:  1,
+		},
+	}
+}
+
+// Logger returns the config of the logger package
+func (c *Config) Logger() *logger.Config {
+	// The package levels have been validated
+	packages, _ := c.Log.PackageLevels()
+	return &logger.Config{
+		ErrorLevel:    logger.ErrorLevel(c.Log.Level),
+		PackageLevels: packages,
+		RedactRules:   c.Log.RedactRules(),
+	}
+}
+
+// RedactRules returns the rule names of Redact
+func (l Log) RedactRules() []string {
+	var rules []string
+	for _, name := range strings.Split(l.Redact, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			rules = append(rules, name)
+		}
+	}
+	return rules
+}
+
+// PackageLevels returns the levels of Packages by package
+func (l Log) PackageLevels() (map[string]logger.ErrorLevel, error) {
+	pairs, err := parsePairs(l.Packages)
+	if err != nil {
+		return nil, fmt.Errorf("log.packages: %v", err)
+	}
+	levels := make(map[string]logger.ErrorLevel, len(pairs))
+	for _, p := range pairs {
+		level, ok := logger.ParseLevel(p[1])
+		if !ok {
+			return nil, fmt.Errorf("log.packages: the level of %s must be one of Debug, Info, Warn and Error but %q", p[0], p[1])
+		}
+		levels[p[0]] = level
+	}
+	return levels, nil
+}
+
+// Database returns the config of the shared DB pool
+func (c *Config) Database() *database.Config {
+	return &database.Config{
+		User:     c.DB.User,
+		Password: c.DB.Password,
+		Host:     c.DB.Host,
+		Port:     c.DB.Port,
+		Database: c.DB.Database,
+
+		MaxOpenConns:    c.DB.MaxOpenConns,
+		MaxIdleConns:    c.DB.MaxIdleConns,
+		ConnMaxLifetime: c.DB.ConnMaxLifetime,
+
+		DialTimeout:  c.DB.DialTimeout,
+		ReadTimeout:  c.DB.ReadTimeout,
+		WriteTimeout: c.DB.WriteTimeout,
+	}
+}
+
+// Tracer returns the config of the tracer of the service, e.g. "mixlunch-api"
+func (c *Config) Tracer(serviceName string) *tracing.Config {
+	return &tracing.Config{
+		Exporter:     c.Tracing.Exporter,
+		OTLPEndpoint: c.Tracing.OTLPEndpoint,
+		SampleRatio:  c.Tracing.SampleRatio,
+		ServiceName:  serviceName,
+	}
+}
+
+// PartyService returns the config of the party service
+func (c *Config) PartyService() *partyservice.Config {
+	return &partyservice.Config{
+		AppCredentialFilePath: c.Firebase.CredentialFile,
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+func envOf(m map[string]string) LookupEnv {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func setup(t *testing.T) (dir string, credential string, teardown func()) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential = writeFile(t, dir, "serviceAccountKey.json", "{}")
+	return dir, credential, func() { os.RemoveAll(dir) }
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	dir, credential, teardown := setup(t)
+	defer teardown()
+
+	file := writeFile(t, dir, "config.yml", `
+http:
+  addr: ":6000"
+  request_timeout: 20s
+log:
+  level: warn
+db:
+  user: file-user
+  host: file-host
+  database: mixlunch
+  max_open_conns: 30
+firebase:
+  credential_file: `+credential+`
+`)
+	env := envOf(map[string]string{
+		"CONFIG_FILE":        file,
+		"DB_USER":            "env-user",
+		"DB_HOST":            "env-host",
+		"AUTH_ACTIVATE":      "ON",
+		"TRACE_SAMPLE_RATIO": "0.25",
+	})
+	args := []string{"-db-host", "flag-host", "-health-firebase"}
+
+	cfg, err := Load("test", args, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Default
+	if cfg.GRPC.Addr != ":8081" {
+		t.Errorf("grpc.addr: expected the default, actual %s", cfg.GRPC.Addr)
+	}
+	// File over default
+	if cfg.HTTP.Addr != ":6000" || cfg.HTTP.RequestTimeout != 20*time.Second || cfg.DB.MaxOpenConns != 30 {
+		t.Errorf("unexpected values from file %+v %+v", cfg.HTTP, cfg.DB)
+	}
+	// Env over file
+	if cfg.DB.User != "env-user" {
+		t.Errorf("db.user: expected env-user, actual %s", cfg.DB.User)
+	}
+	if !cfg.Auth.Activate {
+		t.Error("auth.activate: expected true by AUTH_ACTIVATE=ON")
+	}
+	if cfg.Tracing.SampleRatio != 0.25 {
+		t.Errorf("tracing.sample_ratio: expected 0.25, actual %v", cfg.Tracing.SampleRatio)
+	}
+	// Flag over env
+	if cfg.DB.Host != "flag-host" {
+		t.Errorf("db.host: expected flag-host, actual %s", cfg.DB.Host)
+	}
+	if !cfg.Health.Firebase {
+		t.Error("health.firebase: expected true by the flag")
+	}
+	// Normalized
+	if cfg.Logger().ErrorLevel != logger.Warn {
+		t.Errorf("log.level: expected Warn, actual %s", cfg.Logger().ErrorLevel)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir, credential, teardown := setup(t)
+	defer teardown()
+
+	valid := map[string]string{
+		"DB_USER":                  "user",
+		"DB_HOST":                  "host",
+		"DB_DATABASE":              "mixlunch",
+		"FIREBASE_CREDENTIAL_FILE": credential,
+	}
+	with := func(k, v string) map[string]string {
+		m := make(map[string]string, len(valid)+1)
+		for key, value := range valid {
+			m[key] = value
+		}
+		m[k] = v
+		return m
+	}
+
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		expected string
+	}{
+		{name: "valid", env: valid},
+		{name: "missing db", env: map[string]string{"FIREBASE_CREDENTIAL_FILE": credential}, expected: "db.user (DB_USER) is required"},
+		{name: "log level", env: with("LOG_LEVEL", "verbose"), expected: "log.level must be one of"},
+		{name: "log redact", env: with("LOG_REDACT", "email,phone"), expected: "log.redact: unknown rule \"phone\""},
+		{name: "log packages", env: with("LOG_PACKAGES", "partyservice:verbose"), expected: "the level of partyservice must be one of"},
+		{name: "idle over open", env: with("DB_MAX_IDLE_CONNS", "50"), expected: "must not exceed"},
+		{name: "credential file", env: with("FIREBASE_CREDENTIAL_FILE", filepath.Join(dir, "none.json")), expected: "firebase.credential_file is not readable"},
+		{name: "env type", env: with("DB_MAX_OPEN_CONNS", "many"), expected: "env DB_MAX_OPEN_CONNS"},
+		{name: "flag type", env: valid, args: []string{"-request-timeout", "10"}, expected: "flag -request-timeout"},
+		{name: "unknown key", env: with("CONFIG_FILE", writeFile(t, dir, "typo.yml", "htp:\n  addr: \":5000\"\n")), expected: "field htp not found"},
+		{name: "grpc auth without callers", env: with("GRPC_AUTH_ACTIVATE", "ON"), expected: "grpc.auth.tokens or grpc.auth.service_accounts is required"},
+		{name: "grpc auth tokens", env: with("GRPC_AUTH_TOKENS", "matcher"), args: []string{"-grpc-auth"}, expected: "\"matcher\" is not name:value"},
+		{name: "grpc auth allow", env: with("GRPC_AUTH_TOKENS", "matcher:s3cr3t"), args: []string{"-grpc-auth", "-grpc-auth-allow", "CreateParties:matcher"}, expected: "is not a full method name"},
+		{name: "session lifetime", env: with("AUTH_SESSION_LIFETIME", "720h"), expected: "auth.session_lifetime must be"},
+		{name: "trace exporter", env: with("TRACE_EXPORTER", "jaeger"), expected: "tracing.exporter must be one of none, stdout, otlp"},
+		{name: "trace sample ratio", env: with("TRACE_SAMPLE_RATIO", "1.5"), expected: "tracing.sample_ratio must be 0 to 1"},
+		{name: "trace otlp endpoint", env: with("TRACE_EXPORTER", "otlp"), args: []string{"-trace-otlp-endpoint", "localhost:4318"}, expected: "tracing.otlp_endpoint must be a URL"},
+		{name: "grpc tls key", env: with("GRPC_TLS_CERT_FILE", "cert.pem"), expected: "must be given together"},
+	}
+	for _, tc := range testCases {
+		_, err := Load("test", tc.args, envOf(tc.env))
+		if tc.expected == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s: expected error containing %q, actual %v", tc.name, tc.expected, err)
+		}
+	}
+}
+
+func TestGRPCAuth_Lists(t *testing.T) {
+	a := GRPCAuth{
+		Tokens:          "matcher:s3:cr3t, ops:t0ken",
+		ServiceAccounts: "matcher:matcher@mixlunch.iam.gserviceaccount.com",
+		Allow:           "/pb.MixLunch/CreateParties:matcher,/pb.MixLunch/GetParties:matcher|ops",
+	}
+
+	tokens, err := a.TokenCallers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"s3:cr3t": "matcher", "t0ken": "ops"}; !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("tokens: expected %v, actual %v", expected, tokens)
+	}
+	accounts, err := a.ServiceAccountCallers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"matcher@mixlunch.iam.gserviceaccount.com": "matcher"}; !reflect.DeepEqual(accounts, expected) {
+		t.Errorf("service accounts: expected %v, actual %v", expected, accounts)
+	}
+	allow, err := a.AllowedCallers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"/pb.MixLunch/CreateParties": {"matcher"},
+		"/pb.MixLunch/GetParties":    {"matcher", "ops"},
+	}
+	if !reflect.DeepEqual(allow, expected) {
+		t.Errorf("allow: expected %v, actual %v", expected, allow)
+	}
+
+	if _, err := (GRPCAuth{Tokens: "matcher:same,ops:same"}).TokenCallers(); err == nil {
+		t.Errorf("shared token: expected error")
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenCallers returns the callers by bearer token
+func (a GRPCAuth) TokenCallers() (map[string]string, error) {
+	pairs, err := parsePairs(a.Tokens)
+	if err != nil {
+		return nil, fmt.Errorf("grpc.auth.tokens: %v", err)
+	}
+	ret := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		if _, ok := ret[p[1]]; ok {
+			return nil, fmt.Errorf("grpc.auth.tokens: the token of %s is shared with another caller", p[0])
+		}
+		ret[p[1]] = p[0]
+	}
+	return ret, nil
+}
+
+// ServiceAccountCallers returns the callers by service account email
+func (a GRPCAuth) ServiceAccountCallers() (map[string]string, error) {
+	pairs, err := parsePairs(a.ServiceAccounts)
+	if err != nil {
+		return nil, fmt.Errorf("grpc.auth.service_accounts: %v", err)
+	}
+	ret := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		ret[p[1]] = p[0]
+	}
+	return ret, nil
+}
+
+// AllowedCallers returns the allowed callers by full method name
+func (a GRPCAuth) AllowedCallers() (map[string][]string, error) {
+	pairs, err := parsePairs(a.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("grpc.auth.allow: %v", err)
+	}
+	ret := make(map[string][]string, len(pairs))
+	for _, p := range pairs {
+		if !strings.HasPrefix(p[0], "/") {
+			return nil, fmt.Errorf("grpc.auth.allow: %q is not a full method name like /pb.MixLunch/GetParties", p[0])
+		}
+		ret[p[0]] = append(ret[p[0]], strings.Split(p[1], "|")...)
+	}
+	return ret, nil
+}
+
+// parsePairs parses "name:value,name:value". The value may contain ':'.
+func parsePairs(s string) ([][2]string, error) {
+	var pairs [][2]string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.Index(item, ":")
+		if i <= 0 || i == len(item)-1 {
+			return nil, fmt.Errorf("%q is not name:value", item)
+		}
+		pairs = append(pairs, [2]string{item[:i], item[i+1:]})
+	}
+	return pairs, nil
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LookupEnv looks up an environment variable, which is os.LookupEnv except in tests
+type LookupEnv func(key string) (string, bool)
+
+// option binds a config value to its flag and environment variable
+type option struct {
+	flag  string
+	env   string
+	usage string
+	ptr   func(c *Config) interface{}
+}
+
+var options = []option{
+	{"http", "HTTP_ADDR", "http listen address", func(c *Config) interface{} { return &c.HTTP.Addr }},
+	{"request-timeout", "HTTP_REQUEST_TIMEOUT", "timeout of each API request", func(c *Config) interface{} { return &c.HTTP.RequestTimeout }},
+	{"http-drain-timeout", "HTTP_DRAIN_TIMEOUT", "time to wait for in-flight requests on shutdown", func(c *Config) interface{} { return &c.HTTP.DrainTimeout }},
+	{"http-metrics", "HTTP_METRICS_ADDR", "internal http listen address of /metrics of the REST server, empty to disable", func(c *Config) interface{} { return &c.HTTP.MetricsAddr }},
+
+	{"grpc", "GRPC_ADDR", "gRPC listen address", func(c *Config) interface{} { return &c.GRPC.Addr }},
+	{"call-timeout", "GRPC_CALL_TIMEOUT", "timeout of each gRPC call", func(c *Config) interface{} { return &c.GRPC.CallTimeout }},
+	{"grpc-drain-timeout", "GRPC_DRAIN_TIMEOUT", "time to wait for in-flight calls on shutdown", func(c *Config) interface{} { return &c.GRPC.DrainTimeout }},
+	{"grpc-metrics", "GRPC_METRICS_ADDR", "http listen address of /metrics of the gRPC server, empty to disable", func(c *Config) interface{} { return &c.GRPC.MetricsAddr }},
+	{"grpc-auth", "GRPC_AUTH_ACTIVATE", "authenticate the gRPC callers, ON or OFF", func(c *Config) interface{} { return &c.GRPC.Auth.Activate }},
+	{"grpc-auth-tokens", "GRPC_AUTH_TOKENS", "bearer tokens of the gRPC callers as caller:token,...", func(c *Config) interface{} { return &c.GRPC.Auth.Tokens }},
+	{"grpc-auth-service-accounts", "GRPC_AUTH_SERVICE_ACCOUNTS", "service accounts of the gRPC callers as caller:email,...", func(c *Config) interface{} { return &c.GRPC.Auth.ServiceAccounts }},
+	{"grpc-auth-audience", "GRPC_AUTH_AUDIENCE", "audience of the service account JWTs", func(c *Config) interface{} { return &c.GRPC.Auth.Audience }},
+	{"grpc-auth-allow", "GRPC_AUTH_ALLOW", "allowed callers by gRPC method as method:caller|caller,...", func(c *Config) interface{} { return &c.GRPC.Auth.Allow }},
+	{"grpc-tls-cert", "GRPC_TLS_CERT_FILE", "TLS certificate file of the gRPC server", func(c *Config) interface{} { return &c.GRPC.TLS.CertFile }},
+	{"grpc-tls-key", "GRPC_TLS_KEY_FILE", "TLS key file of the gRPC server", func(c *Config) interface{} { return &c.GRPC.TLS.KeyFile }},
+	{"grpc-tls-client-ca", "GRPC_TLS_CLIENT_CA_FILE", "CA file verifying the gRPC client certificates for mTLS", func(c *Config) interface{} { return &c.GRPC.TLS.ClientCAFile }},
+
+	{"log-level", "LOG_LEVEL", "log level, one of Debug, Info, Warn and Error", func(c *Config) interface{} { return &c.Log.Level }},
+	{"log-packages", "LOG_PACKAGES", "log levels by package as package:level,...", func(c *Config) interface{} { return &c.Log.Packages }},
+	{"log-redact", "LOG_REDACT", "rules masking the personal information in the logs as rule,..., empty to disable", func(c *Config) interface{} { return &c.Log.Redact }},
+
+	{"db-user", "DB_USER", "DB user", func(c *Config) interface{} { return &c.DB.User }},
+	{"db-pass", "DB_PASS", "DB password", func(c *Config) interface{} { return &c.DB.Password }},
+	{"db-host", "DB_HOST", "DB host", func(c *Config) interface{} { return &c.DB.Host }},
+	{"db-port", "DB_PORT", "DB port", func(c *Config) interface{} { return &c.DB.Port }},
+	{"db-database", "DB_DATABASE", "DB database name", func(c *Config) interface{} { return &c.DB.Database }},
+	{"db-max-open-conns", "DB_MAX_OPEN_CONNS", "max number of open DB connections", func(c *Config) interface{} { return &c.DB.MaxOpenConns }},
+	{"db-max-idle-conns", "DB_MAX_IDLE_CONNS", "max number of idle DB connections", func(c *Config) interface{} { return &c.DB.MaxIdleConns }},
+	{"db-conn-max-lifetime", "DB_CONN_MAX_LIFETIME", "max lifetime of a DB connection", func(c *Config) interface{} { return &c.DB.ConnMaxLifetime }},
+	{"db-dial-timeout", "DB_DIAL_TIMEOUT", "timeout of connecting to DB", func(c *Config) interface{} { return &c.DB.DialTimeout }},
+	{"db-read-timeout", "DB_READ_TIMEOUT", "I/O read timeout of DB", func(c *Config) interface{} { return &c.DB.ReadTimeout }},
+	{"db-write-timeout", "DB_WRITE_TIMEOUT", "I/O write timeout of DB", func(c *Config) interface{} { return &c.DB.WriteTimeout }},
+
+	{"firebase-credential-file", "FIREBASE_CREDENTIAL_FILE", "service account key file of Firebase", func(c *Config) interface{} { return &c.Firebase.CredentialFile }},
+	{"firebase-project-id", "FIREBASE_PROJECT_ID", "Firebase project ID verifying tokens, empty to read it from the credential file", func(c *Config) interface{} { return &c.Firebase.ProjectID }},
+	{"auth", "AUTH_ACTIVATE", "activate the authentication, ON or OFF", func(c *Config) interface{} { return &c.Auth.Activate }},
+	{"session-lifetime", "AUTH_SESSION_LIFETIME", "lifetime of the session cookies, 5m to 336h", func(c *Config) interface{} { return &c.Auth.SessionLifetime }},
+	{"session-cookie-secure", "AUTH_SESSION_COOKIE_SECURE", "send the session cookie only over HTTPS, ON or OFF", func(c *Config) interface{} { return &c.Auth.SessionCookieSecure }},
+	{"revocation-cache-ttl", "AUTH_REVOCATION_CACHE_TTL", "how long the token revocation of a user is cached, 0 to ask Firebase every time", func(c *Config) interface{} { return &c.Auth.RevocationCacheTTL }},
+
+	{"health-timeout", "HEALTH_TIMEOUT", "timeout of each dependency check of readiness", func(c *Config) interface{} { return &c.Health.Timeout }},
+	{"health-firebase", "HEALTH_FIREBASE", "check Firebase reachability in readiness", func(c *Config) interface{} { return &c.Health.Firebase }},
+
+	{"trace-exporter", "TRACE_EXPORTER", "exporter of the trace spans, one of none, stdout and otlp", func(c *Config) interface{} { return &c.Tracing.Exporter }},
+	{"trace-otlp-endpoint", "TRACE_OTLP_ENDPOINT", "base URL of the OTLP/HTTP collector", func(c *Config) interface{} { return &c.Tracing.OTLPEndpoint }},
+	{"trace-sample-ratio", "TRACE_SAMPLE_RATIO", "ratio of the new traces to be sampled, 0 to 1", func(c *Config) interface{} { return &c.Tracing.SampleRatio }},
+}
+
+const (
+	configFlag = "config"
+	configEnv  = "CONFIG_FILE"
+)
+
+// Load loads the config with the precedence of flags > environment variables > file > defaults.
+// The file is given by the -config flag or the CONFIG_FILE environment variable.
+func Load(name string, args []string, lookupEnv LookupEnv) (*Config, error) {
+	c := Default()
+
+	// Flags are parsed first to know the file but applied last
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String(configFlag, "", "config file in YAML (env "+configEnv+")")
+	flags := make(map[string]*rawValue, len(options))
+	for _, o := range options {
+		v := &rawValue{def: format(o.ptr(c)), isBool: isBool(o.ptr(c))}
+		flags[o.flag] = v
+		fs.Var(v, o.flag, fmt.Sprintf("%s (env %s)", o.usage, o.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// File
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv(configEnv)
+	}
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: %v", err)
+		}
+		if err := yaml.UnmarshalStrict(b, c); err != nil {
+			return nil, fmt.Errorf("config: %s: %v", path, err)
+		}
+	}
+
+	// Environment variables
+	for _, o := range options {
+		s, ok := lookupEnv(o.env)
+		if !ok {
+			continue
+		}
+		if err := set(o.ptr(c), s); err != nil {
+			return nil, fmt.Errorf("config: env %s: %v", o.env, err)
+		}
+	}
+
+	// Flags
+	for _, o := range options {
+		v := flags[o.flag]
+		if !v.set {
+			continue
+		}
+		if err := set(o.ptr(c), v.value); err != nil {
+			return nil, fmt.Errorf("config: flag -%s: %v", o.flag, err)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// rawValue keeps the flag as it is given to apply it after the file and the environment variables
+type rawValue struct {
+	def    string
+	value  string
+	set    bool
+	isBool bool
+}
+
+func (v *rawValue) String() string {
+	if v == nil {
+		return ""
+	}
+	if v.set {
+		return v.value
+	}
+	return v.def
+}
+
+func (v *rawValue) Set(s string) error {
+	v.value = s
+	v.set = true
+	return nil
+}
+
+func (v *rawValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+func isBool(ptr interface{}) bool {
+	_, ok := ptr.(*bool)
+	return ok
+}
+
+func format(ptr interface{}) string {
+	switch p := ptr.(type) {
+	case *string:
+		return *p
+	case *int:
+		return strconv.Itoa(*p)
+	case *float64:
+		return strconv.FormatFloat(*p, 'g', -1, 64)
+	case *bool:
+		return strconv.FormatBool(*p)
+	case *time.Duration:
+		return p.String()
+	}
+	return ""
+}
+
+func set(ptr interface{}, s string) error {
+	switch p := ptr.(type) {
+	case *string:
+		*p = s
+	case *int:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		*p = i
+	case *float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		*p = f
+	case *bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		*p = b
+	case *time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		*p = d
+	default:
+		return fmt.Errorf("unsupported type %T", ptr)
+	}
+	return nil
+}
+
+// parseBool accepts ON, OFF and empty in addition to strconv.ParseBool as AUTH_ACTIVATE has used them
+func parseBool(s string) (bool, error) {
+	switch strings.ToUpper(s) {
+	case "ON":
+		return true, nil
+	case "OFF", "":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q", s)
+	}
+	return b, nil
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/momotaro98/mixlunch-service-api/auth"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+// ValidationError lists all the invalid values so that they can be fixed at once
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "config: invalid values: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the required values and normalizes the log level
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.HTTP.Addr == "" {
+		add("http.addr is required")
+	}
+	if c.GRPC.Addr == "" {
+		add("grpc.addr is required")
+	}
+
+	level, ok := logger.ParseLevel(c.Log.Level)
+	if !ok {
+		add("log.level must be one of Debug, Info, Warn and Error but %q", c.Log.Level)
+	}
+	c.Log.Level = string(level)
+	if _, err := c.Log.PackageLevels(); err != nil {
+		add("%v", err)
+	}
+	for _, name := range c.Log.RedactRules() {
+		if _, ok := logger.Rules[name]; !ok {
+			add("log.redact: unknown rule %q, which must be one of %s", name, strings.Join(logger.DefaultRedactRules, ", "))
+		}
+	}
+
+	required := []struct {
+		name  string
+		value string
+	}{
+		{"db.user (DB_USER)", c.DB.User},
+		{"db.host (DB_HOST)", c.DB.Host},
+		{"db.port (DB_PORT)", c.DB.Port},
+		{"db.database (DB_DATABASE)", c.DB.Database},
+		{"firebase.credential_file", c.Firebase.CredentialFile},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			add("%s is required", r.name)
+		}
+	}
+	if c.DB.MaxOpenConns < 0 || c.DB.MaxIdleConns < 0 {
+		add("db.max_open_conns and db.max_idle_conns must not be negative")
+	}
+	if c.DB.MaxOpenConns > 0 && c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		add("db.max_idle_conns (%d) must not exceed db.max_open_conns (%d)", c.DB.MaxIdleConns, c.DB.MaxOpenConns)
+	}
+
+	if c.Firebase.CredentialFile != "" {
+		if _, err := os.Stat(c.Firebase.CredentialFile); err != nil {
+			add("firebase.credential_file is not readable: %v", err)
+		}
+	}
+
+	if c.Auth.SessionLifetime < auth.MinSessionLifetime || c.Auth.SessionLifetime > auth.MaxSessionLifetime {
+		add("auth.session_lifetime must be %s to %s but %s", auth.MinSessionLifetime, auth.MaxSessionLifetime, c.Auth.SessionLifetime)
+	}
+	if c.Auth.RevocationCacheTTL < 0 {
+		add("auth.revocation_cache_ttl must not be negative")
+	}
+
+	if c.GRPC.Auth.Activate {
+		tokens, err := c.GRPC.Auth.TokenCallers()
+		if err != nil {
+			add("%v", err)
+		}
+		accounts, err := c.GRPC.Auth.ServiceAccountCallers()
+		if err != nil {
+			add("%v", err)
+		}
+		if err == nil && len(accounts) > 0 && c.GRPC.Auth.Audience == "" {
+			add("grpc.auth.audience is required for grpc.auth.service_accounts")
+		}
+		if len(tokens) == 0 && len(accounts) == 0 {
+			add("grpc.auth.tokens or grpc.auth.service_accounts is required to activate grpc.auth")
+		}
+		if _, err := c.GRPC.Auth.AllowedCallers(); err != nil {
+			add("%v", err)
+		}
+	}
+	if (c.GRPC.TLS.CertFile == "") != (c.GRPC.TLS.KeyFile == "") {
+		add("grpc.tls.cert_file and grpc.tls.key_file must be given together")
+	}
+	if c.GRPC.TLS.ClientCAFile != "" && c.GRPC.TLS.CertFile == "" {
+		add("grpc.tls.client_ca_file needs grpc.tls.cert_file")
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout:
+	case tracing.ExporterOTLP:
+		if u, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			add("tracing.otlp_endpoint must be a URL like http://localhost:4318 but %q", c.Tracing.OTLPEndpoint)
+		}
+	default:
+		add("tracing.exporter must be one of %s but %q", strings.Join(tracing.Exporters, ", "), c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio must be 0 to 1 but %v", c.Tracing.SampleRatio)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
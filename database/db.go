@@ -0,0 +1,88 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Config is the config of the DB pool shared by all the services
+type Config struct {
+	User     string
+	Password string
+	Host     string
+	Port     string
+	Database string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// DSN returns the data source name for go-sql-driver/mysql
+func (c *Config) DSN() string {
+	m := mysql.NewConfig()
+	m.User = c.User
+	m.Passwd = c.Password
+	m.Net = "tcp"
+	m.Addr = net.JoinHostPort(c.Host, c.Port)
+	m.DBName = c.Database
+	m.ParseTime = true
+	m.Params = map[string]string{"charset": "utf8mb4"}
+	m.Timeout = c.DialTimeout
+	m.ReadTimeout = c.ReadTimeout
+	m.WriteTimeout = c.WriteTimeout
+	return m.FormatDSN()
+}
+
+// ProvideDB opens the DB pool, whose statements are traced, and returns it with a cleanup function closing it
+func ProvideDB(cfg *Config) (*sql.DB, func(), error) {
+	db := sql.OpenDB(&tracedConnector{dsn: cfg.DSN(), driver: mysql.MySQLDriver{}})
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	return db, func() {
+		_ = db.Close()
+	}, nil
+}
+
+// Stats is the statistics of the DB pool
+type Stats struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDurationMs     float64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64   `json:"max_idle_closed"`
+	MaxLifetimeClosed  int64   `json:"max_lifetime_closed"`
+}
+
+func NewStats(s sql.DBStats) *Stats {
+	return &Stats{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDurationMs:     float64(s.WaitDuration.Microseconds()) / 1000,
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
+
+// StatsHandler responds the statistics of the DB pool for monitoring
+func StatsHandler(db *sql.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(NewStats(db.Stats()))
+	})
+}
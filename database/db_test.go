@@ -0,0 +1,44 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestConfig_DSN(t *testing.T) {
+	cfg := &Config{
+		User:         "mixlunch",
+		Password:     "p@ss",
+		Host:         "db",
+		Port:         "3306",
+		Database:     "mixlunch",
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	parsed, err := mysql.ParseDSN(cfg.DSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.User != cfg.User || parsed.Passwd != cfg.Password {
+		t.Errorf("unexpected credential %s:%s", parsed.User, parsed.Passwd)
+	}
+	if parsed.Addr != "db:3306" {
+		t.Errorf("expected db:3306, actual %s", parsed.Addr)
+	}
+	if parsed.DBName != cfg.Database {
+		t.Errorf("expected %s, actual %s", cfg.Database, parsed.DBName)
+	}
+	if !parsed.ParseTime {
+		t.Error("parseTime must be enabled")
+	}
+	if parsed.Params["charset"] != "utf8mb4" {
+		t.Errorf("expected utf8mb4, actual %s", parsed.Params["charset"])
+	}
+	if parsed.Timeout != cfg.DialTimeout || parsed.ReadTimeout != cfg.ReadTimeout || parsed.WriteTimeout != cfg.WriteTimeout {
+		t.Errorf("unexpected timeouts %v %v %v", parsed.Timeout, parsed.ReadTimeout, parsed.WriteTimeout)
+	}
+}
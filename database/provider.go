@@ -0,0 +1,9 @@
+package database
+
+import (
+	"github.com/google/wire"
+)
+
+var SuperSet = wire.NewSet(
+	ProvideDB,
+)
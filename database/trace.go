@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+// tracedConnector opens the connections making a span per SQL statement as the child of the span in the context.
+// The arguments of the statements aren't recorded not to export the personal information.
+type tracedConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c *tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn}, nil
+}
+
+func (c *tracedConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// startSQLSpan starts the span of the statement named by its first keyword, e.g. "SQL SELECT"
+func startSQLSpan(ctx context.Context, query string) *tracing.Span {
+	operation := strings.TrimSpace(query)
+	if i := strings.IndexAny(operation, " \t\n"); i >= 0 {
+		operation = operation[:i]
+	}
+	_, span := tracing.StartKind(ctx, "SQL "+strings.ToUpper(operation), tracing.Client)
+	span.SetAttribute("db.system", "mysql")
+	span.SetAttribute("db.statement", query)
+	return span
+}
+
+// endSQLSpan ends the span with the error. The span is dropped when the driver skips the call
+// to execute the statement in another way, which makes its own span.
+func endSQLSpan(span *tracing.Span, err error) {
+	if err == driver.ErrSkip {
+		return
+	}
+	span.RecordError(err)
+	span.End()
+}
+
+// tracedConn traces the statements of the connection of the driver.
+// The optional interfaces of database/sql/driver fall back as database/sql does when the driver lacks them.
+type tracedConn struct {
+	driver.Conn
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	span := startSQLSpan(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	endSQLSpan(span, err)
+	return rows, err
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	span := startSQLSpan(ctx, query)
+	result, err := execer.ExecContext(ctx, query, args)
+	endSQLSpan(span, err)
+	return result, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, query: query}, nil
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	if opts.ReadOnly || opts.Isolation != driver.IsolationLevel(0) {
+		return nil, errors.New("database: the driver doesn't support the transaction options")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	// The default conversion of database/sql
+	return driver.ErrSkip
+}
+
+// tracedStmt traces the executions of the prepared statement,
+// which go-sql-driver/mysql uses for the statements with arguments
+type tracedStmt struct {
+	driver.Stmt
+	query string
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	span := startSQLSpan(ctx, s.query)
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	endSQLSpan(span, err)
+	return rows, err
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	span := startSQLSpan(ctx, s.query)
+	var (
+		result driver.Result
+		err    error
+	)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	endSQLSpan(span, err)
+	return result, err
+}
+
+func (s *tracedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("database: the driver doesn't support the named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+// fakeDriver behaves like go-sql-driver/mysql, which skips the statements with arguments
+// in QueryContext and ExecContext of the connection to prepare them.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) > 0 {
+		return nil, driver.ErrSkip
+	}
+	return &fakeRows{}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) > 0 {
+		return nil, driver.ErrSkip
+	}
+	return nil, errors.New("table not found")
+}
+
+type fakeStmt struct{}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) { return &fakeRows{}, nil }
+
+type fakeRows struct{}
+
+func (r *fakeRows) Columns() []string              { return []string{"id"} }
+func (r *fakeRows) Close() error                   { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+func TestTracedConnector(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracing.SetTracer(tracing.NewTracer(exporter, 1))
+	defer tracing.SetTracer(nil)
+
+	db := sql.OpenDB(&tracedConnector{driver: fakeDriver{}})
+	defer db.Close()
+
+	ctx, parent := tracing.Start(context.Background(), "userservice.GetUserByUserId")
+	rows, err := db.QueryContext(ctx, "SELECT id FROM user WHERE user_id = ?", "uid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if _, err := db.ExecContext(ctx, "\n\tupdate user SET email = ?", "taro@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM nothing"); err == nil {
+		t.Fatal("expected the error of the driver")
+	}
+	parent.End()
+
+	spans := exporter.Spans()
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	// The skipped calls make no spans
+	if len(spans) != 4 {
+		t.Fatalf("expected 3 SQL spans and the parent, actual %v", names)
+	}
+	for _, s := range spans[:3] {
+		if s.ParentSpanID != parent.SpanContext().SpanID || s.Kind != tracing.Client {
+			t.Errorf("expected %s the client child of the service span", s.Name)
+		}
+	}
+	selectSpan := exporter.Span("SQL SELECT")
+	if selectSpan == nil || selectSpan.Attributes["db.statement"] != "SELECT id FROM user WHERE user_id = ?" {
+		t.Errorf("unexpected spans %v", names)
+	}
+	if exporter.Span("SQL UPDATE") == nil {
+		t.Errorf("expected the span named by the first keyword, actual %v", names)
+	}
+	if s := exporter.Span("SQL DELETE"); s == nil || s.Error != "table not found" {
+		t.Errorf("expected the error recorded, actual %+v", s)
+	}
+	for _, s := range spans {
+		for _, v := range s.Attributes {
+			if v == "uid1" || v == "taro@example.com" {
+				t.Errorf("the argument is recorded in %s", s.Name)
+			}
+		}
+	}
+}
@@ -192,6 +192,15 @@ CREATE TABLE IF NOT EXISTS userblocklists (
     CONSTRAINT userblocklists_ibfk_2 FOREIGN KEY(blockee) REFERENCES users(userId) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS userroles (
+    userId CHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+    role VARCHAR(20) CHARACTER SET utf8 NOT NULL,
+    createdAt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updatedAt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (userId),
+    CONSTRAINT userroles_ibfk_1 FOREIGN KEY(userId) REFERENCES users(userId) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS partymemberreviews (
     partyId INT NOT NULL,
     reviewer CHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
@@ -205,3 +214,21 @@ CREATE TABLE IF NOT EXISTS partymemberreviews (
     CONSTRAINT partymemberreviews_ibfk_2 FOREIGN KEY(reviewer) REFERENCES users(userId) ON DELETE CASCADE,
     CONSTRAINT partymemberreviews_ibfk_3 FOREIGN KEY(reviewee) REFERENCES users(userId) ON DELETE CASCADE
 );
+
+CREATE TABLE IF NOT EXISTS usererasures (
+    id INT NOT NULL AUTO_INCREMENT,
+    userId CHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL,
+    requestedBy CHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci,
+    erasedAt DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    INDEX usererasures_userId (userId)
+);
+
+CREATE TABLE IF NOT EXISTS usererasurechatrooms (
+    userErasureId INT NOT NULL,
+    chatRoomId CHAR(50) NOT NULL,
+    anonymizedAt DATETIME,
+    PRIMARY KEY (userErasureId, chatRoomId),
+    INDEX usererasurechatrooms_anonymizedAt (anonymizedAt),
+    CONSTRAINT usererasurechatrooms_ibfk_1 FOREIGN KEY(userErasureId) REFERENCES usererasures(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+
+BEGIN;
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+CREATE TABLE IF NOT EXISTS `userroles` (
+`userId` CHAR (50) CHARACTER SET `utf8mb4` COLLATE `utf8mb4_general_ci` NOT NULL,
+`role` VARCHAR (20) CHARACTER SET `utf8` NOT NULL,
+`createdAt` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+`updatedAt` DATETIME ON UPDATE CURRENT_TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (`userId`),
+INDEX `userroles_ibfk_1` (`userId`),
+CONSTRAINT `userroles_ibfk_1` FOREIGN KEY (`userId`) REFERENCES `users` (`userId`) ON DELETE CASCADE
+);
+
+SET FOREIGN_KEY_CHECKS = 1;
+
+COMMIT;
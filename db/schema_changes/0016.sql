@@ -0,0 +1,17 @@
+
+BEGIN;
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+CREATE TABLE IF NOT EXISTS `usererasures` (
+`id` INT (11) NOT NULL AUTO_INCREMENT,
+`userId` CHAR (50) CHARACTER SET `utf8mb4` COLLATE `utf8mb4_general_ci` NOT NULL,
+`requestedBy` CHAR (50) CHARACTER SET `utf8mb4` COLLATE `utf8mb4_general_ci`,
+`erasedAt` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+PRIMARY KEY (`id`),
+INDEX `usererasures_userId` (`userId`)
+);
+
+SET FOREIGN_KEY_CHECKS = 1;
+
+COMMIT;
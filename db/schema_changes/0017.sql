@@ -0,0 +1,16 @@
+BEGIN;
+
+SET FOREIGN_KEY_CHECKS = 0;
+
+CREATE TABLE IF NOT EXISTS `usererasurechatrooms` (
+`userErasureId` INT (11) NOT NULL,
+`chatRoomId` CHAR (50) NOT NULL,
+`anonymizedAt` DATETIME,
+PRIMARY KEY (`userErasureId`, `chatRoomId`),
+INDEX `usererasurechatrooms_anonymizedAt` (`anonymizedAt`),
+CONSTRAINT `usererasurechatrooms_ibfk_1` FOREIGN KEY (`userErasureId`) REFERENCES `usererasures` (`id`) ON DELETE CASCADE
+);
+
+SET FOREIGN_KEY_CHECKS = 1;
+
+COMMIT;
@@ -1,15 +1,72 @@
 package domainerror
 
 import (
-	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
 )
 
 const (
 	JSONParseErrorCode ErrorCode = iota + 1
 	NoneRequiredItemErrorCode
 	ValidationErrorCode
+	ForbiddenErrorCode
+	InsufficientRoleErrorCode
 )
 
+func init() {
+	Register(Definition{
+		Code:        JSONParseErrorCode,
+		Name:        "json_parse",
+		Description: "The request body is not valid JSON of the expected model.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: Messages{
+			English:  "Request '%s' failed. Error message: %v",
+			Japanese: "リクエスト '%s' を処理できませんでした。エラー: %v",
+		},
+	})
+	Register(Definition{
+		Code:        NoneRequiredItemErrorCode,
+		Name:        "none_required_item",
+		Description: "A required item of the request is missing.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: Messages{
+			English:  "'%s' is required",
+			Japanese: "'%s' は必須です",
+		},
+	})
+	Register(Definition{
+		Code:        ValidationErrorCode,
+		Name:        "validation",
+		Description: "The request has invalid fields, which are listed in \"errors\".",
+		HTTPStatus:  http.StatusUnprocessableEntity,
+		Messages: Messages{
+			English:  "Validation error: %+v",
+			Japanese: "入力内容に誤りがあります: %+v",
+		},
+	})
+	Register(Definition{
+		Code:        ForbiddenErrorCode,
+		Name:        "forbidden",
+		Description: "The caller is not the user owning the resource.",
+		HTTPStatus:  http.StatusForbidden,
+		Messages: Messages{
+			English:  "The user %s is not allowed to access the resource of the user %s",
+			Japanese: "ユーザー %s はユーザー %s のリソースにアクセスできません",
+		},
+	})
+	Register(Definition{
+		Code:        InsufficientRoleErrorCode,
+		Name:        "insufficient_role",
+		Description: "The caller doesn't have the role the endpoint requires.",
+		HTTPStatus:  http.StatusForbidden,
+		Messages: Messages{
+			English:  "The user %s needs the role %s",
+			Japanese: "ユーザー %s には %s のロールが必要です",
+		},
+	})
+}
+
 // JSONParseErrorCode
 
 type JSONParseError struct {
@@ -25,14 +82,21 @@ func NewJSONParseError(requestUrl string, err error) *JSONParseError {
 }
 
 func (e *JSONParseError) Error() string {
-	return fmt.Sprintf("Request '%s' failed. Error message: %v",
-		e.requestUrl, e.err)
+	return Message(e, English)
+}
+
+func (e *JSONParseError) MessageArgs() []interface{} {
+	return []interface{}{e.requestUrl, e.err}
 }
 
 func (e *JSONParseError) Code() ErrorCode {
 	return JSONParseErrorCode
 }
 
+func (e *JSONParseError) HTTPStatus() int {
+	return HTTPStatusOf(e.Code())
+}
+
 // NoneRequiredItemErrorCode
 
 type NoneRequiredItemError struct {
@@ -46,30 +110,119 @@ func NewNoneRequiredItemError(itemName string) *NoneRequiredItemError {
 }
 
 func (e *NoneRequiredItemError) Error() string {
-	return fmt.Sprintf(`'%s' is required`,
-		e.RequiredItemName)
+	return Message(e, English)
+}
+
+func (e *NoneRequiredItemError) MessageArgs() []interface{} {
+	return []interface{}{e.RequiredItemName}
 }
 
 func (e *NoneRequiredItemError) Code() ErrorCode {
 	return NoneRequiredItemErrorCode
 }
 
+func (e *NoneRequiredItemError) HTTPStatus() int {
+	return HTTPStatusOf(e.Code())
+}
+
 // ValidationErrorCode
 
 type ValidationError struct {
-	err error
+	err         error
+	fieldErrors []FieldError
 }
 
+// NewValidationError makes the error with the field errors when err is validator.ValidationErrors
 func NewValidationError(err error) *ValidationError {
-	return &ValidationError{
+	e := &ValidationError{
 		err: err,
 	}
+	if ves, ok := err.(validator.ValidationErrors); ok {
+		e.fieldErrors = make([]FieldError, 0, len(ves))
+		for _, fe := range ves {
+			e.fieldErrors = append(e.fieldErrors, newFieldError(fe))
+		}
+	}
+	return e
 }
 
 func (e *ValidationError) Error() string {
-	return fmt.Sprintf("Validation error: %+v", e.err)
+	return Message(e, English)
+}
+
+func (e *ValidationError) MessageArgs() []interface{} {
+	return []interface{}{e.err}
 }
 
 func (e *ValidationError) Code() ErrorCode {
 	return ValidationErrorCode
 }
+
+func (e *ValidationError) HTTPStatus() int {
+	return HTTPStatusOf(e.Code())
+}
+
+// FieldErrors returns the failed fields, which is empty when the error is not from the validator
+func (e *ValidationError) FieldErrors() []FieldError {
+	return e.fieldErrors
+}
+
+// ForbiddenErrorCode
+
+type ForbiddenError struct {
+	Caller string
+	Owner  string
+}
+
+func NewForbiddenError(caller, owner string) *ForbiddenError {
+	return &ForbiddenError{
+		Caller: caller,
+		Owner:  owner,
+	}
+}
+
+func (e *ForbiddenError) Error() string {
+	return Message(e, English)
+}
+
+func (e *ForbiddenError) MessageArgs() []interface{} {
+	return []interface{}{e.Caller, e.Owner}
+}
+
+func (e *ForbiddenError) Code() ErrorCode {
+	return ForbiddenErrorCode
+}
+
+func (e *ForbiddenError) HTTPStatus() int {
+	return HTTPStatusOf(e.Code())
+}
+
+// InsufficientRoleErrorCode
+
+type InsufficientRoleError struct {
+	Caller   string
+	Required string
+}
+
+func NewInsufficientRoleError(caller, required string) *InsufficientRoleError {
+	return &InsufficientRoleError{
+		Caller:   caller,
+		Required: required,
+	}
+}
+
+func (e *InsufficientRoleError) Error() string {
+	return Message(e, English)
+}
+
+func (e *InsufficientRoleError) MessageArgs() []interface{} {
+	return []interface{}{e.Caller, e.Required}
+}
+
+func (e *InsufficientRoleError) Code() ErrorCode {
+	return InsufficientRoleErrorCode
+}
+
+func (e *InsufficientRoleError) HTTPStatus() int {
+	return HTTPStatusOf(e.Code())
+}
@@ -1,6 +1,12 @@
 package domainerror
 
-// ErrorCode is a custom int type for DomainError
+// ErrorCode is a custom int type for DomainError.
+// Each package allocates its codes in its own range and registers them with Register.
+//
+//	1-99:    domainerror
+//	100-199: userscheduleservice
+//	200-299: partyservice
+//	300-399: userservice
 type ErrorCode int
 
 // DomainError is an error model for handling HTTP response layer.
@@ -8,4 +14,7 @@ type ErrorCode int
 type DomainError interface {
 	error
 	Code() ErrorCode
+	// HTTPStatus is the status code of the HTTP response for the error,
+	// which is HTTPStatusOf(Code()) to keep Definition the only source of it
+	HTTPStatus() int
 }
@@ -0,0 +1,85 @@
+package domainerror
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is a failed field of a ValidationError
+type FieldError struct {
+	// Field is the path of the field in JSON, e.g. "location.latitude" and "languages[0]"
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func newFieldError(fe validator.FieldError) FieldError {
+	field := fe.Namespace()
+	// Drop the name of the validated struct itself
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	return FieldError{
+		Field:   field,
+		Rule:    fe.Tag(),
+		Param:   fe.Param(),
+		Message: fieldErrorMessage(field, fe),
+	}
+}
+
+func fieldErrorMessage(field string, fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "url":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	case "datetime":
+		return fmt.Sprintf("%s must be in the format %s", field, param)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", field, param)
+	case "len":
+		return fmt.Sprintf("%s must be %s in length", field, param)
+	case "min", "gte":
+		if isSized(fe.Kind()) {
+			return fmt.Sprintf("%s must be at least %s in length", field, param)
+		}
+		return fmt.Sprintf("%s must be %s or greater", field, param)
+	case "max", "lte":
+		if isSized(fe.Kind()) {
+			return fmt.Sprintf("%s must be at most %s in length", field, param)
+		}
+		return fmt.Sprintf("%s must be %s or less", field, param)
+	}
+	if param != "" {
+		return fmt.Sprintf("%s failed on the '%s=%s' rule", field, fe.Tag(), param)
+	}
+	return fmt.Sprintf("%s failed on the '%s' rule", field, fe.Tag())
+}
+
+func isSized(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// JSONTagName names the fields of the validator errors by their json tags.
+// Each service registers it with validator.Validate.RegisterTagNameFunc.
+func JSONTagName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+package domainerror
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a language of the error messages in the form of Accept-Language, e.g. "ja"
+type Language string
+
+const (
+	English  Language = "en"
+	Japanese Language = "ja"
+
+	// FallbackLanguage is used when the client accepts none of Languages
+	FallbackLanguage = English
+)
+
+// Languages are the languages every error code must have the message of
+var Languages = []Language{English, Japanese}
+
+// Messages are the message formats of an error code by language.
+// The formats take the MessageArgs of the error.
+type Messages map[Language]string
+
+// MessageArgs is implemented by the errors whose messages take arguments
+type MessageArgs interface {
+	MessageArgs() []interface{}
+}
+
+// Message returns the message of the error in the language, or in FallbackLanguage if it doesn't have the language
+func Message(err DomainError, lang Language) string {
+	def, _ := Lookup(err.Code())
+	format, ok := def.Messages[lang]
+	if !ok {
+		format, ok = def.Messages[FallbackLanguage]
+	}
+	if !ok {
+		return fmt.Sprintf("error code %d", err.Code())
+	}
+	if a, ok := err.(MessageArgs); ok {
+		return fmt.Sprintf(format, a.MessageArgs()...)
+	}
+	return format
+}
+
+// ParseAcceptLanguage returns the most preferred language of Languages in the Accept-Language header,
+// or FallbackLanguage if none of them is accepted.
+func ParseAcceptLanguage(header string) Language {
+	type accepted struct {
+		lang Language
+		q    float64
+	}
+	var langs []accepted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		// "ja-JP" is taken as "ja"
+		if i := strings.Index(tag, "-"); i >= 0 {
+			tag = tag[:i]
+		}
+		langs = append(langs, accepted{Language(tag), q})
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	for _, a := range langs {
+		for _, lang := range Languages {
+			if a.lang == lang {
+				return lang
+			}
+		}
+	}
+	return FallbackLanguage
+}
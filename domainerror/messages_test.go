@@ -0,0 +1,84 @@
+package domainerror_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
+)
+
+var verb = regexp.MustCompile(`%[+#]?[a-z]`)
+
+func TestDefinitions_EveryCodeHasEveryLanguage(t *testing.T) {
+	for _, def := range domainerror.Definitions() {
+		code, messages := def.Code, def.Messages
+		for _, lang := range domainerror.Languages {
+			format, ok := messages[lang]
+			if !ok || format == "" {
+				t.Errorf("Test failed. The code %d has no message in %s", code, lang)
+				continue
+			}
+			// The translations take the same arguments as the fallback
+			expected := verb.FindAllString(messages[domainerror.FallbackLanguage], -1)
+			actual := verb.FindAllString(format, -1)
+			if len(expected) != len(actual) {
+				t.Errorf("Test failed. The code %d in %s. Expected verbs: %v, Actual: %v", code, lang, expected, actual)
+				continue
+			}
+			for i := range expected {
+				if expected[i] != actual[i] {
+					t.Errorf("Test failed. The code %d in %s. Expected verbs: %v, Actual: %v", code, lang, expected, actual)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	err := domainerror.NewNoneRequiredItemError("user_id")
+
+	testCases := []struct {
+		lang     domainerror.Language
+		expected string
+	}{
+		{domainerror.English, "'user_id' is required"},
+		{domainerror.Japanese, "'user_id' は必須です"},
+		{domainerror.Language("fr"), "'user_id' is required"},
+	}
+	for _, tc := range testCases {
+		if actual := domainerror.Message(err, tc.lang); actual != tc.expected {
+			t.Errorf("Test failed. lang: %s, Expected: %s, Actual: %s", tc.lang, tc.expected, actual)
+		}
+	}
+	if err.Error() != "'user_id' is required" {
+		t.Errorf("Test failed. Error() must be in English. Actual: %s", err.Error())
+	}
+	verr := domainerror.NewValidationError(errors.New("bad"))
+	if expected, actual := "入力内容に誤りがあります: bad", domainerror.Message(verr, domainerror.Japanese); actual != expected {
+		t.Errorf("Test failed. Expected: %s, Actual: %s", expected, actual)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected domainerror.Language
+	}{
+		{"", domainerror.FallbackLanguage},
+		{"ja", domainerror.Japanese},
+		{"ja-JP,ja;q=0.9,en;q=0.8", domainerror.Japanese},
+		{"en-US,en;q=0.9,ja;q=0.8", domainerror.English},
+		{"en;q=0.5, ja;q=0.8", domainerror.Japanese},
+		{"fr-CH, fr;q=0.9, ja;q=0.7", domainerror.Japanese},
+		{"fr, de", domainerror.FallbackLanguage},
+		{"ja;q=0, en", domainerror.English},
+		{"*", domainerror.FallbackLanguage},
+	}
+	for _, tc := range testCases {
+		if actual := domainerror.ParseAcceptLanguage(tc.header); actual != tc.expected {
+			t.Errorf("Test failed. header: %q, Expected: %s, Actual: %s", tc.header, tc.expected, actual)
+		}
+	}
+}
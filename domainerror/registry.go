@@ -0,0 +1,64 @@
+package domainerror
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// Definition describes an error code for the client developers
+type Definition struct {
+	Code ErrorCode `json:"code"`
+	// Name is the unique name of the code in snake case, e.g. "duplicate_review"
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	HTTPStatus  int    `json:"http_status"`
+	// Messages must have all of Languages
+	Messages Messages `json:"messages"`
+}
+
+var registry = map[ErrorCode]Definition{}
+
+// Register registers the definitions of error codes.
+// Each package registers its codes in init so that a duplicate code or name fails on startup.
+func Register(defs ...Definition) {
+	for _, def := range defs {
+		if def.Name == "" || def.HTTPStatus == 0 {
+			panic(fmt.Sprintf("domainerror: the code %d needs its name and HTTP status", def.Code))
+		}
+		if registered, ok := registry[def.Code]; ok {
+			panic(fmt.Sprintf("domainerror: the code %d of %s is already registered by %s", def.Code, def.Name, registered.Name))
+		}
+		for _, registered := range registry {
+			if registered.Name == def.Name {
+				panic(fmt.Sprintf("domainerror: the name %s of the code %d is already registered by the code %d", def.Name, def.Code, registered.Code))
+			}
+		}
+		registry[def.Code] = def
+	}
+}
+
+// Lookup returns the definition of the code
+func Lookup(code ErrorCode) (Definition, bool) {
+	def, ok := registry[code]
+	return def, ok
+}
+
+// HTTPStatusOf returns the HTTP status of the code in its definition, which is the only source of the status.
+// An unregistered code is an internal server error.
+func HTTPStatusOf(code ErrorCode) int {
+	if def, ok := registry[code]; ok {
+		return def.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// Definitions returns all the registered definitions in the order of the code
+func Definitions() []Definition {
+	defs := make([]Definition, 0, len(registry))
+	for _, def := range registry {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
+}
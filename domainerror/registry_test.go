@@ -0,0 +1,93 @@
+package domainerror_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
+	"github.com/momotaro98/mixlunch-service-api/partyservice"
+	usService "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
+	"github.com/momotaro98/mixlunch-service-api/userservice"
+)
+
+// samples are an error of every code of all the packages. A new error must be added here.
+var samples = []domainerror.DomainError{
+	domainerror.NewJSONParseError("/api/v1/user/register", errors.New("unexpected EOF")),
+	domainerror.NewNoneRequiredItemError("user_id"),
+	domainerror.NewValidationError(errors.New("invalid")),
+	domainerror.NewForbiddenError("caller", "owner"),
+	domainerror.NewInsufficientRoleError("caller", "admin"),
+
+	usService.NewInvalidDateTimeFormatError("2020-01-01"),
+	usService.NewFromIsAfterToError(time.Unix(1, 0), time.Unix(0, 0)),
+	usService.NewDifferentDayFromAndToError(time.Unix(0, 0), time.Unix(86400, 0)),
+	usService.NewDuplicateInOneDayError(time.Unix(0, 0)),
+	usService.NewTheScheduleNotFoundError(time.Unix(0, 0)),
+	usService.NewTimeRangeIsLessThanSpecifiedError(time.Unix(0, 0), time.Unix(60, 0)),
+
+	partyservice.NewInvalidDateTimeFormatError("2020-01-01"),
+	partyservice.NewDuplicateReviewError(1, "reviewer", "reviewee"),
+	partyservice.NewInconsistencyReviewError(1, "reviewer", "reviewee"),
+
+	userservice.NewDuplicateUserRegisterError("uid"),
+	userservice.NewDuplicateUserBlockRegisterError("blocker", "blockee"),
+	userservice.NewInconsistencyUserBlockError("blocker", "blockee"),
+	userservice.NewUserNotFoundError("uid"),
+	userservice.NewUnknownUserReferenceError("uid"),
+	userservice.NewUserBlockNotFoundError("blocker", "blockee"),
+}
+
+func TestRegistry_EveryErrorIsRegistered(t *testing.T) {
+	for _, err := range samples {
+		if _, ok := domainerror.Lookup(err.Code()); !ok {
+			t.Errorf("Test failed. The code %d of %T is not registered", err.Code(), err)
+		}
+	}
+	if expected, actual := len(samples), len(domainerror.Definitions()); expected != actual {
+		t.Errorf("Test failed. Expected: %d registered codes, Actual: %d", expected, actual)
+	}
+}
+
+func TestRegister_Duplicate_Panic(t *testing.T) {
+	testCases := []struct {
+		name string
+		def  domainerror.Definition
+	}{
+		{"Duplicate code", domainerror.Definition{Code: domainerror.ValidationErrorCode, Name: "new_name", HTTPStatus: http.StatusBadRequest}},
+		{"Duplicate name", domainerror.Definition{Code: 999, Name: "validation", HTTPStatus: http.StatusBadRequest}},
+		{"No name", domainerror.Definition{Code: 999, HTTPStatus: http.StatusBadRequest}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Test failed. Expected: panic, Actual: no panic")
+				}
+			}()
+			domainerror.Register(tc.def)
+		})
+	}
+	if _, ok := domainerror.Lookup(999); ok {
+		t.Errorf("Test failed. The invalid definition must not be registered")
+	}
+}
+
+func TestHTTPStatusOf(t *testing.T) {
+	if expected, actual := http.StatusUnprocessableEntity, domainerror.HTTPStatusOf(domainerror.ValidationErrorCode); expected != actual {
+		t.Errorf("Test failed. Expected: %d, Actual: %d", expected, actual)
+	}
+	if expected, actual := http.StatusInternalServerError, domainerror.HTTPStatusOf(999); expected != actual {
+		t.Errorf("Test failed. Unregistered code. Expected: %d, Actual: %d", expected, actual)
+	}
+}
+
+func TestDefinitions_OrderedByCode(t *testing.T) {
+	defs := domainerror.Definitions()
+	for i := 1; i < len(defs); i++ {
+		if defs[i-1].Code >= defs[i].Code {
+			t.Errorf("Test failed. %d is before %d", defs[i-1].Code, defs[i].Code)
+		}
+	}
+}
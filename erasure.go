@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/momotaro98/mixlunch-service-api/accountservice"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+// pendingChatRoomsInterval is the interval of retrying the chat rooms left pending by the erasures
+const pendingChatRoomsInterval = 10 * time.Minute
+
+// anonymizePendingChatRooms retries anonymizing the erased users in the pending chat rooms until ctx is done.
+// Anonymizing a chat room twice does nothing so that every instance of the server can run it.
+func anonymizePendingChatRooms(ctx context.Context, accounts accountservice.AccountServer, l logger.Logger) {
+	t := time.NewTicker(pendingChatRoomsInterval)
+	defer t.Stop()
+	for {
+		if err := accounts.AnonymizePendingChatRooms(ctx); err != nil {
+			l.WithError(err).LogContext(ctx, logger.Error, "anonymizing the pending chat rooms failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
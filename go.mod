@@ -1,6 +1,7 @@
 module github.com/momotaro98/mixlunch-service-api
 
 require (
+	cloud.google.com/go v0.39.0
 	firebase.google.com/go v3.7.0+incompatible
 	github.com/fullstorydev/grpcurl v1.4.0
 	github.com/go-playground/validator/v10 v10.2.0
@@ -13,12 +14,14 @@ require (
 	github.com/jmoiron/sqlx v1.2.0
 	github.com/momotaro98/stew v1.0.0
 	github.com/momotaro98/strictimportsort v1.1.0
+	github.com/prometheus/client_golang v1.5.1
 	github.com/sirupsen/logrus v1.5.0
 	golang.org/x/oauth2 v0.0.0-20190523182746-aaccbc9213b0 // indirect
 	golang.org/x/tools v0.0.0-20200502202811-ed308ab3e770
 	google.golang.org/api v0.5.0
 	google.golang.org/genproto v0.0.0-20190522204451-c2c4e71fbf69 // indirect
 	google.golang.org/grpc v1.22.0
+	gopkg.in/yaml.v2 v2.2.8
 )
 
 go 1.13
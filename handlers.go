@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/momotaro98/stew"
 
+	"github.com/momotaro98/mixlunch-service-api/accountservice"
+	"github.com/momotaro98/mixlunch-service-api/auth"
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
 	"github.com/momotaro98/mixlunch-service-api/logger"
 	"github.com/momotaro98/mixlunch-service-api/partyservice"
@@ -22,9 +27,14 @@ var (
 	XRequestId = "x-request-id"
 )
 
+// problemContentType is the media type of the problem details,
+// which the clients ask by Accept instead of the legacy ErrorResponse
+const problemContentType = "application/problem+json"
+
 type ErrorResponse struct {
-	Message string                `json:"message"`
-	Code    domainerror.ErrorCode `json:"code"`
+	Message string                   `json:"message"`
+	Code    domainerror.ErrorCode    `json:"code"`
+	Errors  []domainerror.FieldError `json:"errors,omitempty"`
 }
 
 func NewErrorResponse(message string, code domainerror.ErrorCode) *ErrorResponse {
@@ -34,10 +44,11 @@ func NewErrorResponse(message string, code domainerror.ErrorCode) *ErrorResponse
 	}
 }
 
-func assembleErrorResponse(domainError domainerror.DomainError) []byte {
-	message := domainError.Error()
+func assembleErrorResponse(domainError domainerror.DomainError, lang domainerror.Language) []byte {
+	message := domainerror.Message(domainError, lang)
 	code := domainError.Code()
 	errorResponse := NewErrorResponse(message, code)
+	errorResponse.Errors = fieldErrorsOf(domainError)
 	res, err := json.Marshal(errorResponse)
 	if err != nil {
 		panic(err)
@@ -45,84 +56,162 @@ func assembleErrorResponse(domainError domainerror.DomainError) []byte {
 	return res
 }
 
-func responseWithSuccess(l logger.Logger, reqId string, modelToMarshalToJSON interface{}, w http.ResponseWriter) {
+// Problem is the error response body of RFC 7807 with our error code
+type Problem struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail"`
+	Instance string                `json:"instance"`
+	Code     domainerror.ErrorCode `json:"code"`
+	// Errors lists the failed fields of a validation error
+	Errors []domainerror.FieldError `json:"errors,omitempty"`
+}
+
+func assembleProblem(domainError domainerror.DomainError, requestId string, lang domainerror.Language) []byte {
+	status := domainError.HTTPStatus()
+	problem := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   domainerror.Message(domainError, lang),
+		Instance: requestId,
+		Code:     domainError.Code(),
+		Errors:   fieldErrorsOf(domainError),
+	}
+	res, err := json.Marshal(problem)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func fieldErrorsOf(domainError domainerror.DomainError) []domainerror.FieldError {
+	if ve, ok := domainError.(*domainerror.ValidationError); ok {
+		return ve.FieldErrors()
+	}
+	return nil
+}
+
+// responseWithDomainError responds the domain error as ErrorResponse with legacyStatus,
+// or as a problem for the clients accepting application/problem+json.
+// The message is in the language of Accept-Language.
+func responseWithDomainError(w http.ResponseWriter, r *http.Request, l logger.Logger, domainErr domainerror.DomainError, legacyStatus int) {
+	ctx := r.Context()
+	setMetricsErrorCode(ctx, domainErr.Code())
+
+	lang := domainerror.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", string(lang))
+
+	w.Header().Add("Vary", "Accept")
+
+	var res []byte
+	if acceptsProblem(r.Header.Get("Accept")) {
+		w.Header().Set("Content-Type", problemContentType)
+		w.WriteHeader(domainErr.HTTPStatus())
+		res = assembleProblem(domainErr, logger.RequestIdFrom(ctx), lang)
+	} else {
+		w.WriteHeader(legacyStatus)
+		res = assembleErrorResponse(domainErr, lang)
+	}
+	if _, err := w.Write(res); err != nil {
+		l.WithError(err).LogContext(ctx, logger.Error, "writing the response failed")
+	}
+}
+
+// acceptsProblem reports whether the Accept header lists application/problem+json explicitly.
+// The wildcards don't count so that the existing clients keep getting ErrorResponse.
+func acceptsProblem(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil || mediaType != problemContentType {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func responseWithSuccess(ctx context.Context, l logger.Logger, modelToMarshalToJSON interface{}, w http.ResponseWriter) {
 	// Success case : JSON Marshal and return response to client
 	res, err := json.Marshal(modelToMarshalToJSON)
 	if err != nil {
-		l.Log(logger.Error, reqId, err.Error())
+		l.WithError(err).LogContext(ctx, logger.Error, "marshaling the response failed")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write(res); err != nil {
-		l.Log(logger.Error, reqId, err.Error())
-		panic(err)
+		// The client has gone so that nothing can be responded
+		l.WithError(err).LogContext(ctx, logger.Error, "writing the response failed")
 	}
 }
 
+// handleError responds the error of the service.
+// The clients opt in the HTTP status of each domain error by accepting application/problem+json.
+// The other clients keep getting ErrorResponse with 400 Bad Request for every domain error but the authorization ones,
+// which are 403 Forbidden, so that the existing clients telling the errors by the code don't break.
+// The other errors are 500 Internal Server Error for any client.
 func handleError(w http.ResponseWriter, r *http.Request, l logger.Logger, err error) {
-	var (
-		reqId = r.Header.Get(XRequestId)
-	)
+	ctx := r.Context()
 
 	var domainErr domainerror.DomainError
 
 	if errors.As(err, &domainErr) {
-		l.Log(logger.Warn, reqId, err.Error())
-		w.WriteHeader(http.StatusBadRequest)
-		if _, err := w.Write(assembleErrorResponse(domainErr)); err != nil {
-			panic(err)
+		l.WithError(err).LogContext(ctx, logger.Warn, "request rejected")
+		legacyStatus := http.StatusBadRequest
+		if domainErr.HTTPStatus() == http.StatusForbidden {
+			// The authorization errors have been added after the legacy format
+			legacyStatus = http.StatusForbidden
 		}
+		responseWithDomainError(w, r, l, domainErr, legacyStatus)
 		return
 	} else if err != nil {
-		l.Log(logger.Error, reqId, err.Error())
+		l.WithError(err).LogContext(ctx, logger.Error, "request failed")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
 
-func httpGetWrap(w http.ResponseWriter, r *http.Request, l logger.Logger, f func() (interface{}, error)) {
-	var (
-		reqId = r.Header.Get(XRequestId)
-	)
-	l.Log(logger.Info, reqId, fmt.Sprintf("Got GET request. URL: %s", r.URL.Path))
+// httpGetWrap responds the result of f to the GET or DELETE request without body
+func httpGetWrap(w http.ResponseWriter, r *http.Request, l logger.Logger, f func(ctx context.Context) (interface{}, error)) {
+	ctx := r.Context()
+	l.LogContext(ctx, logger.Debug, fmt.Sprintf("Got %s request. URL: %s", r.Method, r.URL.Path))
 
-	retFromService, err := f()
+	retFromService, err := f(ctx)
 	if err != nil {
 		handleError(w, r, l, err)
 		return
 	}
 
-	responseWithSuccess(l, reqId, retFromService, w)
+	responseWithSuccess(ctx, l, retFromService, w)
 }
 
-func httpPostWrap(w http.ResponseWriter, r *http.Request, l logger.Logger, decoding interface{}, f func(decoded interface{}) (interface{}, error)) {
-	var (
-		reqId = r.Header.Get(XRequestId)
-	)
-	l.Log(logger.Info, reqId, fmt.Sprintf("Got POST request. URL: %s", r.URL.Path))
+// httpPostWrap decodes the JSON body of the POST, PUT, PATCH or DELETE request into decoding and passes it to f
+func httpPostWrap(w http.ResponseWriter, r *http.Request, l logger.Logger, decoding interface{}, f func(ctx context.Context, decoded interface{}) (interface{}, error)) {
+	ctx := r.Context()
+	l.LogContext(ctx, logger.Debug, fmt.Sprintf("Got %s request. URL: %s", r.Method, r.URL.Path))
 
 	// Parse the request
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(decoding)
 	if err != nil {
-		l.Log(logger.Error, reqId, err.Error())
-		w.WriteHeader(http.StatusNotAcceptable)
-		jsonErr := domainerror.NewJSONParseError(r.URL.Path, err)
-		if _, err = w.Write(assembleErrorResponse(jsonErr)); err != nil {
-			panic(err)
-		}
+		l.WithError(err).LogContext(ctx, logger.Error, "decoding the request failed")
+		responseWithDomainError(w, r, l, domainerror.NewJSONParseError(r.URL.Path, err), http.StatusNotAcceptable)
 		return
 	}
 
-	retFromService, err := f(decoding)
+	retFromService, err := f(ctx, decoding)
 	if err != nil {
 		handleError(w, r, l, err)
 		return
 	}
 
-	responseWithSuccess(l, reqId, retFromService, w)
+	responseWithSuccess(ctx, l, retFromService, w)
 }
 
 type UserScheduleHandler struct {
@@ -132,7 +221,7 @@ type UserScheduleHandler struct {
 
 func provideUserScheduleHandler(logger logger.Logger, server usService.UserScheduleServer) *UserScheduleHandler {
 	return &UserScheduleHandler{
-		logger: logger,
+		logger: logger.Named("userscheduleservice"),
 		server: server,
 	}
 }
@@ -144,8 +233,8 @@ func (h *UserScheduleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		beginDateTime = params["beginDateTime"]
 		endDateTime   = params["endDateTime"]
 	)
-	httpGetWrap(w, r, h.logger, func() (interface{}, error) {
-		ret, err := h.server.GetUserSchedulesByTimeRange(uid, beginDateTime, endDateTime)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.GetUserSchedulesByTimeRange(ctx, uid, beginDateTime, endDateTime)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -160,7 +249,7 @@ type AddUserScheduleHandler struct {
 
 func provideAddUserScheduleHandler(logger logger.Logger, server usService.UserScheduleServer) *AddUserScheduleHandler {
 	return &AddUserScheduleHandler{
-		logger: logger,
+		logger: logger.Named("userscheduleservice"),
 		server: server,
 	}
 }
@@ -171,9 +260,9 @@ func (h *AddUserScheduleHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		uid    = params["uid"]
 	)
 	var addingUserSchedule usService.UserScheduleForCommand
-	httpPostWrap(w, r, h.logger, &addingUserSchedule, func(decoded interface{}) (interface{}, error) {
+	httpPostWrap(w, r, h.logger, &addingUserSchedule, func(ctx context.Context, decoded interface{}) (interface{}, error) {
 		us, _ := decoded.(*usService.UserScheduleForCommand)
-		ret, err := h.server.AddUserSchedule(uid, us)
+		ret, err := h.server.AddUserSchedule(ctx, uid, us)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -188,7 +277,7 @@ type UpdateUserScheduleHandler struct {
 
 func provideUpdateUserScheduleHandler(logger logger.Logger, server usService.UserScheduleServer) *UpdateUserScheduleHandler {
 	return &UpdateUserScheduleHandler{
-		logger: logger,
+		logger: logger.Named("userscheduleservice"),
 		server: server,
 	}
 }
@@ -199,9 +288,9 @@ func (h *UpdateUserScheduleHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		uid    = params["uid"]
 	)
 	var updatingUserSchedule usService.UserScheduleForCommand
-	httpPostWrap(w, r, h.logger, &updatingUserSchedule, func(decoded interface{}) (interface{}, error) {
+	httpPostWrap(w, r, h.logger, &updatingUserSchedule, func(ctx context.Context, decoded interface{}) (interface{}, error) {
 		us, _ := decoded.(*usService.UserScheduleForCommand)
-		ret, err := h.server.UpdateUserSchedule(uid, us)
+		ret, err := h.server.UpdateUserSchedule(ctx, uid, us)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -216,7 +305,7 @@ type DeleteUserScheduleHandler struct {
 
 func provideDeleteUserScheduleHandler(logger logger.Logger, server usService.UserScheduleServer) *DeleteUserScheduleHandler {
 	return &DeleteUserScheduleHandler{
-		logger: logger,
+		logger: logger.Named("userscheduleservice"),
 		server: server,
 	}
 }
@@ -227,9 +316,9 @@ func (h *DeleteUserScheduleHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		uid    = params["uid"]
 	)
 	var deletingDate usService.SpecifiedDate
-	httpPostWrap(w, r, h.logger, &deletingDate, func(decoded interface{}) (interface{}, error) {
+	httpPostWrap(w, r, h.logger, &deletingDate, func(ctx context.Context, decoded interface{}) (interface{}, error) {
 		sd, _ := decoded.(*usService.SpecifiedDate)
-		ret, err := h.server.DeleteUserSchedule(uid, sd.Date)
+		ret, err := h.server.DeleteUserSchedule(ctx, uid, sd.Date)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -244,7 +333,7 @@ type PartyHandler struct {
 
 func providePartyHandler(logger logger.Logger, server partyservice.PartyServer) *PartyHandler {
 	return &PartyHandler{
-		logger: logger,
+		logger: logger.Named("partyservice"),
 		server: server,
 	}
 }
@@ -256,8 +345,36 @@ func (h *PartyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		beginDateTime = params["beginDateTime"]
 		endDateTime   = params["endDateTime"]
 	)
-	httpGetWrap(w, r, h.logger, func() (interface{}, error) {
-		ret, err := h.server.GetPartyByUserIdAndTimeRange(uid, beginDateTime, endDateTime)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.GetPartyByUserIdAndTimeRange(ctx, uid, beginDateTime, endDateTime)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		return ret, nil
+	})
+}
+
+// AdminPartiesHandler lists the parties of all users in the time range for the operation
+type AdminPartiesHandler struct {
+	logger logger.Logger
+	server partyservice.PartyServer
+}
+
+func provideAdminPartiesHandler(logger logger.Logger, server partyservice.PartyServer) *AdminPartiesHandler {
+	return &AdminPartiesHandler{
+		logger: logger.Named("partyservice"),
+		server: server,
+	}
+}
+
+func (h *AdminPartiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		params        = mux.Vars(r)
+		beginDateTime = params["beginDateTime"]
+		endDateTime   = params["endDateTime"]
+	)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.GetParties(ctx, beginDateTime, endDateTime)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -272,16 +389,19 @@ type PartyReviewMemberHandler struct {
 
 func providePartyReviewMemberHandler(logger logger.Logger, server partyservice.PartyServer) *PartyReviewMemberHandler {
 	return &PartyReviewMemberHandler{
-		logger: logger,
+		logger: logger.Named("partyservice"),
 		server: server,
 	}
 }
 
 func (h *PartyReviewMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var newReviewMember partyservice.PartyReviewMember
-	httpPostWrap(w, r, h.logger, &newReviewMember, func(decoded interface{}) (interface{}, error) {
+	httpPostWrap(w, r, h.logger, &newReviewMember, func(ctx context.Context, decoded interface{}) (interface{}, error) {
 		reviewMember, _ := decoded.(*partyservice.PartyReviewMember)
-		err := h.server.PostPartyReviewMember(reviewMember)
+		if err := authorizeUser(ctx, reviewMember.Reviewer); err != nil {
+			return nil, err
+		}
+		err := h.server.PostPartyReviewMember(ctx, reviewMember)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -296,7 +416,7 @@ type PartyReviewMemberDoneHandler struct {
 
 func providePartyReviewMemberDoneHandler(logger logger.Logger, server partyservice.PartyServer) *PartyReviewMemberDoneHandler {
 	return &PartyReviewMemberDoneHandler{
-		logger: logger,
+		logger: logger.Named("partyservice"),
 		server: server,
 	}
 }
@@ -306,8 +426,8 @@ func (h *PartyReviewMemberDoneHandler) ServeHTTP(w http.ResponseWriter, r *http.
 		params   = mux.Vars(r)
 		reviewer = params["reviewer"]
 	)
-	httpGetWrap(w, r, h.logger, func() (interface{}, error) {
-		ret, err := h.server.GetIsLatestPartyReviewDone(reviewer)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.GetIsLatestPartyReviewDone(ctx, reviewer)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -322,7 +442,7 @@ type TagsHandler struct {
 
 func provideTagsHandler(logger logger.Logger, server tagservice.TagServer) *TagsHandler {
 	return &TagsHandler{
-		logger: logger,
+		logger: logger.Named("tagservice"),
 		server: server,
 	}
 }
@@ -340,8 +460,8 @@ func (h *TagsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		tagType = tagservice.TagType(tagTypeId)
 	}
 
-	httpGetWrap(w, r, h.logger, func() (interface{}, error) {
-		ret, err := h.server.GetTagsByTagType(tagType)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.GetTagsByTagType(ctx, tagType)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -356,7 +476,7 @@ type UserHandler struct {
 
 func provideUserHandler(logger logger.Logger, server userservice.UserServer) *UserHandler {
 	return &UserHandler{
-		logger: logger,
+		logger: logger.Named("userservice"),
 		server: server,
 	}
 }
@@ -366,8 +486,8 @@ func (h *UserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		params = mux.Vars(r)
 		uid    = params["uid"]
 	)
-	httpGetWrap(w, r, h.logger, func() (interface{}, error) {
-		ret, err := h.server.GetUserByUserId(uid)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.GetUserByUserId(ctx, uid)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -382,7 +502,7 @@ type UserPublicHandler struct {
 
 func provideUserPublicHandler(logger logger.Logger, server userservice.UserServer) *UserPublicHandler {
 	return &UserPublicHandler{
-		logger: logger,
+		logger: logger.Named("userservice"),
 		server: server,
 	}
 }
@@ -392,8 +512,8 @@ func (h *UserPublicHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		params = mux.Vars(r)
 		uid    = params["uid"]
 	)
-	httpGetWrap(w, r, h.logger, func() (interface{}, error) {
-		ret, err := h.server.GetUserPublicByUserId(uid)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.GetUserPublicByUserId(ctx, uid)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -408,16 +528,79 @@ type UserRegisterHandler struct {
 
 func provideUserRegisterHandler(logger logger.Logger, server userservice.UserServer) *UserRegisterHandler {
 	return &UserRegisterHandler{
-		logger: logger,
+		logger: logger.Named("userservice"),
 		server: server,
 	}
 }
 
 func (h *UserRegisterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var newUser userservice.UserForCommand
-	httpPostWrap(w, r, h.logger, &newUser, func(decoded interface{}) (interface{}, error) {
+	httpPostWrap(w, r, h.logger, &newUser, func(ctx context.Context, decoded interface{}) (interface{}, error) {
 		user, _ := decoded.(*userservice.UserForCommand)
-		ret, err := h.server.RegisterUser(user)
+		if err := authorizeUser(ctx, user.UserId); err != nil {
+			return nil, err
+		}
+		ret, err := h.server.RegisterUser(ctx, user)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		return ret, nil
+	})
+}
+
+// UserUpdateHandler replaces the user info of the path with the body
+type UserUpdateHandler struct {
+	logger logger.Logger
+	server userservice.UserServer
+}
+
+func provideUserUpdateHandler(logger logger.Logger, server userservice.UserServer) *UserUpdateHandler {
+	return &UserUpdateHandler{
+		logger: logger.Named("userservice"),
+		server: server,
+	}
+}
+
+func (h *UserUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		params = mux.Vars(r)
+		uid    = params["uid"]
+		user   userservice.UserForCommand
+	)
+	httpPostWrap(w, r, h.logger, &user, func(ctx context.Context, decoded interface{}) (interface{}, error) {
+		user, _ := decoded.(*userservice.UserForCommand)
+		// The user of the path is updated whatever user_id of the body is
+		user.UserId = uid
+		ret, err := h.server.UpdateUser(ctx, user)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		return ret, nil
+	})
+}
+
+// UserPatchHandler updates the fields of the user info of the path given by the body
+type UserPatchHandler struct {
+	logger logger.Logger
+	server userservice.UserServer
+}
+
+func provideUserPatchHandler(logger logger.Logger, server userservice.UserServer) *UserPatchHandler {
+	return &UserPatchHandler{
+		logger: logger.Named("userservice"),
+		server: server,
+	}
+}
+
+func (h *UserPatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		params = mux.Vars(r)
+		uid    = params["uid"]
+		update userservice.UserForUpdate
+	)
+	httpPostWrap(w, r, h.logger, &update, func(ctx context.Context, decoded interface{}) (interface{}, error) {
+		update, _ := decoded.(*userservice.UserForUpdate)
+		ret, err := h.server.PatchUser(ctx, uid, update)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -432,19 +615,185 @@ type UserBlockRegisterHandler struct {
 
 func provideUserBlockRegisterHandler(logger logger.Logger, server userservice.UserServer) *UserBlockRegisterHandler {
 	return &UserBlockRegisterHandler{
-		logger: logger,
+		logger: logger.Named("userservice"),
 		server: server,
 	}
 }
 
 func (h *UserBlockRegisterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var newUserBlock userservice.UserBlockForCommand
-	httpPostWrap(w, r, h.logger, &newUserBlock, func(decoded interface{}) (interface{}, error) {
+	httpPostWrap(w, r, h.logger, &newUserBlock, func(ctx context.Context, decoded interface{}) (interface{}, error) {
 		userBlock, _ := decoded.(*userservice.UserBlockForCommand)
-		ret, err := h.server.RegisterUserBlock(userBlock)
+		if err := authorizeUser(ctx, userBlock.Blocker); err != nil {
+			return nil, err
+		}
+		ret, err := h.server.RegisterUserBlock(ctx, userBlock)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		return ret, nil
+	})
+}
+
+// UserBlockDeleteHandler unblocks the blockee of the body
+type UserBlockDeleteHandler struct {
+	logger logger.Logger
+	server userservice.UserServer
+}
+
+func provideUserBlockDeleteHandler(logger logger.Logger, server userservice.UserServer) *UserBlockDeleteHandler {
+	return &UserBlockDeleteHandler{
+		logger: logger.Named("userservice"),
+		server: server,
+	}
+}
+
+func (h *UserBlockDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var userBlock userservice.UserBlockForCommand
+	httpPostWrap(w, r, h.logger, &userBlock, func(ctx context.Context, decoded interface{}) (interface{}, error) {
+		userBlock, _ := decoded.(*userservice.UserBlockForCommand)
+		if err := authorizeUser(ctx, userBlock.Blocker); err != nil {
+			return nil, err
+		}
+		ret, err := h.server.DeleteUserBlock(ctx, userBlock)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
 		return ret, nil
 	})
 }
+
+// defaultUserBlocksLimit is the number of the blocks in a page without the limit parameter
+const defaultUserBlocksLimit = 20
+
+// UserBlocksHandler lists the blocks of the user of the path by the offset and the limit query parameters
+type UserBlocksHandler struct {
+	logger logger.Logger
+	server userservice.UserServer
+}
+
+func provideUserBlocksHandler(logger logger.Logger, server userservice.UserServer) *UserBlocksHandler {
+	return &UserBlocksHandler{
+		logger: logger.Named("userservice"),
+		server: server,
+	}
+}
+
+func (h *UserBlocksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		params = mux.Vars(r)
+		uid    = params["uid"]
+	)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		offset, err := queryInt(r, "offset", 0)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := queryInt(r, "limit", defaultUserBlocksLimit)
+		if err != nil {
+			return nil, err
+		}
+		ret, err := h.server.GetUserBlocks(ctx, &userservice.UserBlockPageQuery{
+			Blocker: uid,
+			Offset:  offset,
+			Limit:   limit,
+		})
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		return ret, nil
+	})
+}
+
+// queryInt returns the integer query parameter, or def when it is absent
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, domainerror.NewValidationError(fmt.Errorf("'%s' must be an integer: %q", name, v))
+	}
+	return i, nil
+}
+
+// AccountExportHandler responds all the personal data of the user of the path as a JSON file
+type AccountExportHandler struct {
+	logger logger.Logger
+	server accountservice.AccountServer
+}
+
+func provideAccountExportHandler(logger logger.Logger, server accountservice.AccountServer) *AccountExportHandler {
+	return &AccountExportHandler{
+		logger: logger.Named("accountservice"),
+		server: server,
+	}
+}
+
+func (h *AccountExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		params = mux.Vars(r)
+		uid    = params["uid"]
+	)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		ret, err := h.server.ExportAccount(ctx, uid)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		// The path allows only alphanumeric user IDs
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="mixlunch-%s.json"`, uid))
+		return ret, nil
+	})
+}
+
+// AccountDeleteHandler erases the user of the path, and anonymizes the user in the messages of the chat rooms.
+// The user leaves the chat rooms by leaving the parties as the chat rooms have no members of their own.
+type AccountDeleteHandler struct {
+	logger logger.Logger
+	server accountservice.AccountServer
+}
+
+func provideAccountDeleteHandler(logger logger.Logger, server accountservice.AccountServer) *AccountDeleteHandler {
+	return &AccountDeleteHandler{
+		logger: logger.Named("accountservice"),
+		server: server,
+	}
+}
+
+func (h *AccountDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		params = mux.Vars(r)
+		uid    = params["uid"]
+	)
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		// The caller is recorded in the audit trail, which is unknown without the authentication
+		var requestedBy string
+		if token, ok := auth.TokenFrom(ctx); ok {
+			requestedBy = token.UID
+		}
+		ret, err := h.server.DeleteAccount(ctx, uid, requestedBy)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		h.logger.With("uid", uid).With("requested_by", requestedBy).LogContext(ctx, logger.Info, "user erased")
+		return ret, nil
+	})
+}
+
+// ErrorsHandler lists the error codes for the client developers
+type ErrorsHandler struct {
+	logger logger.Logger
+}
+
+func provideErrorsHandler(logger logger.Logger) *ErrorsHandler {
+	return &ErrorsHandler{
+		logger: logger.Named("domainerror"),
+	}
+}
+
+func (h *ErrorsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	httpGetWrap(w, r, h.logger, func(ctx context.Context) (interface{}, error) {
+		return domainerror.Definitions(), nil
+	})
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer implements the standard grpc.health.v1.Health service with the checks of Health.
+// The empty service name and the registered service names are served.
+type GRPCServer struct {
+	health        *Health
+	services      map[string]bool
+	watchInterval time.Duration
+}
+
+var _ healthpb.HealthServer = (*GRPCServer)(nil)
+
+func NewGRPCServer(h *Health, watchInterval time.Duration, services ...string) *GRPCServer {
+	s := &GRPCServer{
+		health:        h,
+		services:      map[string]bool{"": true},
+		watchInterval: watchInterval,
+	}
+	for _, name := range services {
+		s.services[name] = true
+	}
+	return s
+}
+
+func (s *GRPCServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !s.services[req.Service] {
+		return nil, status.Errorf(codes.NotFound, "unknown service: %s", req.Service)
+	}
+	return &healthpb.HealthCheckResponse{Status: s.status(ctx)}, nil
+}
+
+// Watch sends the status at first and then every time it changes.
+// The stream ends once draining so that GracefulStop doesn't wait for it.
+func (s *GRPCServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		// The spec of Watch requires SERVICE_UNKNOWN instead of the NotFound error and keeping the call
+		current := healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		if s.services[req.Service] {
+			current = s.status(ctx)
+		}
+		if current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+		if s.health.IsDraining() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *GRPCServer) status(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	if s.health.Check(ctx).Status != StatusOK {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
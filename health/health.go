@@ -0,0 +1,155 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/momotaro98/stew"
+)
+
+type Status string
+
+const (
+	StatusOK       = Status("ok")
+	StatusFail     = Status("fail")
+	StatusDraining = Status("draining")
+)
+
+// FirebaseURL is the endpoint of the public keys which Firebase Auth uses to verify session cookies
+const FirebaseURL = "https://www.googleapis.com/identitytoolkit/v3/relyingparty/publicKeys"
+
+// CheckFunc checks a dependency. It returns nil when the dependency is available.
+type CheckFunc func(ctx context.Context) error
+
+// Result is the result of a dependency check
+type Result struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the result of all dependency checks
+type Report struct {
+	Status  Status    `json:"status"`
+	Version string    `json:"version,omitempty"`
+	Checks  []*Result `json:"checks"`
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Health runs the registered dependency checks.
+// It reports not ready regardless of the checks once Drain is called.
+type Health struct {
+	version  string
+	timeout  time.Duration
+	checks   []check
+	draining int32
+}
+
+// New returns a Health. Each check is cancelled after the timeout.
+func New(version string, timeout time.Duration) *Health {
+	return &Health{
+		version: version,
+		timeout: timeout,
+	}
+}
+
+// Add registers a dependency check. It is not safe to call after the server started.
+func (h *Health) Add(name string, fn CheckFunc) {
+	h.checks = append(h.checks, check{name: name, fn: fn})
+}
+
+// Drain makes the readiness fail so that no new traffic is routed before shutting down.
+func (h *Health) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+func (h *Health) IsDraining() bool {
+	return atomic.LoadInt32(&h.draining) == 1
+}
+
+// Check runs all the checks concurrently and reports the results in the registered order.
+func (h *Health) Check(ctx context.Context) *Report {
+	report := &Report{
+		Status:  StatusOK,
+		Version: h.version,
+		Checks:  make([]*Result, len(h.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			report.Checks[i] = h.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, r := range report.Checks {
+		if r.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	if h.IsDraining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func (h *Health) run(ctx context.Context, c check) *Result {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := c.fn(ctx)
+	r := &Result{
+		Name:      c.name,
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		r.Status = StatusFail
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// PingDB checks the DB connection with a real round trip unlike sql.Open
+func PingDB(db *sql.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// Reachable checks that the URL responds without a server error.
+// It's used for the external services such as Firebase.
+func Reachable(client *http.Client, url string) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return stew.Wrap(err)
+		}
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return stew.Wrap(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+		return nil
+	}
+}
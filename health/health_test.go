@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func okCheck(ctx context.Context) error {
+	return nil
+}
+
+func failCheck(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func slowCheck(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHealth_Check(t *testing.T) {
+	testCases := []struct {
+		checks   map[string]CheckFunc
+		drain    bool
+		expected Status
+	}{
+		{
+			checks:   map[string]CheckFunc{"mysql": okCheck, "firebase": okCheck},
+			expected: StatusOK,
+		},
+		{
+			checks:   map[string]CheckFunc{"mysql": okCheck, "firebase": failCheck},
+			expected: StatusFail,
+		},
+		{
+			checks:   map[string]CheckFunc{"mysql": slowCheck},
+			expected: StatusFail,
+		},
+		{
+			checks:   map[string]CheckFunc{"mysql": okCheck},
+			drain:    true,
+			expected: StatusDraining,
+		},
+	}
+	for i, tc := range testCases {
+		h := New("v1", 10*time.Millisecond)
+		for name, fn := range tc.checks {
+			h.Add(name, fn)
+		}
+		if tc.drain {
+			h.Drain()
+		}
+		report := h.Check(context.Background())
+		if report.Status != tc.expected {
+			t.Errorf("case %d: expected %s, actual %s", i, tc.expected, report.Status)
+		}
+		if len(report.Checks) != len(tc.checks) {
+			t.Errorf("case %d: expected %d results, actual %d", i, len(tc.checks), len(report.Checks))
+		}
+		for _, r := range report.Checks {
+			if r.Status == StatusFail && r.Error == "" {
+				t.Errorf("case %d: error of %s is empty", i, r.Name)
+			}
+		}
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	testCases := []struct {
+		check    CheckFunc
+		drain    bool
+		expected int
+	}{
+		{check: okCheck, expected: http.StatusOK},
+		{check: failCheck, expected: http.StatusServiceUnavailable},
+		{check: okCheck, drain: true, expected: http.StatusServiceUnavailable},
+	}
+	for i, tc := range testCases {
+		h := New("v1", time.Second)
+		h.Add("mysql", tc.check)
+		if tc.drain {
+			h.Drain()
+		}
+		w := httptest.NewRecorder()
+		ReadinessHandler(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		if w.Code != tc.expected {
+			t.Errorf("case %d: expected %d, actual %d", i, tc.expected, w.Code)
+		}
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	h := New("v1", time.Second)
+	h.Add("mysql", failCheck)
+	w := httptest.NewRecorder()
+	LivenessHandler(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected %d, actual %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestGRPCServer_Check(t *testing.T) {
+	h := New("", time.Second)
+	h.Add("mysql", failCheck)
+	s := NewGRPCServer(h, time.Second, "pb.MixLunch")
+
+	for _, service := range []string{"", "pb.MixLunch"} {
+		resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("service %q: expected NOT_SERVING, actual %s", service, resp.Status)
+		}
+	}
+
+	_, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, actual %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// LivenessHandler responds 200 as long as the process can serve HTTP.
+// It doesn't check the dependencies not to restart the process because of their outage.
+func LivenessHandler(h *Health) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, &Report{
+			Status:  StatusOK,
+			Version: h.version,
+			Checks:  []*Result{},
+		})
+	})
+}
+
+// ReadinessHandler responds 200 when all the dependencies are available and otherwise 503.
+func ReadinessHandler(h *Health) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := h.Check(r.Context())
+		code := http.StatusOK
+		if report.Status != StatusOK {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	// The status code must be written before the body
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
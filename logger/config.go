@@ -1,5 +1,10 @@
 package logger
 
 type Config struct {
+	// ErrorLevel is the default level
 	ErrorLevel ErrorLevel
+	// PackageLevels override the level by package, e.g. Debug for "partyservice"
+	PackageLevels map[string]ErrorLevel
+	// RedactRules are the names of Rules masking the personal information in the logs
+	RedactRules []string
 }
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+type requestIdKey struct{}
+
+// WithRequestId returns the context holding the request ID which LogContext picks up
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, requestId)
+}
+
+// RequestIdFrom returns the request ID in the context or empty if none
+func RequestIdFrom(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey{}).(string)
+	return requestId
+}
+
+type uidKey struct{}
+
+// WithUID returns the context holding the authenticated user which LogContext picks up
+func WithUID(ctx context.Context, uid string) context.Context {
+	return context.WithValue(ctx, uidKey{}, uid)
+}
+
+// UIDFrom returns the authenticated user in the context or empty if none
+func UIDFrom(ctx context.Context) string {
+	uid, _ := ctx.Value(uidKey{}).(string)
+	return uid
+}
+
+type routeKey struct{}
+
+// WithRoute returns the context holding the route, e.g. the path template or the gRPC method,
+// which LogContext picks up
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// RouteFrom returns the route in the context or empty if none
+func RouteFrom(ctx context.Context) string {
+	route, _ := ctx.Value(routeKey{}).(string)
+	return route
+}
+
+// contextFields returns the fields of the values in the context.
+// The request ID is always there as it has been, and the others only when the context has them.
+func contextFields(ctx context.Context) Fields {
+	fields := Fields{reqIdKeyText: RequestIdFrom(ctx)}
+	if uid := UIDFrom(ctx); uid != "" {
+		fields[uidKeyText] = uid
+	}
+	if route := RouteFrom(ctx); route != "" {
+		fields[routeKeyText] = route
+	}
+	return fields
+}
+
+// NewRequestId generates a random UUID (version 4) for a request ID
+func NewRequestId() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// maxRequestIdLength bounds the request ID given by clients not to bloat the logs
+const maxRequestIdLength = 128
+
+// RequestIdOrNew returns the request ID given by a client as it is if it's valid
+// and otherwise a new one.
+func RequestIdOrNew(requestId string) string {
+	if !validRequestId(requestId) {
+		return NewRequestId()
+	}
+	return requestId
+}
+
+func validRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+	for i := 0; i < len(requestId); i++ {
+		// Visible ASCII characters only
+		if requestId[i] <= ' ' || requestId[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// levelsBody is the body of LevelsHandler
+type levelsBody struct {
+	Level    ErrorLevel            `json:"level,omitempty"`
+	Packages map[string]ErrorLevel `json:"packages"`
+}
+
+// LevelsHandler shows the levels with GET and changes them with PUT.
+// PUT changes the default level if it's given and the levels of the given packages,
+// where the empty level makes the package use the default level.
+// It must be only for the operators.
+func LevelsHandler(levels *Levels) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body levelsBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, fmt.Sprintf("invalid body: %v", err), http.StatusBadRequest)
+				return
+			}
+			if err := body.apply(levels); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		current := levels.Config()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&levelsBody{
+			Level:    current.ErrorLevel,
+			Packages: current.PackageLevels,
+		})
+	})
+}
+
+// apply validates all the levels first not to change them partially
+func (b *levelsBody) apply(levels *Levels) error {
+	level, ok := ParseLevel(string(b.Level))
+	if b.Level != "" && !ok {
+		return fmt.Errorf("level must be one of Debug, Info, Warn and Error but %q", b.Level)
+	}
+	packages := make(map[string]ErrorLevel, len(b.Packages))
+	for name, l := range b.Packages {
+		if name == "" {
+			return fmt.Errorf("package name is required")
+		}
+		parsed, ok := ParseLevel(string(l))
+		if l != "" && !ok {
+			return fmt.Errorf("the level of %s must be one of Debug, Info, Warn and Error but %q", name, l)
+		}
+		packages[name] = parsed
+	}
+
+	if b.Level != "" {
+		levels.SetLevel(level)
+	}
+	for name, l := range packages {
+		levels.SetPackageLevel(name, l)
+	}
+	return nil
+}
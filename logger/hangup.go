@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReloadOnHangup replaces the levels with the config given by load on every SIGHUP until ctx is done.
+// The levels are kept when load fails.
+func ReloadOnHangup(ctx context.Context, levels *Levels, load func() (*Config, error), l Logger) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	defer signal.Stop(c)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c:
+		}
+		conf, err := load()
+		if err != nil {
+			l.WithError(err).Log(Error, "", "reloading the log levels failed")
+			continue
+		}
+		levels.Replace(conf)
+		l.With("level", conf.ErrorLevel).With("packages", conf.PackageLevels).Log(Info, "", "log levels reloaded")
+	}
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// Levels are the levels of the loggers which can be changed at runtime.
+// The loggers made by Named use the level of the package if it's set, and the default level otherwise.
+type Levels struct {
+	mu       sync.RWMutex
+	level    ErrorLevel
+	packages map[string]ErrorLevel
+}
+
+func ProvideLevels(conf *Config) *Levels {
+	levels := &Levels{}
+	levels.Replace(conf)
+	return levels
+}
+
+// Replace replaces the default level and all the package levels with the config
+func (l *Levels) Replace(conf *Config) {
+	packages := make(map[string]ErrorLevel, len(conf.PackageLevels))
+	for name, level := range conf.PackageLevels {
+		packages[name] = level
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = conf.ErrorLevel
+	l.packages = packages
+}
+
+// Level returns the level of the package, or the default level for the empty name
+func (l *Levels) Level(name string) ErrorLevel {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if level, ok := l.packages[name]; ok {
+		return level
+	}
+	return l.level
+}
+
+// SetLevel sets the default level
+func (l *Levels) SetLevel(level ErrorLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetPackageLevel sets the level of the package. The empty level makes the package use the default level.
+func (l *Levels) SetPackageLevel(name string, level ErrorLevel) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level == "" {
+		delete(l.packages, name)
+		return
+	}
+	l.packages[name] = level
+}
+
+// Config returns the current levels as the config
+func (l *Levels) Config() *Config {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	packages := make(map[string]ErrorLevel, len(l.packages))
+	for name, level := range l.packages {
+		packages[name] = level
+	}
+	return &Config{
+		ErrorLevel:    l.level,
+		PackageLevels: packages,
+	}
+}
+
+// enabled returns whether the entry at the level is logged by the logger of the package
+func (l *Levels) enabled(name string, level ErrorLevel) bool {
+	return rank(level) >= rank(l.Level(name))
+}
+
+func rank(level ErrorLevel) int {
+	switch level {
+	case Debug:
+		return 0
+	case Warn:
+		return 2
+	case Error:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// ParseLevel returns the level case-insensitively, e.g. "debug" as Debug
+func ParseLevel(s string) (ErrorLevel, bool) {
+	for _, l := range []ErrorLevel{Debug, Info, Warn, Error} {
+		if strings.EqualFold(s, string(l)) {
+			return l, true
+		}
+	}
+	return ErrorLevel(s), false
+}
@@ -0,0 +1,93 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+func TestLevels_PackageOverridesDefault(t *testing.T) {
+	r := logger.NewRecorder()
+	r.Levels.Replace(&logger.Config{
+		ErrorLevel:    logger.Warn,
+		PackageLevels: map[string]logger.ErrorLevel{"partyservice": logger.Debug},
+	})
+	ctx := context.Background()
+
+	r.LogContext(ctx, logger.Info, "default info")
+	r.Named("partyservice").LogContext(ctx, logger.Debug, "party debug")
+	r.Named("userservice").LogContext(ctx, logger.Info, "user info")
+	r.Named("userservice").LogContext(ctx, logger.Warn, "user warn")
+
+	var messages []string
+	for _, e := range r.Entries() {
+		messages = append(messages, e.Message)
+	}
+	if expected := []string{"party debug", "user warn"}; !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected %v, actual %v", expected, messages)
+	}
+	if r.Entries()[0].Fields["package"] != "partyservice" {
+		t.Errorf("expected the package field, actual %+v", r.Entries()[0].Fields)
+	}
+
+	// Changed at runtime
+	r.Levels.SetPackageLevel("partyservice", "")
+	r.Named("partyservice").LogContext(ctx, logger.Debug, "party debug again")
+	if len(r.Entries()) != 2 {
+		t.Errorf("expected the package at the default level after reset, actual %+v", r.Entries())
+	}
+}
+
+func TestLevelsHandler(t *testing.T) {
+	levels := logger.ProvideLevels(&logger.Config{ErrorLevel: logger.Info})
+	h := logger.LevelsHandler(levels)
+
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expected       *logger.Config
+	}{
+		{
+			name:           "package",
+			body:           `{"packages": {"partyservice": "debug"}}`,
+			expectedStatus: http.StatusOK,
+			expected:       &logger.Config{ErrorLevel: logger.Info, PackageLevels: map[string]logger.ErrorLevel{"partyservice": logger.Debug}},
+		},
+		{
+			name:           "invalid level changes nothing",
+			body:           `{"level": "Warn", "packages": {"userservice": "verbose"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       &logger.Config{ErrorLevel: logger.Info, PackageLevels: map[string]logger.ErrorLevel{"partyservice": logger.Debug}},
+		},
+		{
+			name:           "default and reset",
+			body:           `{"level": "Error", "packages": {"partyservice": ""}}`,
+			expectedStatus: http.StatusOK,
+			expected:       &logger.Config{ErrorLevel: logger.Error, PackageLevels: map[string]logger.ErrorLevel{}},
+		},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log-levels", strings.NewReader(tc.body)))
+		if w.Code != tc.expectedStatus {
+			t.Errorf("%s: expected %d, actual %d %s", tc.name, tc.expectedStatus, w.Code, w.Body)
+		}
+		if actual := levels.Config(); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected %+v, actual %+v", tc.name, tc.expected, actual)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/log-levels", nil))
+	var body map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil || body["level"] != "Error" {
+		t.Errorf("GET: unexpected body %v, %v", body, err)
+	}
+}
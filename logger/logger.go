@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 
 	log "github.com/sirupsen/logrus"
@@ -17,11 +18,29 @@ const (
 
 type Logger interface {
 	Log(errorLevel ErrorLevel, requestId, message string)
+	// LogContext logs with the request ID, the UID and the route in the context
+	LogContext(ctx context.Context, errorLevel ErrorLevel, message string)
+	// LogFields logs a structured entry with the values in the context
+	LogFields(ctx context.Context, errorLevel ErrorLevel, message string, fields Fields)
+	// With returns the logger adding the field to all the entries
+	With(key string, value interface{}) Logger
+	// WithError returns the logger adding the error to all the entries,
+	// and the stack trace of its stew.Wrap calls to the entries at Error level.
+	WithError(err error) Logger
+	// Named returns the logger of the package, which logs at the level of the package in Levels
+	// and adds the package to all the entries.
+	Named(name string) Logger
 }
 
-func ProvideLogger(conf *Config) Logger {
-	l := log.StandardLogger()
-	// logrus default New function of StandardLogger
+// Fields are the structured values of a log entry
+type Fields map[string]interface{}
+
+// ProvideLogger returns the logger of its own, not the StandardLogger of logrus,
+// so that each binary and test has an independent one.
+// The messages and the fields are masked by the redactor.
+func ProvideLogger(levels *Levels, redactor *Redactor) Logger {
+	l := log.New()
+	// logrus default New function
 	// &Logger{
 	//	Out:          os.Stderr,
 	//	Formatter:    new(TextFormatter),
@@ -38,77 +57,118 @@ func ProvideLogger(conf *Config) Logger {
 	// Can be any io.Writer, see below for File example
 	l.SetOutput(os.Stdout)
 
-	// Only log the warning severity or above.
-	switch conf.ErrorLevel {
-	case Debug:
-		l.SetLevel(log.DebugLevel)
-	case Info:
-		l.SetLevel(log.InfoLevel)
-	case Warn:
-		l.SetLevel(log.WarnLevel)
-	case Error:
-		l.SetLevel(log.ErrorLevel)
-	default:
-		l.SetLevel(log.InfoLevel)
-	}
+	// The levels are checked by Levels, which can be changed at runtime
+	l.SetLevel(log.DebugLevel)
 
 	return &logger{
-		logger: l,
+		logger:   l,
+		levels:   levels,
+		redactor: redactor,
 	}
 }
 
-type logger struct {
-	logger *log.Logger
+// NewLogger returns the logger logging at the level without redaction, for the tools and the tests
+func NewLogger(level ErrorLevel) Logger {
+	return ProvideLogger(ProvideLevels(&Config{ErrorLevel: level}), nil)
 }
 
-func (l *logger) Log(errorLevel ErrorLevel, requestId, message string) {
+func logrusLevel(errorLevel ErrorLevel) log.Level {
 	switch errorLevel {
 	case Debug:
-		l.debug(requestId, message)
+		return log.DebugLevel
 	case Info:
-		l.info(requestId, message)
+		return log.InfoLevel
 	case Warn:
-		l.warn(requestId, message)
+		return log.WarnLevel
 	case Error:
-		l.error(requestId, message)
+		return log.ErrorLevel
+	default:
+		return log.InfoLevel
 	}
 }
 
-var (
-	reqIdKeyText      = "request-id"
-	stackTraceKeyText = "stack_trace"
-)
+type logger struct {
+	logger   *log.Logger
+	levels   *Levels
+	redactor *Redactor
+	// name is the package given by Named
+	name string
+	// fields and err are added by With and WithError
+	fields Fields
+	err    error
+}
 
-func (l *logger) debug(requestId, message string) {
-	//// stack trace
-	//buf := make([]byte, 1024)
-	//stackTrace := runtime.Stack(buf, true)
-	l.logger.WithFields(log.Fields{
-		reqIdKeyText: requestId,
-		//stackTraceKeyText: buf[:stackTrace],
-	}).Debug(message)
+func (l *logger) Log(errorLevel ErrorLevel, requestId, message string) {
+	l.log(errorLevel, message, Fields{reqIdKeyText: requestId})
 }
 
-func (l *logger) info(requestId, message string) {
-	l.logger.WithFields(log.Fields{
-		reqIdKeyText: requestId,
-	}).Info(message)
+func (l *logger) LogContext(ctx context.Context, errorLevel ErrorLevel, message string) {
+	l.log(errorLevel, message, contextFields(ctx))
 }
 
-func (l *logger) warn(requestId, message string) {
-	l.logger.WithFields(log.Fields{
-		reqIdKeyText: requestId,
-	}).Warn(message)
+func (l *logger) LogFields(ctx context.Context, errorLevel ErrorLevel, message string, fields Fields) {
+	entryFields := contextFields(ctx)
+	for k, v := range fields {
+		entryFields[k] = v
+	}
+	l.log(errorLevel, message, entryFields)
 }
 
-func (l *logger) error(requestId, message string) {
-	// TODO: Set Stack Trace by using runtime or error
-	// We would address it by using new error handling in Go1.13 era.
-	//// stack trace
-	//buf := make([]byte, 1024)
-	//stackTrace := runtime.Stack(buf, true)
-	l.logger.WithFields(log.Fields{
-		reqIdKeyText: requestId,
-		//stackTraceKeyText: buf[:stackTrace],
-	}).Error(message)
+func (l *logger) With(key string, value interface{}) Logger {
+	fields := make(Fields, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	c := *l
+	c.fields = fields
+	return &c
+}
+
+func (l *logger) WithError(err error) Logger {
+	c := *l
+	c.err = err
+	return &c
+}
+
+func (l *logger) Named(name string) Logger {
+	c := *l
+	c.name = name
+	return &c
+}
+
+var (
+	reqIdKeyText      = "request-id"
+	uidKeyText        = "uid"
+	routeKeyText      = "route"
+	packageKeyText    = "package"
+	errorKeyText      = "error"
+	stackTraceKeyText = "stack_trace"
+)
+
+// log writes the entry with the fields of With, the error of WithError and the given ones, which win in this order
+func (l *logger) log(errorLevel ErrorLevel, message string, fields Fields) {
+	if !l.levels.enabled(l.name, errorLevel) {
+		return
+	}
+	entryFields := make(log.Fields, len(l.fields)+len(fields)+3)
+	if l.name != "" {
+		entryFields[packageKeyText] = l.name
+	}
+	for k, v := range l.fields {
+		entryFields[k] = v
+	}
+	if l.err != nil {
+		entryFields[errorKeyText] = ErrorMessage(l.err)
+		if errorLevel == Error {
+			if stack := StackTrace(l.err); len(stack) > 0 {
+				entryFields[stackTraceKeyText] = stack
+			}
+		}
+	}
+	for k, v := range fields {
+		entryFields[k] = v
+	}
+	l.redactor.redactFields(entryFields)
+	l.logger.WithFields(entryFields).Log(logrusLevel(errorLevel), l.redactor.Redact(message))
 }
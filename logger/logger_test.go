@@ -0,0 +1,87 @@
+package logger_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/momotaro98/stew"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+func TestLogger_ContextAndFields(t *testing.T) {
+	r := logger.NewRecorder()
+	ctx := logger.WithRequestId(context.Background(), "req1")
+	ctx = logger.WithUID(ctx, "uid1")
+	ctx = logger.WithRoute(ctx, "/api/v1/user/{uid}")
+
+	r.With("party", 3).LogFields(ctx, logger.Info, "joined", logger.Fields{"tags": 2})
+	r.LogContext(logger.WithRequestId(context.Background(), "req2"), logger.Debug, "plain")
+
+	entries := r.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, actual %d", len(entries))
+	}
+	expected := logger.Entry{
+		Level:   logger.Info,
+		Message: "joined",
+		Fields: logger.Fields{
+			"request-id": "req1",
+			"uid":        "uid1",
+			"route":      "/api/v1/user/{uid}",
+			"party":      3,
+			"tags":       2,
+		},
+	}
+	if !reflect.DeepEqual(entries[0], expected) {
+		t.Errorf("expected %+v, actual %+v", expected, entries[0])
+	}
+	// No UID nor route in the context
+	if !reflect.DeepEqual(entries[1].Fields, logger.Fields{"request-id": "req2"}) {
+		t.Errorf("unexpected fields %+v", entries[1].Fields)
+	}
+}
+
+func find() error {
+	return stew.Wrap(errors.New("no rows"))
+}
+
+func get() error {
+	if err := find(); err != nil {
+		return stew.Wrap(err)
+	}
+	return nil
+}
+
+func TestLogger_WithError_StackTraceAtError(t *testing.T) {
+	r := logger.NewRecorder()
+	err := get()
+
+	r.WithError(err).LogContext(context.Background(), logger.Error, "getting failed")
+	r.WithError(err).LogContext(context.Background(), logger.Warn, "getting failed")
+
+	entries := r.Entries()
+	if entries[0].Fields["error"] != "no rows" {
+		t.Errorf("expected the message without the frames, actual %q", entries[0].Fields["error"])
+	}
+	stack, _ := entries[0].Fields["stack_trace"].([]string)
+	if len(stack) != 2 || !strings.HasSuffix(stack[0], "logger_test.find") || !strings.HasSuffix(stack[1], "logger_test.get") {
+		t.Errorf("expected the frames of find and get, actual %v", stack)
+	}
+	if _, ok := entries[1].Fields["stack_trace"]; ok {
+		t.Error("expected no stack trace at Warn")
+	}
+}
+
+func TestStackTrace_NotWrapped(t *testing.T) {
+	err := errors.New("plain: error")
+	if stack := logger.StackTrace(err); stack != nil {
+		t.Errorf("expected no frames, actual %v", stack)
+	}
+	if msg := logger.ErrorMessage(err); msg != "plain: error" {
+		t.Errorf("expected the message as it is, actual %q", msg)
+	}
+}
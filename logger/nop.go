@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"context"
+)
+
+// Nop returns the Logger discarding all the entries
+func Nop() Logger {
+	return nop{}
+}
+
+type nop struct{}
+
+func (nop) Log(errorLevel ErrorLevel, requestId, message string) {}
+
+func (nop) LogContext(ctx context.Context, errorLevel ErrorLevel, message string) {}
+
+func (nop) LogFields(ctx context.Context, errorLevel ErrorLevel, message string, fields Fields) {}
+
+func (n nop) With(key string, value interface{}) Logger { return n }
+
+func (n nop) WithError(err error) Logger { return n }
+
+func (n nop) Named(name string) Logger { return n }
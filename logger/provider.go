@@ -5,5 +5,7 @@ import (
 )
 
 var SuperSet = wire.NewSet(
+	ProvideLevels,
+	ProvideRedactor,
 	ProvideLogger,
 )
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"io/ioutil"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Entry is a log entry captured by Recorder
+type Entry struct {
+	Level   ErrorLevel
+	Message string
+	Fields  Fields
+}
+
+// Recorder is the Logger capturing the entries instead of writing them, for the tests asserting on the logs.
+// The loggers made by With, WithError and Named capture into it as well.
+// It redacts the entries with DefaultRedactRules as the servers do by default.
+type Recorder struct {
+	Logger
+	// Levels are Debug by default to capture all the entries
+	Levels *Levels
+	hook   *recordingHook
+}
+
+func NewRecorder() *Recorder {
+	l := log.New()
+	l.SetOutput(ioutil.Discard)
+	l.SetLevel(log.DebugLevel)
+	hook := &recordingHook{}
+	l.AddHook(hook)
+	levels := ProvideLevels(&Config{ErrorLevel: Debug})
+	redactor, err := NewRedactor(DefaultRedactRules...)
+	if err != nil {
+		panic(err)
+	}
+	return &Recorder{
+		Logger: &logger{logger: l, levels: levels, redactor: redactor},
+		Levels: levels,
+		hook:   hook,
+	}
+}
+
+// Entries returns the entries captured so far
+func (r *Recorder) Entries() []Entry {
+	r.hook.mu.Lock()
+	defer r.hook.mu.Unlock()
+	entries := make([]Entry, len(r.hook.entries))
+	copy(entries, r.hook.entries)
+	return entries
+}
+
+// Reset drops the entries captured so far
+func (r *Recorder) Reset() {
+	r.hook.mu.Lock()
+	defer r.hook.mu.Unlock()
+	r.hook.entries = nil
+}
+
+type recordingHook struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (h *recordingHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *recordingHook) Fire(e *log.Entry) error {
+	fields := make(Fields, len(e.Data))
+	for k, v := range e.Data {
+		fields[k] = v
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, Entry{
+		Level:   errorLevelOf(e.Level),
+		Message: e.Message,
+		Fields:  fields,
+	})
+	return nil
+}
+
+func errorLevelOf(level log.Level) ErrorLevel {
+	switch level {
+	case log.DebugLevel, log.TraceLevel:
+		return Debug
+	case log.InfoLevel:
+		return Info
+	case log.WarnLevel:
+		return Warn
+	default:
+		return Error
+	}
+}
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redacted replaces the masked values
+const redacted = "[REDACTED]"
+
+// Rule masks a kind of personal information in the messages and the fields of the log entries
+type Rule struct {
+	// Keys are the field keys, and the keys of "key: value", "key=value" and "\"key\":value" in the messages,
+	// whose values are masked. They are case-insensitive.
+	Keys []string
+	// Patterns are masked wherever they appear
+	Patterns []*regexp.Regexp
+}
+
+// Rules are the rules Redactor can use by name
+var Rules = map[string]Rule{
+	"email": {
+		Keys:     []string{"email"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	},
+	"name": {
+		Keys: []string{"name", "user_name", "username", "nick_name", "nickname", "display_name", "displayname"},
+	},
+	"coordinates": {
+		Keys: []string{"latitude", "longitude", "lat", "lng"},
+	},
+	"birthday": {
+		Keys: []string{"birthday", "birth_date", "birthdate"},
+	},
+	"token": {
+		// Not "token", which is in the messages like "error verifying token: ..."
+		Keys: []string{"id_token", "idtoken", "access_token", "refresh_token", "session_cookie", "sessioncookie", "authorization"},
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+			// JWTs without the scheme, e.g. ID tokens and session cookies
+			regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]*\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`),
+		},
+	},
+}
+
+// DefaultRedactRules are all the rules in Rules
+var DefaultRedactRules = []string{"email", "name", "coordinates", "birthday", "token"}
+
+// Redactor masks the personal information in the log entries by the rules.
+// The nil Redactor masks nothing.
+type Redactor struct {
+	keys map[string]bool
+	// keyPrefix matches the keys of the rules followed by the separator in the messages
+	keyPrefix *regexp.Regexp
+	patterns  []*regexp.Regexp
+}
+
+// ProvideRedactor returns the Redactor of the rules in the config
+func ProvideRedactor(conf *Config) (*Redactor, error) {
+	return NewRedactor(conf.RedactRules...)
+}
+
+// NewRedactor returns the Redactor of the rules in Rules by name
+func NewRedactor(names ...string) (*Redactor, error) {
+	r := &Redactor{keys: make(map[string]bool)}
+	var keys []string
+	for _, name := range names {
+		rule, ok := Rules[name]
+		if !ok {
+			return nil, fmt.Errorf("logger: unknown redaction rule %q", name)
+		}
+		for _, k := range rule.Keys {
+			r.keys[strings.ToLower(k)] = true
+			keys = append(keys, regexp.QuoteMeta(k))
+		}
+		r.patterns = append(r.patterns, rule.Patterns...)
+	}
+	if len(keys) > 0 {
+		// Longer keys first not to stop at "name" of "user_name"
+		sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+		r.keyPrefix = regexp.MustCompile(`(?i)"?\b(?:` + strings.Join(keys, "|") + `)"?\s*[:=]\s*`)
+	}
+	return r, nil
+}
+
+// Redact returns the string with the personal information masked
+func (r *Redactor) Redact(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	for _, p := range r.patterns {
+		s = p.ReplaceAllString(s, redacted)
+	}
+	if r.keyPrefix != nil {
+		s = r.redactKeyValues(s)
+	}
+	return s
+}
+
+var (
+	// nextKey is the start of the next key of a struct formatted by %+v, e.g. " Email:"
+	nextKey = regexp.MustCompile(`\s+\w+[:=]`)
+	// emptyValue is the next key right after the key of an empty value, e.g. "Sex:" of "NickName: Sex:1"
+	emptyValue = regexp.MustCompile(`^\w+[:=]`)
+)
+
+// redactKeyValues masks the values following the keys of the rules
+func (r *Redactor) redactKeyValues(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range r.keyPrefix.FindAllStringIndex(s, -1) {
+		if loc[0] < last {
+			// In the value masked already
+			continue
+		}
+		start := loc[1]
+		end := valueEnd(s, start)
+		if end == start {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(redacted)
+		last = end
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// valueEnd returns the end of the value starting at start, which is a quoted string,
+// or ends before the next key of %+v, a delimiter or the end.
+func valueEnd(s string, start int) int {
+	if start < len(s) && s[start] == '"' {
+		if i := strings.IndexByte(s[start+1:], '"'); i >= 0 {
+			return start + 1 + i + 1
+		}
+		return len(s)
+	}
+	rest := s[start:]
+	if emptyValue.MatchString(rest) {
+		return start
+	}
+	end := len(rest)
+	if loc := nextKey.FindStringIndex(rest); loc != nil {
+		end = loc[0]
+	}
+	if i := strings.IndexAny(rest[:end], ",;{}[]\n"); i >= 0 {
+		end = i
+	}
+	return start + end
+}
+
+// redactFields masks the values of the keys of the rules, and the personal information in the other strings
+func (r *Redactor) redactFields(fields map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	for k, v := range fields {
+		if r.keys[strings.ToLower(k)] {
+			fields[k] = redacted
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			fields[k] = r.Redact(v)
+		case []string:
+			masked := make([]string, len(v))
+			for i, s := range v {
+				masked[i] = r.Redact(s)
+			}
+			fields[k] = masked
+		}
+	}
+}
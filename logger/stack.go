@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+)
+
+// stewFrame matches the frame which stew.Wrap appends to the error message as " file:line function\n"
+var stewFrame = regexp.MustCompile(` (\S+\.go:\d+ \S+)\n`)
+
+// StackTrace returns the frames of the stew.Wrap calls on the error, the innermost first
+func StackTrace(err error) []string {
+	if err == nil {
+		return nil
+	}
+	matches := stewFrame.FindAllStringSubmatch(err.Error(), -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	stack := make([]string, len(matches))
+	for i, m := range matches {
+		stack[i] = m[1]
+	}
+	return stack
+}
+
+// ErrorMessage returns the message of the error without the frames of stew.Wrap
+func ErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return strings.TrimSpace(stewFrame.ReplaceAllString(err.Error(), ""))
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -10,26 +10,32 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/momotaro98/mixlunch-service-api/auth"
+	"github.com/momotaro98/mixlunch-service-api/config"
+	"github.com/momotaro98/mixlunch-service-api/database"
+	"github.com/momotaro98/mixlunch-service-api/health"
 	"github.com/momotaro98/mixlunch-service-api/logger"
-	"github.com/momotaro98/mixlunch-service-api/partyservice"
-	"github.com/momotaro98/mixlunch-service-api/tagservice"
-	usService "github.com/momotaro98/mixlunch-service-api/userscheduleservice"
-	"github.com/momotaro98/mixlunch-service-api/userservice"
+	"github.com/momotaro98/mixlunch-service-api/metrics"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
 )
 
 const (
-	Version               = "1.3.1"
-	ServiceAccountKeyPath = "./serviceAccount/serviceAccountKey.json"
+	Version = "1.3.1"
 )
 
 func main() {
-	var (
-		httpAddr = flag.String("http", ":5000", "http listen address")
-	)
-	flag.Parse()
+	// Loading config from the file, environment variables and flags
+	cfg, err := config.Load(os.Args[0], os.Args[1:], os.LookupEnv)
+	if err == flag.ErrHelp {
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	errChan := make(chan error)
 	go func() {
@@ -38,126 +44,240 @@ func main() {
 		errChan <- fmt.Errorf("%s", <-c)
 	}()
 
-	// Initial loading config
-	var (
-		logConf = &logger.Config{
-			ErrorLevel: logger.Info, // Might need to be set from command argument
-		}
+	logConf := cfg.Logger()
+	app, cleanup, err := initializeApplication(logConf, cfg.Database(), cfg.PartyService())
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&charset=utf8mb4",
-			os.Getenv("DB_USER"), os.Getenv("DB_PASS"),
-			os.Getenv("DB_HOST"), os.Getenv("DB_PORT"),
-			os.Getenv("DB_DATABASE"),
-		)
-		usConf = &usService.Config{
-			DSN: dsn,
+	// Reload the log levels from the config sources on SIGHUP
+	go logger.ReloadOnHangup(context.Background(), app.Levels, func() (*logger.Config, error) {
+		cfg, err := config.Load(os.Args[0], os.Args[1:], os.LookupEnv)
+		if err != nil {
+			return nil, err
 		}
-		pConf = &partyservice.Config{
-			DSN:                   dsn,
-			AppCredentialFilePath: ServiceAccountKeyPath,
-		}
-		tConf = &tagservice.Config{
-			DSN: dsn,
-		}
-		uConf = &userservice.Config{
-			DSN: dsn,
-		}
-	)
+		return cfg.Logger(), nil
+	}, app.Logger)
+
+	// Anonymize the erased users in the chat rooms where it failed on the erasures
+	go anonymizePendingChatRooms(context.Background(), app.AccountServer, app.Logger)
+
+	// Tracing, which the spans of the services and the SQL statements use as well
+	tracer, err := tracing.New(cfg.Tracer("mixlunch-service-api"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	tracing.SetTracer(tracer)
 
 	// Middlewares
 
 	// Auth middleware
-	var authActivate bool
-	if os.Getenv("AUTH_ACTIVATE") == "ON" {
-		authActivate = true
+	var (
+		verifier   auth.TokenVerifier
+		sessions   auth.SessionManager
+		revocation *auth.RevocationChecker
+	)
+	if cfg.Auth.Activate {
+		projectID, err := firebaseProjectID(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		verifier = newTokenVerifier(projectID)
+		if sessions, err = auth.NewFirebaseSessions(context.Background(), projectID, cfg.Firebase.CredentialFile); err != nil {
+			log.Fatal(err)
+		}
+		revocation = auth.NewRevocationChecker(sessions, cfg.Auth.RevocationCacheTTL)
 	}
-	auth := AuthMiddle(authActivate, logConf, ServiceAccountKeyPath)
+	authMiddle := AuthMiddle(cfg.Auth.Activate, app.Logger, verifier, revocation)
+
+	// Timeout middleware
+	timeout := TimeoutMiddle(cfg.HTTP.RequestTimeout)
 
 	const (
-		GET  = "GET"
-		POST = "POST"
+		GET    = "GET"
+		POST   = "POST"
+		PUT    = "PUT"
+		PATCH  = "PATCH"
+		DELETE = "DELETE"
 	)
 
-	// Launch REST server
-	go func() {
-		r := mux.NewRouter()
-		s := r.PathPrefix("/api/v1").Subrouter()
-
-		// User schedule server
-		s.Handle("/userschedule/{uid:[a-zA-Z0-9]+}/{beginDateTime}/{endDateTime}",
-			M(initializeUserScheduleHandler(logConf, usConf, tConf), auth)).
-			Methods(GET)
-		// [Note] The order of the p.Add routing is crucial
-		// "update" must be before none("add") because "update" is also a case of {uid:[a-zA-Z0-9]+}
-		s.Handle("/userschedule/update/{uid:[a-zA-Z0-9]+}/",
-			M(initializeUpdateUserScheduleHandler(logConf, usConf, tConf), auth)).
-			Methods(POST)
-		s.Handle("/userschedule/delete/{uid:[a-zA-Z0-9]+}/",
-			M(initializeDeleteUserScheduleHandler(logConf, usConf, tConf), auth)).
-			Methods(POST)
-		s.Handle("/userschedule/{uid:[a-zA-Z0-9]+}/",
-			M(initializeAddUserScheduleHandler(logConf, usConf, tConf), auth)).
-			Methods(POST)
+	// Dependency checks
+	hc := health.New(fmt.Sprintf("higher v%s", Version), cfg.Health.Timeout)
+	hc.Add("mysql", health.PingDB(app.DB))
+	if cfg.Health.Firebase {
+		hc.Add("firebase", health.Reachable(http.DefaultClient, health.FirebaseURL))
+	}
 
-		// Party server
-		s.Handle("/party/review/member",
-			M(initializePartyReviewMemberHandler(logConf, pConf, uConf, tConf), auth)).
-			Methods(POST)
-		s.Handle("/party/review/done/{reviewer:[a-zA-Z0-9]+}",
-			M(initializePartyReviewMemberDoneHandler(logConf, pConf, uConf, tConf), auth)).
-			Methods(GET)
-		s.Handle("/party/{uid:[a-zA-Z0-9]+}/{beginDateTime}/{endDateTime}",
-			M(initializePartyHandler(logConf, pConf, uConf, tConf), auth)).
-			Methods(GET)
-
-		// Tag server
-		// [Note] Longer path should be upper side
-		s.Handle("/tags/{ttid:[0-9]+}",
-			M(initializeTagsHandler(logConf, tConf), auth)).
-			Methods(GET)
-		s.Handle("/tags",
-			M(initializeTagsHandler(logConf, tConf), auth)).
-			Methods(GET)
-
-		// User server
-		s.Handle("/user/public/{uid:[a-zA-Z0-9]+}",
-			M(initializeUserPublicHandler(logConf, uConf, tConf), auth)).
-			Methods(GET)
-		s.Handle("/user/register",
-			M(initializeUserRegisterHandler(logConf, uConf, tConf), auth)).
-			Methods(POST)
-		s.Handle("/user/{uid:[a-zA-Z0-9]+}",
-			M(initializeUserHandler(logConf, uConf, tConf), auth)).
-			Methods(GET)
-		// Block list
-		s.Handle("/user/block",
-			M(initializeUserBlockRegisterHandler(logConf, uConf, tConf), auth)).
+	// Routing
+	r := mux.NewRouter()
+	s := r.PathPrefix("/api/v1").Subrouter()
+
+	// User schedule server
+	s.Handle("/userschedule/{uid:[a-zA-Z0-9]+}/{beginDateTime}/{endDateTime}",
+		M(app.UserScheduleHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(GET)
+	// [Note] The order of the p.Add routing is crucial
+	// "update" must be before none("add") because "update" is also a case of {uid:[a-zA-Z0-9]+}
+	s.Handle("/userschedule/update/{uid:[a-zA-Z0-9]+}/",
+		M(app.UpdateUserScheduleHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(POST)
+	s.Handle("/userschedule/delete/{uid:[a-zA-Z0-9]+}/",
+		M(app.DeleteUserScheduleHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(POST)
+	s.Handle("/userschedule/{uid:[a-zA-Z0-9]+}/",
+		M(app.AddUserScheduleHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(POST)
+
+	// Party server
+	s.Handle("/party/review/member",
+		M(app.PartyReviewMemberHandler, authMiddle, timeout)).
+		Methods(POST)
+	s.Handle("/party/review/done/{reviewer:[a-zA-Z0-9]+}",
+		M(app.PartyReviewMemberDoneHandler, OwnerMiddle(app.Logger, "reviewer"), authMiddle, timeout)).
+		Methods(GET)
+	s.Handle("/party/{uid:[a-zA-Z0-9]+}/{beginDateTime}/{endDateTime}",
+		M(app.PartyHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(GET)
+
+	// Tag server
+	// [Note] Longer path should be upper side
+	s.Handle("/tags/{ttid:[0-9]+}",
+		M(app.TagsHandler, authMiddle, timeout)).
+		Methods(GET)
+	s.Handle("/tags",
+		M(app.TagsHandler, authMiddle, timeout)).
+		Methods(GET)
+
+	// User server
+	s.Handle("/user/public/{uid:[a-zA-Z0-9]+}",
+		M(app.UserPublicHandler, authMiddle, timeout)).
+		Methods(GET)
+	s.Handle("/user/register",
+		M(app.UserRegisterHandler, authMiddle, timeout)).
+		Methods(POST)
+	// Block list
+	// [Note] It should be upper side than "/user/{uid}", which matches "/user/block" as well
+	s.Handle("/user/block",
+		M(app.UserBlockRegisterHandler, authMiddle, timeout)).
+		Methods(POST)
+	s.Handle("/user/block",
+		M(app.UserBlockDeleteHandler, authMiddle, timeout)).
+		Methods(DELETE)
+	s.Handle("/user/{uid:[a-zA-Z0-9]+}/blocks",
+		M(app.UserBlocksHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(GET)
+	s.Handle("/user/{uid:[a-zA-Z0-9]+}",
+		M(app.UserHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(GET)
+	s.Handle("/user/{uid:[a-zA-Z0-9]+}",
+		M(app.UserUpdateHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(PUT)
+	s.Handle("/user/{uid:[a-zA-Z0-9]+}",
+		M(app.UserPatchHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(PATCH)
+	// Account, which is the personal data of the user across the services
+	s.Handle("/user/{uid:[a-zA-Z0-9]+}/export",
+		M(app.AccountExportHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(GET)
+	s.Handle("/user/{uid:[a-zA-Z0-9]+}",
+		M(app.AccountDeleteHandler, OwnerMiddle(app.Logger, "uid"), authMiddle, timeout)).
+		Methods(DELETE)
+
+	// Session, which needs Firebase to create and revoke the session cookies
+	if cfg.Auth.Activate {
+		session := NewSessionHandler(app.Logger, verifier, sessions, revocation, cfg.Auth.SessionLifetime, cfg.Auth.SessionCookieSecure)
+		s.Handle("/session",
+			M(session, timeout)).
 			Methods(POST)
+		s.Handle("/session",
+			M(session, authMiddle, timeout)).
+			Methods(DELETE)
+	}
 
-		// Health check
-		s.HandleFunc("/healthcheck", func(w http.ResponseWriter, r *http.Request) {
-			db, err := sql.Open("mysql", tConf.DSN)
-			if err != nil {
-				json.NewEncoder(w).Encode(map[string]string{
-					"ok":      "false",
-					"version": fmt.Sprintf("higher v%s", Version),
-				})
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-			db.Close()
-
-			json.NewEncoder(w).Encode(map[string]string{
-				"ok":      "true",
-				"version": fmt.Sprintf("higher v%s", Version),
-			})
-			w.WriteHeader(http.StatusOK)
-		}).Methods(GET)
-
-		// Launch web server
-		log.Println("http:", *httpAddr)
-		errChan <- http.ListenAndServe(*httpAddr, r)
+	// Admin, which requires the admin role for all the endpoints
+	roles := auth.NewRoleResolver(auth.NewSQLRoleStore(app.DB))
+	admin := s.PathPrefix("/admin").Subrouter()
+	admin.Use(mux.MiddlewareFunc(timeout), mux.MiddlewareFunc(authMiddle), mux.MiddlewareFunc(RoleMiddle(app.Logger, roles, auth.RoleAdmin)))
+	admin.Handle("/parties/{beginDateTime}/{endDateTime}", app.AdminPartiesHandler).
+		Methods(GET)
+	admin.Handle("/log-levels", logger.LevelsHandler(app.Levels)).
+		Methods(GET, PUT)
+	admin.Handle("/dbstats", database.StatsHandler(app.DB)).
+		Methods(GET)
+
+	// Error codes
+	s.Handle("/errors",
+		M(app.ErrorsHandler, timeout)).
+		Methods(GET)
+
+	// Health check
+	r.Handle("/livez", health.LivenessHandler(hc)).Methods(GET)
+	r.Handle("/readyz", health.ReadinessHandler(hc)).Methods(GET)
+	// Deprecated: Use /readyz
+	s.HandleFunc("/healthcheck", func(w http.ResponseWriter, r *http.Request) {
+		report := hc.Check(r.Context())
+		ok, code := "true", http.StatusOK
+		if report.Status != health.StatusOK {
+			ok, code = "false", http.StatusServiceUnavailable
+		}
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]string{
+			"ok":      ok,
+			"version": report.Version,
+		})
+	}).Methods(GET)
+
+	// Monitoring
+	m := metrics.New()
+	m.Register(metrics.NewDBStatsCollector(app.DB))
+
+	// Launch REST server
+	srv := &http.Server{
+		Addr:    cfg.HTTP.Addr,
+		Handler: M(r, RecoverMiddle(app.Logger), TraceMiddle(r), MetricsMiddle(m, r), AccessLogMiddle(app.Logger, r), RequestIdMiddle),
+	}
+	go func() {
+		log.Println("http:", cfg.HTTP.Addr)
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			errChan <- err
+		}
 	}()
 
-	log.Fatal(<-errChan)
+	// Metrics endpoint, which is served on the internal address not to be public
+	var metricsServer *http.Server
+	if cfg.HTTP.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", m.Handler())
+		metricsServer = &http.Server{
+			Addr:    cfg.HTTP.MetricsAddr,
+			Handler: metricsMux,
+		}
+		go func() {
+			log.Println("metrics:", cfg.HTTP.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != http.ErrServerClosed {
+				errChan <- err
+			}
+		}()
+	}
+
+	log.Println("shutting down:", <-errChan)
+
+	// Stop taking new traffic first, then wait for the in-flight requests
+	hc.Drain()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.DrainTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Println("http shutdown:", err)
+	}
+	if metricsServer != nil {
+		// Scraping the last metrics isn't waited for
+		_ = metricsServer.Close()
+	}
+	// The spans of the last requests are exported even after the drain timeout
+	tctx, tcancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer tcancel()
+	if err := tracer.Shutdown(tctx); err != nil {
+		log.Println("tracing shutdown:", err)
+	}
+	cleanup()
 }
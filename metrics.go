@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
+	"github.com/momotaro98/mixlunch-service-api/metrics"
+)
+
+// metricsEntry holds the domain error responded by the handler, if any
+type metricsEntry struct {
+	errorCode domainerror.ErrorCode
+}
+
+type metricsEntryKey struct{}
+
+// setMetricsErrorCode records the code of the domain error responded for the request
+func setMetricsErrorCode(ctx context.Context, code domainerror.ErrorCode) {
+	if entry, ok := ctx.Value(metricsEntryKey{}).(*metricsEntry); ok {
+		entry.errorCode = code
+	}
+}
+
+// MetricsMiddle counts the requests and observes the latency by route template,
+// and counts the domain errors by code.
+func MetricsMiddle(m *metrics.Metrics, router *mux.Router) MFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			entry := &metricsEntry{}
+			rec := &responseRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), metricsEntryKey{}, entry)))
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			route := routeTemplate(router, r)
+			if route == "" {
+				// Not to make a label per unknown path
+				route = "unmatched"
+			}
+			m.ObserveHTTP(r.Method, route, rec.status, time.Since(start))
+			if entry.errorCode != 0 {
+				m.IncDomainError(entry.errorCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
+)
+
+const namespace = "mixlunch"
+
+// Metrics holds the metrics of the servers in its own registry
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	domainErrors *prometheus.CounterVec
+
+	grpcCalls    *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	grpcMessages *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by route template and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests by route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		domainErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "domain_errors_total",
+			Help:      "Number of domain errors responded by error code.",
+		}, []string{"code"}),
+		grpcCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "calls_total",
+			Help:      "Number of gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of gRPC calls by method.",
+			// Streaming calls such as CreateParties take much longer than HTTP requests
+			Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 120},
+		}, []string{"method"}),
+		grpcMessages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "stream_messages_total",
+			Help:      "Number of streamed gRPC messages by method and direction.",
+		}, []string{"method", "direction"}),
+	}
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.domainErrors,
+		m.grpcCalls,
+		m.grpcDuration,
+		m.grpcMessages,
+	)
+	return m
+}
+
+// Register registers additional collectors such as DBStatsCollector
+func (m *Metrics) Register(collectors ...prometheus.Collector) {
+	m.registry.MustRegister(collectors...)
+}
+
+// Handler serves the metrics in the Prometheus format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTP records an HTTP request. The route must be the template, not the raw path.
+func (m *Metrics) ObserveHTTP(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (m *Metrics) IncDomainError(code domainerror.ErrorCode) {
+	m.domainErrors.WithLabelValues(strconv.Itoa(int(code))).Inc()
+}
+
+// ObserveGRPC records a gRPC call. The method is the full method name.
+func (m *Metrics) ObserveGRPC(method string, code codes.Code, duration time.Duration) {
+	m.grpcCalls.WithLabelValues(method, code.String()).Inc()
+	m.grpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+const (
+	Sent     = "sent"
+	Received = "received"
+)
+
+// IncGRPCMessage records a streamed message of the direction, Sent or Received
+func (m *Metrics) IncGRPCMessage(method, direction string) {
+	m.grpcMessages.WithLabelValues(method, direction).Inc()
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	m.ObserveHTTP("GET", "/api/v1/user/{uid:[a-zA-Z0-9]+}", 200, 10*time.Millisecond)
+	m.ObserveHTTP("GET", "/api/v1/user/{uid:[a-zA-Z0-9]+}", 200, 20*time.Millisecond)
+	m.IncDomainError(domainerror.ValidationErrorCode)
+	m.ObserveGRPC("/pb.MixLunch/CreateParties", codes.OK, time.Second)
+	m.IncGRPCMessage("/pb.MixLunch/CreateParties", Received)
+	m.IncGRPCMessage("/pb.MixLunch/CreateParties", Received)
+	m.IncGRPCMessage("/pb.MixLunch/CreateParties", Received)
+
+	testCases := []struct {
+		name     string
+		actual   float64
+		expected float64
+	}{
+		{"http requests", testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/v1/user/{uid:[a-zA-Z0-9]+}", "200")), 2},
+		{"domain errors", testutil.ToFloat64(m.domainErrors.WithLabelValues("3")), 1},
+		{"grpc calls", testutil.ToFloat64(m.grpcCalls.WithLabelValues("/pb.MixLunch/CreateParties", "OK")), 1},
+		{"grpc messages", testutil.ToFloat64(m.grpcMessages.WithLabelValues("/pb.MixLunch/CreateParties", Received)), 3},
+	}
+	for _, tc := range testCases {
+		if tc.actual != tc.expected {
+			t.Errorf("%s: expected %v, actual %v", tc.name, tc.expected, tc.actual)
+		}
+	}
+}
+
+func TestDBStatsCollector(t *testing.T) {
+	// sql.Open doesn't connect so that the stats are available without DB
+	db, err := sql.Open("mysql", "user:pass@tcp(localhost:3306)/mixlunch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+
+	c := NewDBStatsCollector(db)
+	if n := testutil.CollectAndCount(c); n != 8 {
+		t.Errorf("expected 8 metrics, actual %d", n)
+	}
+	New().Register(c)
+}
@@ -3,13 +3,14 @@ package partyservice
 import (
 	"context"
 	"database/sql"
+	"sync"
 
+	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go"
 	"google.golang.org/api/option"
 )
 
 type Config struct {
-	DSN                   string
 	AppCredentialFilePath string
 }
 
@@ -17,26 +18,46 @@ type SqlDb struct {
 	*sql.DB
 }
 
-func ProvideDB(cfg *Config) SqlDb {
-	dsn := cfg.DSN
-	var err error
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
-	}
+// ProvideDB provides the DB pool shared by the services
+func ProvideDB(db *sql.DB) SqlDb {
 	return SqlDb{db}
 }
 
+// App is the Firebase app. Its Firestore client is opened on the first use
+// and shared by the callers until the cleanup function of ProvideApp closes it.
 type App struct {
 	*firebase.App
+	fs *sharedFirestore
 }
 
-func ProvideApp(cnf *Config) App {
+type sharedFirestore struct {
+	once   sync.Once
+	client *firestore.Client
+	err    error
+}
+
+// ProvideApp creates the Firebase app and returns it with a cleanup function closing its clients
+func ProvideApp(cnf *Config) (App, func()) {
 	var err error
 	opt := option.WithCredentialsFile(cnf.AppCredentialFilePath)
 	app, err := firebase.NewApp(context.Background(), nil, opt)
 	if err != nil {
 		panic(err)
 	}
-	return App{app}
+	a := App{App: app, fs: &sharedFirestore{}}
+	return a, func() {
+		// Running the once here also keeps the client from being opened after closing
+		a.fs.once.Do(func() {})
+		if a.fs.client != nil {
+			_ = a.fs.client.Close()
+		}
+	}
+}
+
+func (a App) firestore() (*firestore.Client, error) {
+	a.fs.once.Do(func() {
+		// The client outlives any request so it isn't bound to the request context
+		a.fs.client, a.fs.err = a.App.Firestore(context.Background())
+	})
+	return a.fs.client, a.fs.err
 }
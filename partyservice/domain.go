@@ -1,6 +1,7 @@
 package partyservice
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"time"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
 	"github.com/momotaro98/mixlunch-service-api/tagservice"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
 	"github.com/momotaro98/mixlunch-service-api/userservice"
 	"github.com/momotaro98/mixlunch-service-api/utils"
 )
@@ -62,13 +64,14 @@ type IsLatestReviewDone struct {
 // [Note] Trying to separate "Command" and "Query" model in User type constructor
 
 type PartyServer interface {
-	GetParties(beginDateTimeStr, endDateTimeStr string) (*Parties, error)
-	GetPartyByUserIdAndTimeRange(userId, beginDateTimeStr, endDateTimeStr string) (*Parties, error)
-	GetIsLatestPartyReviewDone(userId string) (*IsLatestReviewDone, error)
-	GetLastNPartiesOfAUser(userId string, n int) (*Parties, error)
-	PostPartyReviewMember(reviewMember *PartyReviewMember) error
-	UpsertParties(partyModel []*PartyForCommand) error
-	GenerateChatRoom(chatRoomId string) error
+	GetParties(ctx context.Context, beginDateTimeStr, endDateTimeStr string) (*Parties, error)
+	GetPartyByUserIdAndTimeRange(ctx context.Context, userId, beginDateTimeStr, endDateTimeStr string) (*Parties, error)
+	GetIsLatestPartyReviewDone(ctx context.Context, userId string) (*IsLatestReviewDone, error)
+	GetLastNPartiesOfAUser(ctx context.Context, userId string, n int) (*Parties, error)
+	PostPartyReviewMember(ctx context.Context, reviewMember *PartyReviewMember) error
+	UpsertParties(ctx context.Context, partyModel []*PartyForCommand) error
+	GenerateChatRoom(ctx context.Context, chatRoomId string) error
+	AnonymizeChatRooms(ctx context.Context, userId string, chatRoomIds []string) error
 }
 
 func ProvidePartyServer(
@@ -94,14 +97,16 @@ type realPartyServer struct {
 	chatRoomRepository     IChatRoomRepository
 }
 
-func (s *realPartyServer) GetParties(beginDateTimeStr, endDateTimeStr string) (*Parties, error) {
+func (s *realPartyServer) GetParties(ctx context.Context, beginDateTimeStr, endDateTimeStr string) (*Parties, error) {
+	ctx, span := tracing.Start(ctx, "partyservice.GetParties")
+	defer span.End()
 	// Parse begin and end DateTime string to RFC3339 spec
 	beginDateTime, endDateTime, err := parseBeginEndDateTime(beginDateTimeStr, endDateTimeStr)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 	// Query parties
-	partyDtos, err := s.partyQueryRepository.QueryPartiesWhereTimeRange(&PartyQueryDto{
+	partyDtos, err := s.partyQueryRepository.QueryPartiesWhereTimeRange(ctx, &PartyQueryDto{
 		beginDateTime: beginDateTime,
 		endDateTime:   endDateTime,
 	})
@@ -112,14 +117,16 @@ func (s *realPartyServer) GetParties(beginDateTimeStr, endDateTimeStr string) (*
 		return &Parties{}, nil
 	}
 	// Assign Parties
-	parties, err := s.populateIntoParties(partyDtos) // Parties of GetParties doesn't require user ID
+	parties, err := s.populateIntoParties(ctx, partyDtos) // Parties of GetParties doesn't require user ID
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 	return parties, nil
 }
 
-func (s *realPartyServer) GetPartyByUserIdAndTimeRange(userId, beginDateTimeStr, endDateTimeStr string) (*Parties, error) {
+func (s *realPartyServer) GetPartyByUserIdAndTimeRange(ctx context.Context, userId, beginDateTimeStr, endDateTimeStr string) (*Parties, error) {
+	ctx, span := tracing.Start(ctx, "partyservice.GetPartyByUserIdAndTimeRange")
+	defer span.End()
 	// Parse begin and end DateTime string to RFC3339 spec
 	beginDateTime, endDateTime, err := parseBeginEndDateTime(beginDateTimeStr, endDateTimeStr)
 	if err != nil {
@@ -127,6 +134,7 @@ func (s *realPartyServer) GetPartyByUserIdAndTimeRange(userId, beginDateTimeStr,
 	}
 	// Query parties
 	partyDtos, err := s.partyQueryRepository.QueryPartiesWhereUserIdAndTimeRange(
+		ctx,
 		userId,
 		&PartyQueryDto{
 			beginDateTime: beginDateTime,
@@ -142,14 +150,14 @@ func (s *realPartyServer) GetPartyByUserIdAndTimeRange(userId, beginDateTimeStr,
 		}, nil
 	}
 	// Assign Parties
-	parties, err := s.populateIntoParties(partyDtos)
+	parties, err := s.populateIntoParties(ctx, partyDtos)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 	return parties, nil
 }
 
-func (s *realPartyServer) populateIntoParties(partyDtos []*PartyDto) (*Parties, error) {
+func (s *realPartyServer) populateIntoParties(ctx context.Context, partyDtos []*PartyDto) (*Parties, error) {
 	// Issue: This is 1+N query issue. Fix by involving GetParties and GetPartyByUserIdAndTimeRange when we have time.
 	var parties = Parties{
 		Parties: make([]*Party, 0),
@@ -157,7 +165,7 @@ func (s *realPartyServer) populateIntoParties(partyDtos []*PartyDto) (*Parties,
 	for _, pDto := range partyDtos {
 		var members []*userservice.UserPublic
 		// Get Party members in each party
-		memberDtos, err := s.partyQueryRepository.QueryPartyMembersWherePartyId(pDto.id)
+		memberDtos, err := s.partyQueryRepository.QueryPartyMembersWherePartyId(ctx, pDto.id)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -166,7 +174,7 @@ func (s *realPartyServer) populateIntoParties(partyDtos []*PartyDto) (*Parties,
 			// UserId
 			userId := memberDto.userId
 			// UserName and Email
-			userPublic, err := s.userServer.GetUserPublicByUserId(userId)
+			userPublic, err := s.userServer.GetUserPublicByUserId(ctx, userId)
 			if err != nil {
 				return nil, stew.Wrap(err)
 			}
@@ -174,11 +182,11 @@ func (s *realPartyServer) populateIntoParties(partyDtos []*PartyDto) (*Parties,
 		}
 
 		// Get Tags of the party
-		tagIdsDto, err := s.partyQueryRepository.QueryPartyTagsWherePartyId(pDto.id)
+		tagIdsDto, err := s.partyQueryRepository.QueryPartyTagsWherePartyId(ctx, pDto.id)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
-		tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.All, tagIdsDto.tagIds)
+		tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.All, tagIdsDto.tagIds)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -192,8 +200,10 @@ func (s *realPartyServer) populateIntoParties(partyDtos []*PartyDto) (*Parties,
 	return &parties, nil
 }
 
-func (s *realPartyServer) GetIsLatestPartyReviewDone(userId string) (*IsLatestReviewDone, error) {
-	parties, err := s.GetLastNPartiesOfAUser(userId, 1)
+func (s *realPartyServer) GetIsLatestPartyReviewDone(ctx context.Context, userId string) (*IsLatestReviewDone, error) {
+	ctx, span := tracing.Start(ctx, "partyservice.GetIsLatestPartyReviewDone")
+	defer span.End()
+	parties, err := s.GetLastNPartiesOfAUser(ctx, userId, 1)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -208,7 +218,7 @@ func (s *realPartyServer) GetIsLatestPartyReviewDone(userId string) (*IsLatestRe
 		Reviewer: userId,
 	}
 
-	reviews, err := s.SearchPartyReviewMember(query)
+	reviews, err := s.SearchPartyReviewMember(ctx, query)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -221,8 +231,10 @@ func (s *realPartyServer) GetIsLatestPartyReviewDone(userId string) (*IsLatestRe
 	}, nil
 }
 
-func (s *realPartyServer) GetLastNPartiesOfAUser(userId string, n int) (*Parties, error) {
-	partiesDto, err := s.partyQueryRepository.QueryPartiesWhereUserIdLastN(userId, n)
+func (s *realPartyServer) GetLastNPartiesOfAUser(ctx context.Context, userId string, n int) (*Parties, error) {
+	ctx, span := tracing.Start(ctx, "partyservice.GetLastNPartiesOfAUser")
+	defer span.End()
+	partiesDto, err := s.partyQueryRepository.QueryPartiesWhereUserIdLastN(ctx, userId, n)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -233,7 +245,7 @@ func (s *realPartyServer) GetLastNPartiesOfAUser(userId string, n int) (*Parties
 		}, nil
 	}
 
-	parties, err := s.populateIntoParties(partiesDto)
+	parties, err := s.populateIntoParties(ctx, partiesDto)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -247,13 +259,15 @@ type ReviewMemberQuery struct {
 	Reviewee string
 }
 
-func (s *realPartyServer) SearchPartyReviewMember(reviewMemberQuery *ReviewMemberQuery) ([]*PartyReviewMember, error) {
+func (s *realPartyServer) SearchPartyReviewMember(ctx context.Context, reviewMemberQuery *ReviewMemberQuery) ([]*PartyReviewMember, error) {
+	ctx, span := tracing.Start(ctx, "partyservice.SearchPartyReviewMember")
+	defer span.End()
 	var queryDto = &ReviewMemberQueryDto{
 		partyID:  int64(reviewMemberQuery.PartyID),
 		reviewer: reviewMemberQuery.Reviewer,
 		reviewee: reviewMemberQuery.Reviewee,
 	}
-	retDtos, err := s.partyQueryRepository.QueryPartyReviewMembers(queryDto)
+	retDtos, err := s.partyQueryRepository.QueryPartyReviewMembers(ctx, queryDto)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -271,9 +285,9 @@ func (s *realPartyServer) SearchPartyReviewMember(reviewMemberQuery *ReviewMembe
 	return ret, nil
 }
 
-func (s *realPartyServer) tran(txFunc func(*sql.Tx) (interface{}, error)) (data interface{}, err error) {
+func (s *realPartyServer) tran(ctx context.Context, txFunc func(*sql.Tx) (interface{}, error)) (data interface{}, err error) {
 	// [Note] Make Tran, Rollback, Commit as Interface method for Dependency Injection
-	tx, err := s.partyCommandRepository.Tran()
+	tx, err := s.partyCommandRepository.Tran(ctx)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -299,13 +313,15 @@ type PartyReviewMember struct {
 	Comment  string  `json:"comment" validate:"omitempty,min=0,max=300"`
 }
 
-func (s *realPartyServer) PostPartyReviewMember(reviewMember *PartyReviewMember) error {
+func (s *realPartyServer) PostPartyReviewMember(ctx context.Context, reviewMember *PartyReviewMember) error {
+	ctx, span := tracing.Start(ctx, "partyservice.PostPartyReviewMember")
+	defer span.End()
 	// Validation
 	if err := Validate(reviewMember); err != nil {
 		return domainerror.NewValidationError(err)
 	}
 
-	_, err := s.tran(func(tx *sql.Tx) (interface{}, error) {
+	_, err := s.tran(ctx, func(tx *sql.Tx) (interface{}, error) {
 		reviewMemberDto := &PartyMemberReviewDto{
 			partyID:  int64(reviewMember.PartyID),
 			reviewer: reviewMember.Reviewer,
@@ -314,7 +330,7 @@ func (s *realPartyServer) PostPartyReviewMember(reviewMember *PartyReviewMember)
 			comments: reviewMember.Comment,
 		}
 
-		err := s.partyCommandRepository.InsertPartyMemberReview(tx, reviewMemberDto)
+		err := s.partyCommandRepository.InsertPartyMemberReview(ctx, tx, reviewMemberDto)
 		if err != nil {
 			var repoErr RepositoryError
 			if errors.As(err, &repoErr) {
@@ -336,13 +352,15 @@ func (s *realPartyServer) PostPartyReviewMember(reviewMember *PartyReviewMember)
 	return nil
 }
 
-func (s *realPartyServer) UpsertParties(partyModels []*PartyForCommand) error {
-	_, err := s.tran(func(tx *sql.Tx) (interface{}, error) {
+func (s *realPartyServer) UpsertParties(ctx context.Context, partyModels []*PartyForCommand) error {
+	ctx, span := tracing.Start(ctx, "partyservice.UpsertParties")
+	defer span.End()
+	_, err := s.tran(ctx, func(tx *sql.Tx) (interface{}, error) {
 		if len(partyModels) < 1 {
 			return nil, nil
 		}
 		// Delete the existing date's parties
-		err := s.partyCommandRepository.DeletePartiesWithADay(tx, partyModels[0].StartFrom)
+		err := s.partyCommandRepository.DeletePartiesWithADay(ctx, tx, partyModels[0].StartFrom)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -358,7 +376,7 @@ func (s *realPartyServer) UpsertParties(partyModels []*PartyForCommand) error {
 				chatRoomId:    utils.NewNullString(party.ChatRoomId),
 				memberUserIDs: memUserIDs,
 			}
-			_, err := s.partyCommandRepository.InsertParty(tx, &partyDto)
+			_, err := s.partyCommandRepository.InsertParty(ctx, tx, &partyDto)
 			if err != nil {
 				return nil, stew.Wrap(err)
 			}
@@ -373,8 +391,23 @@ func (s *realPartyServer) UpsertParties(partyModels []*PartyForCommand) error {
 }
 
 // GenerateChatRoom generates chat room of a party in storage service for app users
-func (s *realPartyServer) GenerateChatRoom(chatRoomId string) error {
-	return s.chatRoomRepository.CreateChatRoom(chatRoomId)
+func (s *realPartyServer) GenerateChatRoom(ctx context.Context, chatRoomId string) error {
+	ctx, span := tracing.Start(ctx, "partyservice.GenerateChatRoom")
+	defer span.End()
+	return s.chatRoomRepository.CreateChatRoom(ctx, chatRoomId)
+}
+
+// AnonymizeChatRooms anonymizes the user in the chat rooms, which are the ones of the parties the user has joined.
+// Nothing else is removed as the chat room in Firestore keeps no members but the messages.
+func (s *realPartyServer) AnonymizeChatRooms(ctx context.Context, userId string, chatRoomIds []string) error {
+	ctx, span := tracing.Start(ctx, "partyservice.AnonymizeChatRooms")
+	defer span.End()
+	for _, chatRoomId := range chatRoomIds {
+		if err := s.chatRoomRepository.AnonymizeChatRoomMessages(ctx, chatRoomId, userId); err != nil {
+			return stew.Wrap(err)
+		}
+	}
+	return nil
 }
 
 func parseBeginEndDateTime(beginDateTimeStr, endDateTimeStr string) (begin *time.Time, end *time.Time, err error) {
@@ -1,6 +1,7 @@
 package partyservice
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -39,7 +40,7 @@ func TestGetPartiesByTimeRange_InvalidDatetimeString_ThrowSpecificError(t *testi
 	// How to act and assert
 	test := func(t *testing.T, begin, end string) {
 		// Act
-		parties, err := partyServer.GetParties(begin, end)
+		parties, err := partyServer.GetParties(context.Background(), begin, end)
 		// Assert
 		var e *InvalidDateTimeFormatError
 		if !errors.As(err, &e) {
@@ -79,7 +80,7 @@ func TestGetPartyByUserIdAndTimeRange_InvalidBeginDatetimeString_ThrowSpecificEr
 	/// How to act and assert
 	test := func(t *testing.T, userId, begin, end string) {
 		// Act
-		parties, err := partyServer.GetPartyByUserIdAndTimeRange(userId, begin, end)
+		parties, err := partyServer.GetPartyByUserIdAndTimeRange(context.Background(), userId, begin, end)
 		// Assert
 		var e *InvalidDateTimeFormatError
 		if !errors.As(err, &e) {
@@ -135,7 +136,7 @@ func TestGetLastNPartiesOfAUser(t *testing.T) {
 	partyQueryRepoMock := NewMockIPartyQueryRepository(mockCtrl)
 
 	partyQueryRepoMock.EXPECT().
-		QueryPartyMembersWherePartyId(gomock.Any()).
+		QueryPartyMembersWherePartyId(gomock.Any(), gomock.Any()).
 		Return([]*PartyMemberDto{
 			{
 				partyId: 1,
@@ -148,23 +149,23 @@ func TestGetLastNPartiesOfAUser(t *testing.T) {
 		}, nil).AnyTimes()
 
 	userServerMock := NewMockUserServer(mockCtrl)
-	userServerMock.EXPECT().GetUserPublicByUserId(gomock.Any()).
+	userServerMock.EXPECT().GetUserPublicByUserId(gomock.Any(), gomock.Any()).
 		Return(&userservice.UserPublic{
 			UserId: "lunch-mate",
 		}, nil).AnyTimes()
 
-	partyQueryRepoMock.EXPECT().QueryPartyTagsWherePartyId(gomock.Any()).
+	partyQueryRepoMock.EXPECT().QueryPartyTagsWherePartyId(gomock.Any(), gomock.Any()).
 		Return(&PartyTagsDto{
 			tagIds: []uint16{24, 56, 60}, // random
 		}, nil).AnyTimes()
 
 	tagServerMock := testmock.NewMockTagServer(mockCtrl)
-	tagServerMock.EXPECT().GetTagsByTagTypeAndTagIds(gomock.Any(), gomock.Any()).
+	tagServerMock.EXPECT().GetTagsByTagTypeAndTagIds(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(nil, nil).AnyTimes()
 
 	assert := func(t *testing.T, partyServer PartyServer, n, expected int) {
 		// Act
-		parties, err := partyServer.GetLastNPartiesOfAUser(userID, n)
+		parties, err := partyServer.GetLastNPartiesOfAUser(context.Background(), userID, n)
 		if err != nil {
 			t.Errorf("expected: nil, got: %+v", err)
 		}
@@ -176,7 +177,7 @@ func TestGetLastNPartiesOfAUser(t *testing.T) {
 	t.Run("n is equal to parties", func(t *testing.T) {
 		n := 3
 		partyQueryRepoMock.EXPECT().
-			QueryPartiesWhereUserIdLastN(userID, n).
+			QueryPartiesWhereUserIdLastN(gomock.Any(), userID, n).
 			Return(partyDtos, nil)
 		partyServer := ProvidePartyServer(
 			partyQueryRepoMock,
@@ -191,7 +192,7 @@ func TestGetLastNPartiesOfAUser(t *testing.T) {
 	t.Run("n is more than parties", func(t *testing.T) {
 		n := 4
 		partyQueryRepoMock.EXPECT().
-			QueryPartiesWhereUserIdLastN(userID, n).
+			QueryPartiesWhereUserIdLastN(gomock.Any(), userID, n).
 			Return(partyDtos, nil)
 		partyServer := ProvidePartyServer(
 			partyQueryRepoMock,
@@ -207,7 +208,7 @@ func TestGetLastNPartiesOfAUser(t *testing.T) {
 		n := 3
 		partyDtos = []*PartyDto{}
 		partyQueryRepoMock.EXPECT().
-			QueryPartiesWhereUserIdLastN(userID, n).
+			QueryPartiesWhereUserIdLastN(gomock.Any(), userID, n).
 			Return(partyDtos, nil)
 		partyServer := ProvidePartyServer(
 			partyQueryRepoMock,
@@ -250,11 +251,11 @@ func TestUpsertParties_Successfully_Nil(t *testing.T) {
 	partyQueryRepository := NewMockIPartyQueryRepository(mockCtrl)
 	// Command
 	partyCommandRepository := NewMockIPartyCommandRepository(mockCtrl)
-	partyCommandRepository.EXPECT().Tran().Return(&sql.Tx{}, nil)
+	partyCommandRepository.EXPECT().Tran(gomock.Any()).Return(&sql.Tx{}, nil)
 	partyCommandRepository.EXPECT().Commit(gomock.Any()).Return(nil)
 	//partyCommandRepository.EXPECT().Rollback(gomock.Any()).Return(nil)
-	partyCommandRepository.EXPECT().InsertParty(gomock.Any(), gomock.Any()).Return(anyInt64, nil).AnyTimes()
-	partyCommandRepository.EXPECT().DeletePartiesWithADay(gomock.Any(), gomock.Any()).Return(nil)
+	partyCommandRepository.EXPECT().InsertParty(gomock.Any(), gomock.Any(), gomock.Any()).Return(anyInt64, nil).AnyTimes()
+	partyCommandRepository.EXPECT().DeletePartiesWithADay(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 	partyServer := ProvidePartyServer(
 		partyQueryRepository,
 		partyCommandRepository,
@@ -263,9 +264,32 @@ func TestUpsertParties_Successfully_Nil(t *testing.T) {
 		NewMockIChatRoomRepository(mockCtrl))
 
 	// Act
-	err := partyServer.UpsertParties(parties)
+	err := partyServer.UpsertParties(context.Background(), parties)
 	// Assert
 	if err != nil {
 		t.Errorf("Test failed. Expected: nil', Actual: %v", err)
 	}
 }
+
+func TestAnonymizeChatRooms(t *testing.T) {
+	// Arrange
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	chatRoomRepositoryMock := NewMockIChatRoomRepository(mockCtrl)
+	gomock.InOrder(
+		chatRoomRepositoryMock.EXPECT().AnonymizeChatRoomMessages(gomock.Any(), "room1", uid).Return(nil),
+		chatRoomRepositoryMock.EXPECT().AnonymizeChatRoomMessages(gomock.Any(), "room3", uid).Return(nil),
+	)
+	partyServer := ProvidePartyServer(
+		NewMockIPartyQueryRepository(mockCtrl),
+		NewMockIPartyCommandRepository(mockCtrl),
+		NewMockUserServer(mockCtrl),
+		testmock.NewMockTagServer(mockCtrl),
+		chatRoomRepositoryMock)
+	// Act
+	err := partyServer.AnonymizeChatRooms(context.Background(), uid, []string{"room1", "room3"})
+	// Assert
+	if err != nil {
+		t.Errorf("expected: nil, actual: %+v", err)
+	}
+}
@@ -1,7 +1,7 @@
 package partyservice
 
 import (
-	"fmt"
+	"net/http"
 
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
 )
@@ -12,6 +12,39 @@ const (
 	InconsistencyReviewErrorCode
 )
 
+func init() {
+	domainerror.Register(domainerror.Definition{
+		Code:        InvalidDateTimeFormatCode,
+		Name:        "invalid_party_datetime_format",
+		Description: "The datetime of the party query is not in RFC3339.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "Specified datetime format is wrong. Use RFC3339 format. Your datetime: %s",
+			domainerror.Japanese: "日時の形式が正しくありません。RFC3339形式で指定してください。指定された日時: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        DuplicateReviewErrorCode,
+		Name:        "duplicate_review",
+		Description: "The reviewer already reviewed the reviewee in the party.",
+		HTTPStatus:  http.StatusConflict,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The review is already posted. party_id: %d, reviewer: %s, reviewee: %s",
+			domainerror.Japanese: "このレビューは既に投稿されています。party_id: %d, reviewer: %s, reviewee: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        InconsistencyReviewErrorCode,
+		Name:        "inconsistent_review",
+		Description: "The reviewer or the reviewee is not a member of the party.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The review post has incosistency. Check party_id: %d, reviewer: %s, reviewee: %s",
+			domainerror.Japanese: "レビューの投稿に不整合があります。party_id: %d, reviewer: %s, reviewee: %s を確認してください",
+		},
+	})
+}
+
 // InvalidDateTimeFormat
 
 type InvalidDateTimeFormatError struct {
@@ -27,14 +60,21 @@ func NewInvalidDateTimeFormatError(specifiedDateTimeStr string) *InvalidDateTime
 }
 
 func (e *InvalidDateTimeFormatError) Error() string {
-	return fmt.Sprintf("Specified datetime format is wrong. Use RFC3339 format. Your datetime: %s",
-		e.SpecifiedDateTimeStr)
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *InvalidDateTimeFormatError) MessageArgs() []interface{} {
+	return []interface{}{e.SpecifiedDateTimeStr}
 }
 
 func (e *InvalidDateTimeFormatError) Code() domainerror.ErrorCode {
 	return InvalidDateTimeFormatCode
 }
 
+func (e *InvalidDateTimeFormatError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 type DuplicateReviewError struct {
 	PartyID  int
 	Reviewer string
@@ -52,15 +92,21 @@ func NewDuplicateReviewError(partyID int, reviewer, reviewee string) *DuplicateR
 }
 
 func (e *DuplicateReviewError) Error() string {
-	return fmt.Sprintf("The review is already posted. party_id: %d, reviewer: %s, reviewee: %s",
-		e.PartyID, e.Reviewer, e.Reviewee,
-	)
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *DuplicateReviewError) MessageArgs() []interface{} {
+	return []interface{}{e.PartyID, e.Reviewer, e.Reviewee}
 }
 
 func (e *DuplicateReviewError) Code() domainerror.ErrorCode {
 	return DuplicateReviewErrorCode
 }
 
+func (e *DuplicateReviewError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 type InconsistencyReviewError struct {
 	PartyID  int
 	Reviewer string
@@ -78,11 +124,17 @@ func NewInconsistencyReviewError(partyID int, reviewer, reviewee string) *Incons
 }
 
 func (e *InconsistencyReviewError) Error() string {
-	return fmt.Sprintf("The review post has incosistency. Check party_id: %d, reviewer: %s, reviewee: %s",
-		e.PartyID, e.Reviewer, e.Reviewee,
-	)
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *InconsistencyReviewError) MessageArgs() []interface{} {
+	return []interface{}{e.PartyID, e.Reviewer, e.Reviewee}
 }
 
 func (e *InconsistencyReviewError) Code() domainerror.ErrorCode {
 	return InconsistencyReviewErrorCode
 }
+
+func (e *InconsistencyReviewError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
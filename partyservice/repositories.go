@@ -6,9 +6,12 @@ import (
 	"errors"
 	"time"
 
+	"cloud.google.com/go/firestore"
 	"github.com/go-sql-driver/mysql"
 	"github.com/huandu/go-sqlbuilder"
 	"github.com/momotaro98/stew"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type PartyDto struct {
@@ -30,12 +33,12 @@ type PartyTagsDto struct {
 }
 
 type IPartyQueryRepository interface {
-	QueryPartiesWhereTimeRange(queryDto *PartyQueryDto) ([]*PartyDto, error)
-	QueryPartiesWhereUserIdAndTimeRange(userId string, queryDto *PartyQueryDto) ([]*PartyDto, error)
-	QueryPartiesWhereUserIdLastN(userId string, n int) ([]*PartyDto, error)
-	QueryPartyMembersWherePartyId(partyId int64) ([]*PartyMemberDto, error)
-	QueryPartyTagsWherePartyId(partyId int64) (*PartyTagsDto, error)
-	QueryPartyReviewMembers(queryDto *ReviewMemberQueryDto) ([]*PartyMemberReviewDto, error)
+	QueryPartiesWhereTimeRange(ctx context.Context, queryDto *PartyQueryDto) ([]*PartyDto, error)
+	QueryPartiesWhereUserIdAndTimeRange(ctx context.Context, userId string, queryDto *PartyQueryDto) ([]*PartyDto, error)
+	QueryPartiesWhereUserIdLastN(ctx context.Context, userId string, n int) ([]*PartyDto, error)
+	QueryPartyMembersWherePartyId(ctx context.Context, partyId int64) ([]*PartyMemberDto, error)
+	QueryPartyTagsWherePartyId(ctx context.Context, partyId int64) (*PartyTagsDto, error)
+	QueryPartyReviewMembers(ctx context.Context, queryDto *ReviewMemberQueryDto) ([]*PartyMemberReviewDto, error)
 }
 
 var _ IPartyQueryRepository = (*realPartyQueryRepository)(nil)
@@ -70,9 +73,9 @@ func buildSQLForQueryPartiesWhereTimeRange(queryDto *PartyQueryDto) (sql string,
 	return sb.Build()
 }
 
-func (r *realPartyQueryRepository) QueryPartiesWhereTimeRange(queryDto *PartyQueryDto) ([]*PartyDto, error) {
+func (r *realPartyQueryRepository) QueryPartiesWhereTimeRange(ctx context.Context, queryDto *PartyQueryDto) ([]*PartyDto, error) {
 	query, args := buildSQLForQueryPartiesWhereTimeRange(queryDto)
-	return r.queryPartyDtos(query, args...)
+	return r.queryPartyDtos(ctx, query, args...)
 }
 
 func buildSQLForQueryPartiesWhereUserIdAndTimeRange(userId string, queryDto *PartyQueryDto) (sql string, args []interface{}) {
@@ -93,13 +96,13 @@ func buildSQLForQueryPartiesWhereUserIdAndTimeRange(userId string, queryDto *Par
 	return sb.Build()
 }
 
-func (r *realPartyQueryRepository) QueryPartiesWhereUserIdAndTimeRange(userId string, queryDto *PartyQueryDto) ([]*PartyDto, error) {
+func (r *realPartyQueryRepository) QueryPartiesWhereUserIdAndTimeRange(ctx context.Context, userId string, queryDto *PartyQueryDto) ([]*PartyDto, error) {
 	query, args := buildSQLForQueryPartiesWhereUserIdAndTimeRange(userId, queryDto)
-	return r.queryPartyDtos(query, args...)
+	return r.queryPartyDtos(ctx, query, args...)
 }
 
-func (r *realPartyQueryRepository) QueryPartiesWhereUserIdLastN(userId string, n int) ([]*PartyDto, error) {
-	return r.queryPartyDtos(`
+func (r *realPartyQueryRepository) QueryPartiesWhereUserIdLastN(ctx context.Context, userId string, n int) ([]*PartyDto, error) {
+	return r.queryPartyDtos(ctx, `
 		SELECT p.id, p.startFrom, p.endTo, p.chatRoomId
 		FROM partymembers pm
 		INNER JOIN parties p ON pm.partyId = p.id
@@ -111,9 +114,9 @@ func (r *realPartyQueryRepository) QueryPartiesWhereUserIdLastN(userId string, n
 	)
 }
 
-func (r *realPartyQueryRepository) queryPartyDtos(query string, args ...interface{}) ([]*PartyDto, error) {
+func (r *realPartyQueryRepository) queryPartyDtos(ctx context.Context, query string, args ...interface{}) ([]*PartyDto, error) {
 	var partyDtos []*PartyDto
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -127,9 +130,9 @@ func (r *realPartyQueryRepository) queryPartyDtos(query string, args ...interfac
 	return partyDtos, nil
 }
 
-func (r *realPartyQueryRepository) QueryPartyMembersWherePartyId(partyId int64) ([]*PartyMemberDto, error) {
+func (r *realPartyQueryRepository) QueryPartyMembersWherePartyId(ctx context.Context, partyId int64) ([]*PartyMemberDto, error) {
 	var pMemberDtos []*PartyMemberDto
-	rows, err := r.db.Query(`
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT pm.partyMemberId, pm.userId, pm.partyId
 		FROM partymembers pm
 		WHERE pm.partyId = ?`,
@@ -147,8 +150,8 @@ func (r *realPartyQueryRepository) QueryPartyMembersWherePartyId(partyId int64)
 	return pMemberDtos, nil
 }
 
-func (r *realPartyQueryRepository) QueryPartyTagsWherePartyId(partyId int64) (*PartyTagsDto, error) {
-	rows, err := r.db.Query(`
+func (r *realPartyQueryRepository) QueryPartyTagsWherePartyId(ctx context.Context, partyId int64) (*PartyTagsDto, error) {
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT partyId, tagId
 		FROM partytags
 		where partyId = ?`,
@@ -205,12 +208,12 @@ func buildSQLForQueryPartyReviewMembers(queryDto *ReviewMemberQueryDto) (sql str
 	return sb.Build()
 }
 
-func (r *realPartyQueryRepository) QueryPartyReviewMembers(queryDto *ReviewMemberQueryDto) ([]*PartyMemberReviewDto, error) {
+func (r *realPartyQueryRepository) QueryPartyReviewMembers(ctx context.Context, queryDto *ReviewMemberQueryDto) ([]*PartyMemberReviewDto, error) {
 	// Build query
 	query, args := buildSQLForQueryPartyReviewMembers(queryDto)
 	// Query
 	var retDtos []*PartyMemberReviewDto
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -225,12 +228,12 @@ func (r *realPartyQueryRepository) QueryPartyReviewMembers(queryDto *ReviewMembe
 }
 
 type IPartyCommandRepository interface {
-	Tran() (*sql.Tx, error)
+	Tran(ctx context.Context) (*sql.Tx, error)
 	Commit(*sql.Tx) error
 	Rollback(*sql.Tx) error
-	InsertParty(tx *sql.Tx, dto *PartyCommandDto) (int64, error)
-	DeletePartiesWithADay(tx *sql.Tx, targetDay time.Time) error
-	InsertPartyMemberReview(tx *sql.Tx, dto *PartyMemberReviewDto) error
+	InsertParty(ctx context.Context, tx *sql.Tx, dto *PartyCommandDto) (int64, error)
+	DeletePartiesWithADay(ctx context.Context, tx *sql.Tx, targetDay time.Time) error
+	InsertPartyMemberReview(ctx context.Context, tx *sql.Tx, dto *PartyMemberReviewDto) error
 }
 
 var _ IPartyCommandRepository = (*realPartyCommandRepository)(nil)
@@ -253,8 +256,8 @@ type PartyCommandDto struct {
 	memberUserIDs []string
 }
 
-func (r *realPartyCommandRepository) Tran() (*sql.Tx, error) {
-	return r.db.DB.Begin()
+func (r *realPartyCommandRepository) Tran(ctx context.Context) (*sql.Tx, error) {
+	return r.db.DB.BeginTx(ctx, nil)
 }
 
 func (r *realPartyCommandRepository) Commit(tx *sql.Tx) error {
@@ -265,9 +268,9 @@ func (r *realPartyCommandRepository) Rollback(tx *sql.Tx) error {
 	return tx.Rollback()
 }
 
-func (r *realPartyCommandRepository) InsertParty(tx *sql.Tx, dto *PartyCommandDto) (int64, error) {
+func (r *realPartyCommandRepository) InsertParty(ctx context.Context, tx *sql.Tx, dto *PartyCommandDto) (int64, error) {
 	// Inserting to parties table
-	res, err := tx.Exec("INSERT INTO parties (startFrom, endTo, chatRoomId) VALUES (?, ?, ?)",
+	res, err := tx.ExecContext(ctx, "INSERT INTO parties (startFrom, endTo, chatRoomId) VALUES (?, ?, ?)",
 		dto.startFrom, dto.endTo, dto.chatRoomId)
 	if err != nil {
 		return 0, stew.Wrap(err)
@@ -279,7 +282,7 @@ func (r *realPartyCommandRepository) InsertParty(tx *sql.Tx, dto *PartyCommandDt
 
 	// Inserting to partymembers table
 	for _, userID := range dto.memberUserIDs {
-		_, err := tx.Exec("INSERT INTO partymembers (partyId, userId) VALUES (?, ?)",
+		_, err := tx.ExecContext(ctx, "INSERT INTO partymembers (partyId, userId) VALUES (?, ?)",
 			insertedPartyId, userID)
 		if err != nil {
 			return 0, stew.Wrap(err)
@@ -289,12 +292,12 @@ func (r *realPartyCommandRepository) InsertParty(tx *sql.Tx, dto *PartyCommandDt
 	return insertedPartyId, nil
 }
 
-func (r *realPartyCommandRepository) DeletePartiesWithADay(tx *sql.Tx, targetDay time.Time) error {
+func (r *realPartyCommandRepository) DeletePartiesWithADay(ctx context.Context, tx *sql.Tx, targetDay time.Time) error {
 	start := time.Date(targetDay.Year(), targetDay.Month(), targetDay.Day(),
 		0, 0, 0, 0, time.Local)
 	end := time.Date(targetDay.Year(), targetDay.Month(), targetDay.Day(),
 		23, 59, 59, 999, time.Local)
-	if _, err := tx.Exec(`
+	if _, err := tx.ExecContext(ctx, `
 		DELETE FROM parties
 		WHERE startFrom >= ? AND endTo <= ?`,
 		start, end); err != nil {
@@ -311,8 +314,8 @@ type PartyMemberReviewDto struct {
 	comments string
 }
 
-func (r *realPartyCommandRepository) InsertPartyMemberReview(tx *sql.Tx, dto *PartyMemberReviewDto) error {
-	_, err := tx.Exec(`
+func (r *realPartyCommandRepository) InsertPartyMemberReview(ctx context.Context, tx *sql.Tx, dto *PartyMemberReviewDto) error {
+	_, err := tx.ExecContext(ctx, `
 		INSERT INTO partymemberreviews (partyId, reviewer, reviewee, score, comments)
 		VALUES (?, ?, ?, ?, ?)`,
 		dto.partyID, dto.reviewer, dto.reviewee, dto.score, dto.comments)
@@ -332,7 +335,8 @@ func (r *realPartyCommandRepository) InsertPartyMemberReview(tx *sql.Tx, dto *Pa
 }
 
 type IChatRoomRepository interface {
-	CreateChatRoom(chatRoomId string) error
+	CreateChatRoom(ctx context.Context, chatRoomId string) error
+	AnonymizeChatRoomMessages(ctx context.Context, chatRoomId, userId string) error
 }
 
 var _ IChatRoomRepository = (*realChatRoomRepository)(nil)
@@ -350,25 +354,103 @@ func ProvideChatRoomRepository(app App) IChatRoomRepository {
 const (
 	document   = "rooms"
 	keyOfChats = "messages"
+	// erasedUserId replaces the user ID of an erased user in the chat rooms
+	erasedUserId = "erased-user"
 )
 
-func (r *realChatRoomRepository) CreateChatRoom(chatRoomId string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// newChatRoom is the document of a chat room, to whose messages the app adds the messages of the members
+func newChatRoom() map[string]interface{} {
+	return map[string]interface{}{
+		keyOfChats: []interface{}{},
+	}
+}
+
+func (r *realChatRoomRepository) CreateChatRoom(ctx context.Context, chatRoomId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	// Access to Firebase Cloud Firestore
 	// https://firebase.google.com/docs/firestore/manage-data/add-data
-	client, err := r.app.Firestore(ctx)
+	client, err := r.app.firestore()
+	if err != nil {
+		return stew.Wrap(err)
+	}
+
+	_, err = client.Collection(document).Doc(chatRoomId).Set(ctx, newChatRoom())
 	if err != nil {
 		return stew.Wrap(err)
 	}
-	defer client.Close()
+	return nil
+}
+
+// AnonymizeChatRoomMessages replaces the user ID in the messages of the chat room with erasedUserId
+// in a transaction not to lose the messages added meanwhile.
+// The chat room not in Firestore has no messages to be anonymized.
+func (r *realChatRoomRepository) AnonymizeChatRoomMessages(ctx context.Context, chatRoomId, userId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-	_, err = client.Collection(document).Doc(chatRoomId).Set(ctx, map[string]interface{}{
-		keyOfChats: []string{},
+	client, err := r.app.firestore()
+	if err != nil {
+		return stew.Wrap(err)
+	}
+
+	room := client.Collection(document).Doc(chatRoomId)
+	err = client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snapshot, err := tx.Get(room)
+		if err != nil {
+			return err
+		}
+		messages, _ := snapshot.Data()[keyOfChats].([]interface{})
+		anonymized, changed := anonymizeMessages(messages, userId)
+		if !changed {
+			return nil
+		}
+		return tx.Update(room, []firestore.Update{
+			{Path: keyOfChats, Value: anonymized},
+		})
 	})
 	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
 		return stew.Wrap(err)
 	}
 	return nil
 }
+
+// anonymizeMessages returns the messages whose values of the user ID are replaced with erasedUserId,
+// and whether any is replaced.
+// The layout of a message is up to the app so that the user ID is replaced wherever it is in the message.
+func anonymizeMessages(messages []interface{}, userId string) ([]interface{}, bool) {
+	anonymized, changed := anonymizeValue(messages, userId)
+	return anonymized.([]interface{}), changed
+}
+
+func anonymizeValue(v interface{}, userId string) (interface{}, bool) {
+	switch v := v.(type) {
+	case string:
+		if v == userId {
+			return erasedUserId, true
+		}
+		return v, false
+	case []interface{}:
+		ret, changed := make([]interface{}, len(v)), false
+		for i, e := range v {
+			var c bool
+			ret[i], c = anonymizeValue(e, userId)
+			changed = changed || c
+		}
+		return ret, changed
+	case map[string]interface{}:
+		ret, changed := make(map[string]interface{}, len(v)), false
+		for k, e := range v {
+			var c bool
+			ret[k], c = anonymizeValue(e, userId)
+			changed = changed || c
+		}
+		return ret, changed
+	default:
+		return v, false
+	}
+}
@@ -5,6 +5,7 @@
 package partyservice
 
 import (
+	context "context"
 	sql "database/sql"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -35,93 +36,93 @@ func (m *MockIPartyQueryRepository) EXPECT() *MockIPartyQueryRepositoryMockRecor
 }
 
 // QueryPartiesWhereTimeRange mocks base method
-func (m *MockIPartyQueryRepository) QueryPartiesWhereTimeRange(queryDto *PartyQueryDto) ([]*PartyDto, error) {
+func (m *MockIPartyQueryRepository) QueryPartiesWhereTimeRange(ctx context.Context, queryDto *PartyQueryDto) ([]*PartyDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryPartiesWhereTimeRange", queryDto)
+	ret := m.ctrl.Call(m, "QueryPartiesWhereTimeRange", ctx, queryDto)
 	ret0, _ := ret[0].([]*PartyDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryPartiesWhereTimeRange indicates an expected call of QueryPartiesWhereTimeRange
-func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartiesWhereTimeRange(queryDto interface{}) *gomock.Call {
+func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartiesWhereTimeRange(ctx, queryDto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartiesWhereTimeRange", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartiesWhereTimeRange), queryDto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartiesWhereTimeRange", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartiesWhereTimeRange), ctx, queryDto)
 }
 
 // QueryPartiesWhereUserIdAndTimeRange mocks base method
-func (m *MockIPartyQueryRepository) QueryPartiesWhereUserIdAndTimeRange(userId string, queryDto *PartyQueryDto) ([]*PartyDto, error) {
+func (m *MockIPartyQueryRepository) QueryPartiesWhereUserIdAndTimeRange(ctx context.Context, userId string, queryDto *PartyQueryDto) ([]*PartyDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryPartiesWhereUserIdAndTimeRange", userId, queryDto)
+	ret := m.ctrl.Call(m, "QueryPartiesWhereUserIdAndTimeRange", ctx, userId, queryDto)
 	ret0, _ := ret[0].([]*PartyDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryPartiesWhereUserIdAndTimeRange indicates an expected call of QueryPartiesWhereUserIdAndTimeRange
-func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartiesWhereUserIdAndTimeRange(userId, queryDto interface{}) *gomock.Call {
+func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartiesWhereUserIdAndTimeRange(ctx, userId, queryDto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartiesWhereUserIdAndTimeRange", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartiesWhereUserIdAndTimeRange), userId, queryDto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartiesWhereUserIdAndTimeRange", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartiesWhereUserIdAndTimeRange), ctx, userId, queryDto)
 }
 
 // QueryPartiesWhereUserIdLastN mocks base method
-func (m *MockIPartyQueryRepository) QueryPartiesWhereUserIdLastN(userId string, n int) ([]*PartyDto, error) {
+func (m *MockIPartyQueryRepository) QueryPartiesWhereUserIdLastN(ctx context.Context, userId string, n int) ([]*PartyDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryPartiesWhereUserIdLastN", userId, n)
+	ret := m.ctrl.Call(m, "QueryPartiesWhereUserIdLastN", ctx, userId, n)
 	ret0, _ := ret[0].([]*PartyDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryPartiesWhereUserIdLastN indicates an expected call of QueryPartiesWhereUserIdLastN
-func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartiesWhereUserIdLastN(userId, n interface{}) *gomock.Call {
+func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartiesWhereUserIdLastN(ctx, userId, n interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartiesWhereUserIdLastN", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartiesWhereUserIdLastN), userId, n)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartiesWhereUserIdLastN", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartiesWhereUserIdLastN), ctx, userId, n)
 }
 
 // QueryPartyMembersWherePartyId mocks base method
-func (m *MockIPartyQueryRepository) QueryPartyMembersWherePartyId(partyId int64) ([]*PartyMemberDto, error) {
+func (m *MockIPartyQueryRepository) QueryPartyMembersWherePartyId(ctx context.Context, partyId int64) ([]*PartyMemberDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryPartyMembersWherePartyId", partyId)
+	ret := m.ctrl.Call(m, "QueryPartyMembersWherePartyId", ctx, partyId)
 	ret0, _ := ret[0].([]*PartyMemberDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryPartyMembersWherePartyId indicates an expected call of QueryPartyMembersWherePartyId
-func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartyMembersWherePartyId(partyId interface{}) *gomock.Call {
+func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartyMembersWherePartyId(ctx, partyId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartyMembersWherePartyId", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartyMembersWherePartyId), partyId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartyMembersWherePartyId", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartyMembersWherePartyId), ctx, partyId)
 }
 
 // QueryPartyTagsWherePartyId mocks base method
-func (m *MockIPartyQueryRepository) QueryPartyTagsWherePartyId(partyId int64) (*PartyTagsDto, error) {
+func (m *MockIPartyQueryRepository) QueryPartyTagsWherePartyId(ctx context.Context, partyId int64) (*PartyTagsDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryPartyTagsWherePartyId", partyId)
+	ret := m.ctrl.Call(m, "QueryPartyTagsWherePartyId", ctx, partyId)
 	ret0, _ := ret[0].(*PartyTagsDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryPartyTagsWherePartyId indicates an expected call of QueryPartyTagsWherePartyId
-func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartyTagsWherePartyId(partyId interface{}) *gomock.Call {
+func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartyTagsWherePartyId(ctx, partyId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartyTagsWherePartyId", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartyTagsWherePartyId), partyId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartyTagsWherePartyId", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartyTagsWherePartyId), ctx, partyId)
 }
 
 // QueryPartyReviewMembers mocks base method
-func (m *MockIPartyQueryRepository) QueryPartyReviewMembers(queryDto *ReviewMemberQueryDto) ([]*PartyMemberReviewDto, error) {
+func (m *MockIPartyQueryRepository) QueryPartyReviewMembers(ctx context.Context, queryDto *ReviewMemberQueryDto) ([]*PartyMemberReviewDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryPartyReviewMembers", queryDto)
+	ret := m.ctrl.Call(m, "QueryPartyReviewMembers", ctx, queryDto)
 	ret0, _ := ret[0].([]*PartyMemberReviewDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryPartyReviewMembers indicates an expected call of QueryPartyReviewMembers
-func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartyReviewMembers(queryDto interface{}) *gomock.Call {
+func (mr *MockIPartyQueryRepositoryMockRecorder) QueryPartyReviewMembers(ctx, queryDto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartyReviewMembers", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartyReviewMembers), queryDto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPartyReviewMembers", reflect.TypeOf((*MockIPartyQueryRepository)(nil).QueryPartyReviewMembers), ctx, queryDto)
 }
 
 // MockIPartyCommandRepository is a mock of IPartyCommandRepository interface
@@ -148,18 +149,18 @@ func (m *MockIPartyCommandRepository) EXPECT() *MockIPartyCommandRepositoryMockR
 }
 
 // Tran mocks base method
-func (m *MockIPartyCommandRepository) Tran() (*sql.Tx, error) {
+func (m *MockIPartyCommandRepository) Tran(ctx context.Context) (*sql.Tx, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Tran")
+	ret := m.ctrl.Call(m, "Tran", ctx)
 	ret0, _ := ret[0].(*sql.Tx)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Tran indicates an expected call of Tran
-func (mr *MockIPartyCommandRepositoryMockRecorder) Tran() *gomock.Call {
+func (mr *MockIPartyCommandRepositoryMockRecorder) Tran(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tran", reflect.TypeOf((*MockIPartyCommandRepository)(nil).Tran))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tran", reflect.TypeOf((*MockIPartyCommandRepository)(nil).Tran), ctx)
 }
 
 // Commit mocks base method
@@ -191,46 +192,46 @@ func (mr *MockIPartyCommandRepositoryMockRecorder) Rollback(arg0 interface{}) *g
 }
 
 // InsertParty mocks base method
-func (m *MockIPartyCommandRepository) InsertParty(tx *sql.Tx, dto *PartyCommandDto) (int64, error) {
+func (m *MockIPartyCommandRepository) InsertParty(ctx context.Context, tx *sql.Tx, dto *PartyCommandDto) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertParty", tx, dto)
+	ret := m.ctrl.Call(m, "InsertParty", ctx, tx, dto)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertParty indicates an expected call of InsertParty
-func (mr *MockIPartyCommandRepositoryMockRecorder) InsertParty(tx, dto interface{}) *gomock.Call {
+func (mr *MockIPartyCommandRepositoryMockRecorder) InsertParty(ctx, tx, dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertParty", reflect.TypeOf((*MockIPartyCommandRepository)(nil).InsertParty), tx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertParty", reflect.TypeOf((*MockIPartyCommandRepository)(nil).InsertParty), ctx, tx, dto)
 }
 
 // DeletePartiesWithADay mocks base method
-func (m *MockIPartyCommandRepository) DeletePartiesWithADay(tx *sql.Tx, targetDay time.Time) error {
+func (m *MockIPartyCommandRepository) DeletePartiesWithADay(ctx context.Context, tx *sql.Tx, targetDay time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePartiesWithADay", tx, targetDay)
+	ret := m.ctrl.Call(m, "DeletePartiesWithADay", ctx, tx, targetDay)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePartiesWithADay indicates an expected call of DeletePartiesWithADay
-func (mr *MockIPartyCommandRepositoryMockRecorder) DeletePartiesWithADay(tx, targetDay interface{}) *gomock.Call {
+func (mr *MockIPartyCommandRepositoryMockRecorder) DeletePartiesWithADay(ctx, tx, targetDay interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePartiesWithADay", reflect.TypeOf((*MockIPartyCommandRepository)(nil).DeletePartiesWithADay), tx, targetDay)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePartiesWithADay", reflect.TypeOf((*MockIPartyCommandRepository)(nil).DeletePartiesWithADay), ctx, tx, targetDay)
 }
 
 // InsertPartyMemberReview mocks base method
-func (m *MockIPartyCommandRepository) InsertPartyMemberReview(tx *sql.Tx, dto *PartyMemberReviewDto) error {
+func (m *MockIPartyCommandRepository) InsertPartyMemberReview(ctx context.Context, tx *sql.Tx, dto *PartyMemberReviewDto) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertPartyMemberReview", tx, dto)
+	ret := m.ctrl.Call(m, "InsertPartyMemberReview", ctx, tx, dto)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InsertPartyMemberReview indicates an expected call of InsertPartyMemberReview
-func (mr *MockIPartyCommandRepositoryMockRecorder) InsertPartyMemberReview(tx, dto interface{}) *gomock.Call {
+func (mr *MockIPartyCommandRepositoryMockRecorder) InsertPartyMemberReview(ctx, tx, dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPartyMemberReview", reflect.TypeOf((*MockIPartyCommandRepository)(nil).InsertPartyMemberReview), tx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPartyMemberReview", reflect.TypeOf((*MockIPartyCommandRepository)(nil).InsertPartyMemberReview), ctx, tx, dto)
 }
 
 // MockIChatRoomRepository is a mock of IChatRoomRepository interface
@@ -257,15 +258,29 @@ func (m *MockIChatRoomRepository) EXPECT() *MockIChatRoomRepositoryMockRecorder
 }
 
 // CreateChatRoom mocks base method
-func (m *MockIChatRoomRepository) CreateChatRoom(chatRoomId string) error {
+func (m *MockIChatRoomRepository) CreateChatRoom(ctx context.Context, chatRoomId string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateChatRoom", chatRoomId)
+	ret := m.ctrl.Call(m, "CreateChatRoom", ctx, chatRoomId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateChatRoom indicates an expected call of CreateChatRoom
-func (mr *MockIChatRoomRepositoryMockRecorder) CreateChatRoom(chatRoomId interface{}) *gomock.Call {
+func (mr *MockIChatRoomRepositoryMockRecorder) CreateChatRoom(ctx, chatRoomId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChatRoom", reflect.TypeOf((*MockIChatRoomRepository)(nil).CreateChatRoom), chatRoomId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChatRoom", reflect.TypeOf((*MockIChatRoomRepository)(nil).CreateChatRoom), ctx, chatRoomId)
+}
+
+// AnonymizeChatRoomMessages mocks base method
+func (m *MockIChatRoomRepository) AnonymizeChatRoomMessages(ctx context.Context, chatRoomId, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeChatRoomMessages", ctx, chatRoomId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeChatRoomMessages indicates an expected call of AnonymizeChatRoomMessages
+func (mr *MockIChatRoomRepositoryMockRecorder) AnonymizeChatRoomMessages(ctx, chatRoomId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeChatRoomMessages", reflect.TypeOf((*MockIChatRoomRepository)(nil).AnonymizeChatRoomMessages), ctx, chatRoomId, userId)
 }
@@ -1,6 +1,7 @@
 package partyservice
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -199,3 +200,44 @@ FROM partymemberreviews
 		assert(t, input, expSQL, expArgLen)
 	})
 }
+
+func TestAnonymizeMessages(t *testing.T) {
+	const userId = "erased"
+
+	t.Run("created chat room", func(t *testing.T) {
+		messages := newChatRoom()[keyOfChats].([]interface{})
+		anonymized, changed := anonymizeMessages(messages, userId)
+		if changed || len(anonymized) != 0 {
+			t.Errorf("expected: no change, actual: %v, %v", anonymized, changed)
+		}
+	})
+
+	t.Run("messages added by the app", func(t *testing.T) {
+		// The messages are added to the chat room created by CreateChatRoom
+		room := newChatRoom()
+		room[keyOfChats] = append(room[keyOfChats].([]interface{}),
+			map[string]interface{}{"text": "Hello", "createdAt": time.Unix(0, 0), "user": map[string]interface{}{"_id": userId, "name": "John"}},
+			map[string]interface{}{"text": "Hi", "createdAt": time.Unix(1, 0), "user": map[string]interface{}{"_id": "other", "name": "Ann"}},
+			map[string]interface{}{"text": "See you", "readBy": []interface{}{"other", userId}},
+		)
+		messages := room[keyOfChats].([]interface{})
+
+		anonymized, changed := anonymizeMessages(messages, userId)
+
+		if !changed {
+			t.Fatalf("expected: changed")
+		}
+		expected := []interface{}{
+			map[string]interface{}{"text": "Hello", "createdAt": time.Unix(0, 0), "user": map[string]interface{}{"_id": erasedUserId, "name": "John"}},
+			map[string]interface{}{"text": "Hi", "createdAt": time.Unix(1, 0), "user": map[string]interface{}{"_id": "other", "name": "Ann"}},
+			map[string]interface{}{"text": "See you", "readBy": []interface{}{"other", erasedUserId}},
+		}
+		if !reflect.DeepEqual(anonymized, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, anonymized)
+		}
+		// The messages read from Firestore are not modified
+		if messages[0].(map[string]interface{})["user"].(map[string]interface{})["_id"] != userId {
+			t.Errorf("the original messages are modified")
+		}
+	})
+}
@@ -5,6 +5,7 @@
 package partyservice
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	userservice "github.com/momotaro98/mixlunch-service-api/userservice"
 	reflect "reflect"
@@ -34,61 +35,121 @@ func (m *MockUserServer) EXPECT() *MockUserServerMockRecorder {
 }
 
 // GetUserByUserId mocks base method
-func (m *MockUserServer) GetUserByUserId(userId string) (*userservice.User, error) {
+func (m *MockUserServer) GetUserByUserId(ctx context.Context, userId string) (*userservice.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByUserId", userId)
+	ret := m.ctrl.Call(m, "GetUserByUserId", ctx, userId)
 	ret0, _ := ret[0].(*userservice.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserByUserId indicates an expected call of GetUserByUserId
-func (mr *MockUserServerMockRecorder) GetUserByUserId(userId interface{}) *gomock.Call {
+func (mr *MockUserServerMockRecorder) GetUserByUserId(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUserId", reflect.TypeOf((*MockUserServer)(nil).GetUserByUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUserId", reflect.TypeOf((*MockUserServer)(nil).GetUserByUserId), ctx, userId)
 }
 
 // GetUserPublicByUserId mocks base method
-func (m *MockUserServer) GetUserPublicByUserId(userId string) (*userservice.UserPublic, error) {
+func (m *MockUserServer) GetUserPublicByUserId(ctx context.Context, userId string) (*userservice.UserPublic, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserPublicByUserId", userId)
+	ret := m.ctrl.Call(m, "GetUserPublicByUserId", ctx, userId)
 	ret0, _ := ret[0].(*userservice.UserPublic)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserPublicByUserId indicates an expected call of GetUserPublicByUserId
-func (mr *MockUserServerMockRecorder) GetUserPublicByUserId(userId interface{}) *gomock.Call {
+func (mr *MockUserServerMockRecorder) GetUserPublicByUserId(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPublicByUserId", reflect.TypeOf((*MockUserServer)(nil).GetUserPublicByUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPublicByUserId", reflect.TypeOf((*MockUserServer)(nil).GetUserPublicByUserId), ctx, userId)
 }
 
 // RegisterUser mocks base method
-func (m *MockUserServer) RegisterUser(newUser *userservice.UserForCommand) (*userservice.User, error) {
+func (m *MockUserServer) RegisterUser(ctx context.Context, newUser *userservice.UserForCommand) (*userservice.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterUser", newUser)
+	ret := m.ctrl.Call(m, "RegisterUser", ctx, newUser)
 	ret0, _ := ret[0].(*userservice.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterUser indicates an expected call of RegisterUser
-func (mr *MockUserServerMockRecorder) RegisterUser(newUser interface{}) *gomock.Call {
+func (mr *MockUserServerMockRecorder) RegisterUser(ctx, newUser interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockUserServer)(nil).RegisterUser), newUser)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockUserServer)(nil).RegisterUser), ctx, newUser)
+}
+
+// UpdateUser mocks base method
+func (m *MockUserServer) UpdateUser(ctx context.Context, user *userservice.UserForCommand) (*userservice.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, user)
+	ret0, _ := ret[0].(*userservice.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser
+func (mr *MockUserServerMockRecorder) UpdateUser(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserServer)(nil).UpdateUser), ctx, user)
+}
+
+// PatchUser mocks base method
+func (m *MockUserServer) PatchUser(ctx context.Context, userId string, update *userservice.UserForUpdate) (*userservice.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchUser", ctx, userId, update)
+	ret0, _ := ret[0].(*userservice.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchUser indicates an expected call of PatchUser
+func (mr *MockUserServerMockRecorder) PatchUser(ctx, userId, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchUser", reflect.TypeOf((*MockUserServer)(nil).PatchUser), ctx, userId, update)
 }
 
 // RegisterUserBlock mocks base method
-func (m *MockUserServer) RegisterUserBlock(newUserBlock *userservice.UserBlockForCommand) ([]*userservice.UserBlockForQuery, error) {
+func (m *MockUserServer) RegisterUserBlock(ctx context.Context, newUserBlock *userservice.UserBlockForCommand) ([]*userservice.UserBlockForQuery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterUserBlock", newUserBlock)
+	ret := m.ctrl.Call(m, "RegisterUserBlock", ctx, newUserBlock)
 	ret0, _ := ret[0].([]*userservice.UserBlockForQuery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterUserBlock indicates an expected call of RegisterUserBlock
-func (mr *MockUserServerMockRecorder) RegisterUserBlock(newUserBlock interface{}) *gomock.Call {
+func (mr *MockUserServerMockRecorder) RegisterUserBlock(ctx, newUserBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUserBlock", reflect.TypeOf((*MockUserServer)(nil).RegisterUserBlock), ctx, newUserBlock)
+}
+
+// DeleteUserBlock mocks base method
+func (m *MockUserServer) DeleteUserBlock(ctx context.Context, userBlock *userservice.UserBlockForCommand) (*userservice.UserBlockForCommand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserBlock", ctx, userBlock)
+	ret0, _ := ret[0].(*userservice.UserBlockForCommand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserBlock indicates an expected call of DeleteUserBlock
+func (mr *MockUserServerMockRecorder) DeleteUserBlock(ctx, userBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserBlock", reflect.TypeOf((*MockUserServer)(nil).DeleteUserBlock), ctx, userBlock)
+}
+
+// GetUserBlocks mocks base method
+func (m *MockUserServer) GetUserBlocks(ctx context.Context, query *userservice.UserBlockPageQuery) (*userservice.UserBlockPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBlocks", ctx, query)
+	ret0, _ := ret[0].(*userservice.UserBlockPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBlocks indicates an expected call of GetUserBlocks
+func (mr *MockUserServerMockRecorder) GetUserBlocks(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUserBlock", reflect.TypeOf((*MockUserServer)(nil).RegisterUserBlock), newUserBlock)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBlocks", reflect.TypeOf((*MockUserServer)(nil).GetUserBlocks), ctx, query)
 }
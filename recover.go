@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+// RecoverMiddle converts a panic in the handlers into a logged 500 response
+// with the stack trace and the request ID instead of letting it crash the connection.
+func RecoverMiddle(l logger.Logger) MFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				// net/http uses ErrAbortHandler to abort the response on purpose
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				l.LogFields(r.Context(), logger.Error, fmt.Sprintf("panic: %v", p), logger.Fields{
+					"stack_trace": string(debug.Stack()),
+				})
+				// The status can't be changed once the handler has written it
+				if rec.status == 0 {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+// RequestIdMiddle takes the request ID of the x-request-id header or generates a new one when it's absent,
+// then stores it in the request context for the logger and sets it on the response header.
+func RequestIdMiddle(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqId := logger.RequestIdOrNew(r.Header.Get(XRequestId))
+		w.Header().Set(XRequestId, reqId)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestId(r.Context(), reqId)))
+	})
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/momotaro98/mixlunch-service-api/auth"
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
+	"github.com/momotaro98/mixlunch-service-api/logger"
+)
+
+const (
+	// sessionCookieName is the cookie AuthMiddle takes the session cookie from
+	sessionCookieName = "session"
+
+	// recentSignIn is how recently the user must have signed in to create a session,
+	// which Firebase recommends to limit the stolen ID tokens.
+	recentSignIn = 5 * time.Minute
+)
+
+// SessionHandler creates the sessions with POST and deletes them with DELETE.
+// DELETE must be inside AuthMiddle.
+type SessionHandler struct {
+	logger     logger.Logger
+	verifier   auth.TokenVerifier
+	sessions   auth.SessionManager
+	revocation *auth.RevocationChecker
+	// lifetime is the lifetime of the session cookies
+	lifetime time.Duration
+	// secure sends the cookie only over HTTPS
+	secure bool
+}
+
+func NewSessionHandler(l logger.Logger, verifier auth.TokenVerifier, sessions auth.SessionManager, revocation *auth.RevocationChecker, lifetime time.Duration, secure bool) *SessionHandler {
+	return &SessionHandler{
+		logger:     l,
+		verifier:   verifier,
+		sessions:   sessions,
+		revocation: revocation,
+		lifetime:   lifetime,
+		secure:     secure,
+	}
+}
+
+type SessionForCommand struct {
+	IdToken string `json:"id_token"`
+}
+
+// Session is the response of the created session.
+// The session cookie is only in the HttpOnly cookie, not to be readable by the scripts.
+type Session struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (h *SessionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.create(w, r)
+	case http.MethodDelete:
+		h.delete(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// create exchanges the ID token of a recent sign-in for a session cookie
+func (h *SessionHandler) create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var command SessionForCommand
+	if err := json.NewDecoder(r.Body).Decode(&command); err != nil {
+		h.logger.WithError(err).LogContext(ctx, logger.Error, "decoding the request failed")
+		responseWithDomainError(w, r, h.logger, domainerror.NewJSONParseError(r.URL.Path, err), http.StatusNotAcceptable)
+		return
+	}
+	if command.IdToken == "" {
+		handleError(w, r, h.logger, domainerror.NewNoneRequiredItemError("id_token"))
+		return
+	}
+
+	// Only an ID token makes a session, not a session cookie
+	token, err := h.verifier.VerifyIDToken(ctx, command.IdToken)
+	if errors.Is(err, auth.ErrKeysUnavailable) {
+		h.logger.WithError(err).LogContext(ctx, logger.Error, "error verifying token")
+		http.Error(w, "Authentication is unavailable.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		h.logger.WithError(err).LogContext(ctx, logger.Warn, "error verifying token")
+		http.Error(w, "Token is not valid.", http.StatusUnauthorized)
+		return
+	}
+	if time.Since(time.Unix(token.AuthTime, 0)) > recentSignIn {
+		h.logger.With("uid", token.UID).With("auth_time", token.AuthTime).LogContext(ctx, logger.Warn, "sign-in is not recent")
+		http.Error(w, "Recent sign-in is required.", http.StatusUnauthorized)
+		return
+	}
+	if revoked, err := h.revocation.Revoked(ctx, token); err != nil || revoked {
+		rejectRevoked(w, r, h.logger, token, err)
+		return
+	}
+	setAccessLogUid(ctx, token.UID)
+
+	cookie, err := h.sessions.CreateSessionCookie(ctx, command.IdToken, h.lifetime)
+	if err != nil {
+		h.logger.WithError(err).LogContext(ctx, logger.Error, "creating session cookie failed")
+		http.Error(w, "Session is unavailable.", http.StatusServiceUnavailable)
+		return
+	}
+	expiresAt := time.Now().Add(h.lifetime)
+	http.SetCookie(w, h.cookie(cookie, expiresAt))
+	responseWithSuccess(ctx, h.logger, &Session{
+		ExpiresAt: expiresAt,
+	}, w)
+}
+
+// delete revokes all the sessions of the caller, not only the current one,
+// since Firebase revokes the refresh tokens by user.
+func (h *SessionHandler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token, ok := auth.TokenFrom(ctx)
+	if !ok {
+		// The authentication is deactivated
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err := h.revocation.Revoke(ctx, token.UID); err != nil {
+		h.logger.WithError(err).LogContext(ctx, logger.Error, "revoking the sessions failed")
+		http.Error(w, "Session is unavailable.", http.StatusServiceUnavailable)
+		return
+	}
+	// Expire the cookie in the browser as well
+	http.SetCookie(w, h.cookie("", time.Unix(0, 0)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SessionHandler) cookie(value string, expires time.Time) *http.Cookie {
+	c := &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   h.secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if value == "" {
+		c.MaxAge = -1
+	} else {
+		c.MaxAge = int(h.lifetime / time.Second)
+	}
+	return c
+}
+
+// rejectRevoked responds to the token revoked, or failed to be checked with err
+func rejectRevoked(w http.ResponseWriter, r *http.Request, l logger.Logger, token *auth.Token, err error) {
+	ctx := r.Context()
+	if err != nil {
+		l.WithError(err).With("uid", token.UID).LogContext(ctx, logger.Error, "error checking revocation")
+		http.Error(w, "Authentication is unavailable.", http.StatusServiceUnavailable)
+		return
+	}
+	l.With("uid", token.UID).With("iat", token.IssuedAt).LogContext(ctx, logger.Warn, "token is revoked")
+	http.Error(w, "Token is revoked.", http.StatusUnauthorized)
+}
@@ -1,7 +1,11 @@
 package tagservice
 
 import (
+	"context"
+
 	"github.com/momotaro98/stew"
+
+	"github.com/momotaro98/mixlunch-service-api/tracing"
 )
 
 type TagType int8
@@ -62,8 +66,8 @@ func NewSmallTag(tagId uint16, name string) *SmallTag {
 }
 
 type TagServer interface {
-	GetTagsByTagType(tagType TagType) ([]*CategoryTags, error)
-	GetTagsByTagTypeAndTagIds(tagType TagType, tagIds []uint16) ([]*CategoryTags, error)
+	GetTagsByTagType(ctx context.Context, tagType TagType) ([]*CategoryTags, error)
+	GetTagsByTagTypeAndTagIds(ctx context.Context, tagType TagType, tagIds []uint16) ([]*CategoryTags, error)
 }
 
 type RealTagServer struct {
@@ -114,9 +118,11 @@ func (s *RealTagServer) handleFromTagDtosToTagModels(tagDtos []*TagQueryDto) []*
 
 // GetTagsByTagType gets tags by using tag type.
 // All tags can be got when passed TagType is "All".
-func (s *RealTagServer) GetTagsByTagType(tagType TagType) (categoryTagsList []*CategoryTags, err error) {
+func (s *RealTagServer) GetTagsByTagType(ctx context.Context, tagType TagType) (categoryTagsList []*CategoryTags, err error) {
+	ctx, span := tracing.Start(ctx, "tagservice.GetTagsByTagType")
+	defer span.End()
 	// Query tags
-	tagQueryDtos, err := s.tagQueryRepository.QueryTagsWhereTagType(uint8(tagType))
+	tagQueryDtos, err := s.tagQueryRepository.QueryTagsWhereTagType(ctx, uint8(tagType))
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -125,14 +131,16 @@ func (s *RealTagServer) GetTagsByTagType(tagType TagType) (categoryTagsList []*C
 
 // GetTagsByTagTypeAndTagIds gets tags by using tag type and tag IDs.
 // All tags can be got when passed TagType is "All".
-func (s *RealTagServer) GetTagsByTagTypeAndTagIds(tagType TagType, tagIds []uint16) ([]*CategoryTags, error) {
+func (s *RealTagServer) GetTagsByTagTypeAndTagIds(ctx context.Context, tagType TagType, tagIds []uint16) ([]*CategoryTags, error) {
+	ctx, span := tracing.Start(ctx, "tagservice.GetTagsByTagTypeAndTagIds")
+	defer span.End()
 	// [Issue]
 	// For now the method gets all of tag rows (extend to in-memory)
 	// then filters by specified tag IDs.
 	// Considering performance (large in-memory allocation), it should leave the filtering task to Database.
 
 	// Query tags
-	tagQueryDtos, err := s.tagQueryRepository.QueryTagsWhereTagType(uint8(tagType))
+	tagQueryDtos, err := s.tagQueryRepository.QueryTagsWhereTagType(ctx, uint8(tagType))
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
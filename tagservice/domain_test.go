@@ -1,6 +1,7 @@
 package tagservice
 
 import (
+	"context"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -34,11 +35,11 @@ func TestGetTagsByTagType_RegularAllCase_Success(t *testing.T) {
 	defer mockCtrl.Finish()
 	tagQueryRepositoryMock := NewMockITagQueryRepository(mockCtrl)
 	tagQueryRepositoryMock.EXPECT().
-		QueryTagsWhereTagType(uint8(All)).
+		QueryTagsWhereTagType(gomock.Any(), uint8(All)).
 		Return([]*TagQueryDto{tqDto1, tqDto2, tqDto3}, nil)
 	tagServer := ProvideTagServer(tagQueryRepositoryMock)
 	// Act
-	categoriesTags, _ := tagServer.GetTagsByTagType(All)
+	categoriesTags, _ := tagServer.GetTagsByTagType(context.Background(), All)
 	// Assert
 	if len(categoriesTags) != 2 {
 		t.Errorf("2 dayo")
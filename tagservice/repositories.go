@@ -1,6 +1,7 @@
 package tagservice
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/momotaro98/stew"
@@ -14,7 +15,7 @@ type TagQueryDto struct {
 }
 
 type ITagQueryRepository interface {
-	QueryTagsWhereTagType(tagTypeId uint8) ([]*TagQueryDto, error)
+	QueryTagsWhereTagType(ctx context.Context, tagTypeId uint8) ([]*TagQueryDto, error)
 }
 
 var _ ITagQueryRepository = (*realTagQueryRepository)(nil)
@@ -31,17 +32,17 @@ func ProvideTagQueryRepository(db SqlDb) ITagQueryRepository {
 
 // QueryTagsWhereTagType does query Tags master.
 // All tags (tag types) can be got when the passed tagTypeId is 0.
-func (r *realTagQueryRepository) QueryTagsWhereTagType(tagTypeId uint8) ([]*TagQueryDto, error) {
+func (r *realTagQueryRepository) QueryTagsWhereTagType(ctx context.Context, tagTypeId uint8) ([]*TagQueryDto, error) {
 	var err error
 	var rows *sql.Rows
 	if tagTypeId == 0 {
-		rows, err = r.db.Query(`
+		rows, err = r.db.QueryContext(ctx, `
 		SELECT t.tagId, t.name, c.categoryId, c.name
 		FROM tags t
 		INNER JOIN categories c ON t.categoryId = c.categoryId
 		ORDER BY c.categoryId ASC`)
 	} else {
-		rows, err = r.db.Query(`
+		rows, err = r.db.QueryContext(ctx, `
 		SELECT t.tagId, t.name, c.categoryId, c.name
 		FROM tags t
 		INNER JOIN categories c ON t.categoryId = c.categoryId
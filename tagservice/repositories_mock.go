@@ -5,6 +5,7 @@
 package tagservice
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -33,16 +34,16 @@ func (m *MockITagQueryRepository) EXPECT() *MockITagQueryRepositoryMockRecorder
 }
 
 // QueryTagsWhereTagType mocks base method
-func (m *MockITagQueryRepository) QueryTagsWhereTagType(tagTypeId uint8) ([]*TagQueryDto, error) {
+func (m *MockITagQueryRepository) QueryTagsWhereTagType(ctx context.Context, tagTypeId uint8) ([]*TagQueryDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryTagsWhereTagType", tagTypeId)
+	ret := m.ctrl.Call(m, "QueryTagsWhereTagType", ctx, tagTypeId)
 	ret0, _ := ret[0].([]*TagQueryDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryTagsWhereTagType indicates an expected call of QueryTagsWhereTagType
-func (mr *MockITagQueryRepositoryMockRecorder) QueryTagsWhereTagType(tagTypeId interface{}) *gomock.Call {
+func (mr *MockITagQueryRepositoryMockRecorder) QueryTagsWhereTagType(ctx, tagTypeId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTagsWhereTagType", reflect.TypeOf((*MockITagQueryRepository)(nil).QueryTagsWhereTagType), tagTypeId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTagsWhereTagType", reflect.TypeOf((*MockITagQueryRepository)(nil).QueryTagsWhereTagType), ctx, tagTypeId)
 }
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// TimeoutMiddle bounds the request context with the given timeout so that
+// services and repositories called from the handler are cancelled when it passes.
+// A zero or negative timeout leaves the request context as it is.
+func TimeoutMiddle(timeout time.Duration) MFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
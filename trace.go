@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+// TraceMiddle starts the server span of the request named by the method and the route template,
+// as the child of the caller's span in the traceparent header if any.
+// The spans of the service methods and the SQL statements of the request become its children.
+func TraceMiddle(router *mux.Router) MFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routeTemplate(router, r)
+			if route == "" {
+				// Not to make a span name per unknown path
+				route = "unmatched"
+			}
+			ctx := tracing.Extract(r.Context(), tracing.HeaderCarrier(r.Header))
+			ctx, span := tracing.StartKind(ctx, r.Method+" "+route, tracing.Server)
+			defer span.End()
+			rec := &responseRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			span.SetAttribute("http.method", r.Method)
+			span.SetAttribute("http.route", route)
+			span.SetAttribute("http.status_code", rec.status)
+			if rec.status >= http.StatusInternalServerError {
+				span.RecordError(fmt.Errorf("%d %s", rec.status, http.StatusText(rec.status)))
+			}
+		})
+	}
+}
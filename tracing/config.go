@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// The exporters selectable by Config
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Exporters are the names of the exporters selectable by Config
+var Exporters = []string{ExporterNone, ExporterStdout, ExporterOTLP}
+
+type Config struct {
+	// Exporter is one of Exporters
+	Exporter string
+	// OTLPEndpoint is the base URL of the OTLP/HTTP collector
+	OTLPEndpoint string
+	// SampleRatio is the ratio of the traces started by the service to be sampled, 0 to 1
+	SampleRatio float64
+	// ServiceName is the service.name of the spans exported by OTLP
+	ServiceName string
+}
+
+// otlpTimeout bounds each post to the collector
+const otlpTimeout = 10 * time.Second
+
+// New returns the tracer exporting to the exporter of the config, or nil for ExporterNone
+func New(conf *Config) (*Tracer, error) {
+	var exporter Exporter
+	switch conf.Exporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterStdout:
+		exporter = NewStdoutExporter(os.Stdout)
+	case ExporterOTLP:
+		exporter = NewOTLPExporter(conf.OTLPEndpoint, conf.ServiceName, &http.Client{Timeout: otlpTimeout})
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", conf.Exporter)
+	}
+	return NewTracer(exporter, conf.SampleRatio), nil
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Exporter sends the finished spans somewhere
+type Exporter interface {
+	// ExportSpan is called by Span.End, which must not block on I/O for long
+	ExportSpan(span *SpanData)
+	// Shutdown exports the spans held by the exporter
+	Shutdown(ctx context.Context) error
+}
+
+// StdoutExporter writes a JSON line per span, e.g. to see the spans in development
+type StdoutExporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{encoder: json.NewEncoder(w)}
+}
+
+type stdoutSpan struct {
+	Name         string                 `json:"name"`
+	Kind         string                 `json:"kind"`
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	StartTime    time.Time              `json:"start_time"`
+	DurationMs   float64                `json:"duration_ms"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+func (e *StdoutExporter) ExportSpan(span *SpanData) {
+	s := stdoutSpan{
+		Name:       span.Name,
+		Kind:       span.Kind.String(),
+		TraceID:    span.SpanContext.TraceID.String(),
+		SpanID:     span.SpanContext.SpanID.String(),
+		StartTime:  span.StartTime,
+		DurationMs: float64(span.EndTime.Sub(span.StartTime).Microseconds()) / 1000,
+		Attributes: span.Attributes,
+		Error:      span.Error,
+	}
+	if span.ParentSpanID.IsValid() {
+		s.ParentSpanID = span.ParentSpanID.String()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// Nowhere to report the failure of writing to stdout
+	_ = e.encoder.Encode(s)
+}
+
+func (e *StdoutExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// InMemoryExporter holds the spans for the tests
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(span *SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func (e *InMemoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans returns the spans in the order they are ended
+func (e *InMemoryExporter) Spans() []*SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	spans := make([]*SpanData, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+// Span returns the last span of the name or nil if none
+func (e *InMemoryExporter) Span(name string) *SpanData {
+	spans := e.Spans()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name == name {
+			return spans[i]
+		}
+	}
+	return nil
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
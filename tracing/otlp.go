@@ -0,0 +1,255 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	otlpTracesPath = "/v1/traces"
+	// otlpQueueSize bounds the spans waiting to be exported. The spans are dropped when it's full
+	// not to slow down the requests when the collector is unavailable.
+	otlpQueueSize = 2048
+	otlpBatchSize = 512
+	otlpInterval  = 5 * time.Second
+)
+
+// OTLPExporter posts the spans in batches to an OpenTelemetry collector by OTLP/HTTP in JSON
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+
+	queue chan *SpanData
+	// flush asks the loop to export the queued spans now
+	flush chan chan error
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewOTLPExporter starts the exporter posting to the endpoint, e.g. "http://localhost:4318".
+// The spans are of the resource of the service name.
+func NewOTLPExporter(endpoint, serviceName string, client *http.Client) *OTLPExporter {
+	e := &OTLPExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + otlpTracesPath,
+		serviceName: serviceName,
+		client:      client,
+		queue:       make(chan *SpanData, otlpQueueSize),
+		flush:       make(chan chan error),
+		done:        make(chan struct{}),
+	}
+	go e.loop()
+	return e
+}
+
+func (e *OTLPExporter) ExportSpan(span *SpanData) {
+	select {
+	case e.queue <- span:
+	case <-e.done:
+	default:
+		// Dropped as the queue is full
+	}
+}
+
+// Shutdown exports the queued spans and stops the exporter
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	errc := make(chan error, 1)
+	select {
+	case e.flush <- errc:
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	e.once.Do(func() { close(e.done) })
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *OTLPExporter) loop() {
+	ticker := time.NewTicker(otlpInterval)
+	defer ticker.Stop()
+	var batch []*SpanData
+	send := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := e.post(batch)
+		batch = nil
+		return err
+	}
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= otlpBatchSize {
+				e.logError(send())
+			}
+		case <-ticker.C:
+			e.logError(send())
+		case errc := <-e.flush:
+			for n := len(e.queue); n > 0; n-- {
+				batch = append(batch, <-e.queue)
+			}
+			errc <- send()
+			return
+		}
+	}
+}
+
+// logError logs the failure of the background export, which has no caller to return it to
+func (e *OTLPExporter) logError(err error) {
+	if err != nil {
+		log.Println("tracing:", err)
+	}
+}
+
+func (e *OTLPExporter) post(spans []*SpanData) error {
+	body, err := json.Marshal(otlpRequest(e.serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("otlp: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("otlp: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp: exporting %d spans failed: %v", len(spans), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("otlp: exporting %d spans failed: %s", len(spans), resp.Status)
+	}
+	return nil
+}
+
+// The JSON encoding of OTLP https://github.com/open-telemetry/opentelemetry-proto
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	TraceState        string         `json:"traceState,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// otlpStatusError is STATUS_CODE_ERROR
+const otlpStatusError = 2
+
+func otlpRequest(serviceName string, spans []*SpanData) *otlpTraces {
+	s := make([]otlpSpan, len(spans))
+	for i, span := range spans {
+		s[i] = otlpSpan{
+			TraceID:           span.SpanContext.TraceID.String(),
+			SpanID:            span.SpanContext.SpanID.String(),
+			TraceState:        span.SpanContext.TraceState,
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+		}
+		if span.ParentSpanID.IsValid() {
+			s[i].ParentSpanID = span.ParentSpanID.String()
+		}
+		if span.Error != "" {
+			s[i].Status = &otlpStatus{Code: otlpStatusError, Message: span.Error}
+		}
+	}
+	return &otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: otlpAttributes(map[string]interface{}{
+			"service.name": serviceName,
+		})},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/momotaro98/mixlunch-service-api/tracing"},
+			Spans: s,
+		}},
+	}}}
+}
+
+func otlpAttributes(attributes map[string]interface{}) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attributes))
+	for k, v := range attributes {
+		var value otlpAnyValue
+		switch v := v.(type) {
+		case string:
+			value.StringValue = &v
+		case bool:
+			value.BoolValue = &v
+		case int:
+			i := strconv.Itoa(v)
+			value.IntValue = &i
+		case int64:
+			i := strconv.FormatInt(v, 10)
+			value.IntValue = &i
+		case float64:
+			value.DoubleValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: value})
+	}
+	return kvs
+}
@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// The headers of W3C Trace Context https://www.w3.org/TR/trace-context/
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// Carrier carries the trace context between the services, e.g. the HTTP headers and the gRPC metadata
+type Carrier interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// HeaderCarrier is the Carrier of the HTTP headers
+type HeaderCarrier http.Header
+
+func (c HeaderCarrier) Get(key string) string {
+	return http.Header(c).Get(key)
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	http.Header(c).Set(key, value)
+}
+
+// MetadataCarrier is the Carrier of the gRPC metadata
+type MetadataCarrier metadata.MD
+
+func (c MetadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+type remoteKey struct{}
+
+// Extract returns the context holding the span context of the caller in the carrier,
+// which becomes the parent of the span started next. The context is returned as it is
+// if the carrier has no valid traceparent.
+func Extract(ctx context.Context, carrier Carrier) context.Context {
+	sc, err := parseTraceparent(carrier.Get(TraceparentHeader))
+	if err != nil {
+		return ctx
+	}
+	sc.TraceState = carrier.Get(TracestateHeader)
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// Inject sets the span context of the span in the context to the carrier to call another service
+func Inject(ctx context.Context, carrier Carrier) {
+	sc := SpanFromContext(ctx).SpanContext()
+	if !sc.IsValid() {
+		return
+	}
+	carrier.Set(TraceparentHeader, formatTraceparent(sc))
+	if sc.TraceState != "" {
+		carrier.Set(TracestateHeader, sc.TraceState)
+	}
+}
+
+// RemoteSpanContextFromContext returns the span context extracted by Extract, invalid if none
+func RemoteSpanContextFromContext(ctx context.Context) SpanContext {
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+const sampledFlag = 0x01
+
+// formatTraceparent formats the span context as version 00, e.g. "00-<trace ID>-<span ID>-01"
+func formatTraceparent(sc SpanContext) string {
+	flags := 0
+	if sc.Sampled {
+		flags = sampledFlag
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, flags)
+}
+
+// parseTraceparent parses the traceparent header. The fields of the future versions
+// following the four of version 00 are ignored as the specification says.
+func parseTraceparent(s string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 {
+		return sc, fmt.Errorf("tracing: invalid traceparent %q", s)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return sc, fmt.Errorf("tracing: invalid traceparent version %q", s)
+	}
+	if len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 {
+		return sc, fmt.Errorf("tracing: invalid traceparent %q", s)
+	}
+	// Upper case isn't allowed
+	if strings.ToLower(s) != s {
+		return sc, fmt.Errorf("tracing: invalid traceparent %q", s)
+	}
+	var f [1]byte
+	if _, err := hex.Decode(sc.TraceID[:], []byte(traceID)); err != nil {
+		return sc, fmt.Errorf("tracing: invalid trace ID %q", traceID)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(spanID)); err != nil {
+		return sc, fmt.Errorf("tracing: invalid span ID %q", spanID)
+	}
+	if _, err := hex.Decode(f[:], []byte(flags)); err != nil {
+		return sc, fmt.Errorf("tracing: invalid trace flags %q", flags)
+	}
+	if !sc.IsValid() {
+		return sc, fmt.Errorf("tracing: all zero IDs in traceparent %q", s)
+	}
+	sc.Sampled = f[0]&sampledFlag != 0
+	return sc, nil
+}
@@ -0,0 +1,260 @@
+// Package tracing records the spans of the requests across HTTP, gRPC, the services and SQL,
+// and exports them to stdout or an OTLP collector in the manner of OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID identifies a trace across the services
+type TraceID [16]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID identifies a span in a trace
+type SpanID [8]byte
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext is the part of a span propagated to the other services
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	// TraceState is the tracestate header of the caller passed through as it is
+	TraceState string
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanKind is the role of the span in the trace
+type SpanKind int
+
+// The values are the ones of OTLP
+const (
+	Internal SpanKind = 1
+	Server   SpanKind = 2
+	Client   SpanKind = 3
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case Server:
+		return "server"
+	case Client:
+		return "client"
+	default:
+		return "internal"
+	}
+}
+
+// SpanData is the finished span passed to the exporter
+type SpanData struct {
+	Name         string
+	Kind         SpanKind
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+	// Error is the message of the error recorded, empty if none
+	Error string
+}
+
+// Span is an operation of a trace.
+// The methods of the nil Span, which is returned when tracing is disabled, do nothing.
+type Span struct {
+	tracer *Tracer
+	mu     sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+// SetAttribute sets a value of string, bool, int, int64 or float64 to the span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil || !s.data.SpanContext.Sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		// The attributes have been passed to the exporter
+		return
+	}
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	s.data.Attributes[key] = value
+}
+
+// RecordError marks the span failed by the error. The nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil || !s.data.SpanContext.Sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.data.Error = err.Error()
+}
+
+// SpanContext returns the span context, which is invalid for the nil Span
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+// End finishes the span and exports it if it's sampled. The calls after the first one do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+	if data.SpanContext.Sampled {
+		s.tracer.exporter.ExportSpan(&data)
+	}
+}
+
+// Tracer starts the spans and passes the sampled ones to the exporter
+type Tracer struct {
+	exporter Exporter
+	// threshold is the sample ratio scaled to the upper 63 bits of the trace ID
+	threshold uint64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewTracer returns the tracer sampling the ratio (0 to 1) of the traces it starts.
+// The traces started by the callers follow the sampling decisions of the callers.
+func NewTracer(exporter Exporter, sampleRatio float64) *Tracer {
+	var seed int64
+	_ = binary.Read(crand.Reader, binary.LittleEndian, &seed)
+	if sampleRatio > 1 {
+		sampleRatio = 1
+	}
+	if sampleRatio < 0 {
+		sampleRatio = 0
+	}
+	return &Tracer{
+		exporter:  exporter,
+		threshold: uint64(sampleRatio * (1 << 63)),
+		rnd:       rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Shutdown exports the spans the exporter holds. The nil tracer does nothing.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+// start starts the span of the kind as the child of the span in the context, or of the remote span
+// extracted into the context, or as the root of a new trace.
+func (t *Tracer) start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanFromContext(ctx).SpanContext()
+	if !parent.IsValid() {
+		parent = RemoteSpanContextFromContext(ctx)
+	}
+
+	sc := SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled, TraceState: parent.TraceState}
+	t.mu.Lock()
+	if !parent.IsValid() {
+		t.rnd.Read(sc.TraceID[:])
+	}
+	t.rnd.Read(sc.SpanID[:])
+	t.mu.Unlock()
+	if !parent.IsValid() {
+		// Deterministic by the trace ID as OpenTelemetry TraceIDRatioBased does
+		sc.Sampled = binary.BigEndian.Uint64(sc.TraceID[8:])>>1 < t.threshold
+	}
+
+	span := &Span{
+		tracer: t,
+		data: SpanData{
+			Name:         name,
+			Kind:         kind,
+			SpanContext:  sc,
+			ParentSpanID: parent.SpanID,
+			StartTime:    time.Now(),
+		},
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// global holds the *Tracer used by Start, nil until SetTracer is called
+var global atomic.Value
+
+// SetTracer sets the tracer used by Start. The nil tracer disables tracing.
+func SetTracer(t *Tracer) {
+	global.Store(tracerHolder{t})
+}
+
+// tracerHolder lets atomic.Value store the nil tracer
+type tracerHolder struct {
+	tracer *Tracer
+}
+
+func globalTracer() *Tracer {
+	h, _ := global.Load().(tracerHolder)
+	return h.tracer
+}
+
+// Start starts the span of an operation in the service, e.g. a service method.
+// It returns the context as it is and the nil Span when tracing is disabled.
+// The span must be ended by End.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return StartKind(ctx, name, Internal)
+}
+
+// StartKind starts the span of the kind, which is Server for the requests and Client for the calls to others
+func StartKind(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	t := globalTracer()
+	if t == nil {
+		return ctx, nil
+	}
+	return t.start(ctx, name, kind)
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the span in the context or nil if none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
@@ -0,0 +1,190 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/momotaro98/mixlunch-service-api/tracing"
+)
+
+// setup makes the tracer exporting to memory the global one, which the test resets by tracing.SetTracer(nil)
+func setup(sampleRatio float64) *tracing.InMemoryExporter {
+	exporter := tracing.NewInMemoryExporter()
+	tracing.SetTracer(tracing.NewTracer(exporter, sampleRatio))
+	return exporter
+}
+
+func TestStart_ChildSpans(t *testing.T) {
+	exporter := setup(1)
+	defer tracing.SetTracer(nil)
+
+	ctx, root := tracing.StartKind(context.Background(), "GET /api/v1/user/{uid}", tracing.Server)
+	childCtx, child := tracing.Start(ctx, "userservice.GetUserByUserId")
+	_, sql := tracing.StartKind(childCtx, "SQL SELECT", tracing.Client)
+	sql.SetAttribute("db.statement", "SELECT 1")
+	sql.RecordError(errors.New("bad connection"))
+	sql.End()
+	child.End()
+	child.End()
+	root.End()
+
+	spans := exporter.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans ended once, actual %d", len(spans))
+	}
+	r, c, s := exporter.Span("GET /api/v1/user/{uid}"), exporter.Span("userservice.GetUserByUserId"), exporter.Span("SQL SELECT")
+	if r.ParentSpanID.IsValid() {
+		t.Errorf("expected the root without parent, actual %s", r.ParentSpanID)
+	}
+	if c.ParentSpanID != r.SpanContext.SpanID || s.ParentSpanID != c.SpanContext.SpanID {
+		t.Error("expected the spans nested in order")
+	}
+	if c.SpanContext.TraceID != r.SpanContext.TraceID || s.SpanContext.TraceID != r.SpanContext.TraceID {
+		t.Error("expected the spans in a trace")
+	}
+	if s.Attributes["db.statement"] != "SELECT 1" || s.Error != "bad connection" || s.Kind != tracing.Client {
+		t.Errorf("unexpected SQL span %+v", s)
+	}
+}
+
+func TestStart_Disabled(t *testing.T) {
+	tracing.SetTracer(nil)
+	ctx := context.Background()
+	actualCtx, span := tracing.Start(ctx, "userservice.GetUserByUserId")
+	if actualCtx != ctx || span != nil {
+		t.Error("expected the context as it is and the nil span")
+	}
+	// Nothing happens with the nil span
+	span.SetAttribute("k", "v")
+	span.RecordError(errors.New("e"))
+	span.End()
+}
+
+func TestStart_Sampling(t *testing.T) {
+	exporter := setup(0)
+	defer tracing.SetTracer(nil)
+
+	_, span := tracing.Start(context.Background(), "not sampled")
+	span.End()
+	if len(exporter.Spans()) != 0 {
+		t.Error("expected the new trace not sampled by ratio 0")
+	}
+
+	// The sampling decision of the caller wins
+	header := http.Header{}
+	header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := tracing.Extract(context.Background(), tracing.HeaderCarrier(header))
+	_, span = tracing.StartKind(ctx, "sampled by the caller", tracing.Server)
+	span.End()
+	actual := exporter.Span("sampled by the caller")
+	if actual == nil {
+		t.Fatal("expected the span sampled by the caller")
+	}
+	if actual.SpanContext.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || actual.ParentSpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("expected the child of the caller, actual %+v", actual)
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	setup(1)
+	defer tracing.SetTracer(nil)
+	ctx, span := tracing.Start(context.Background(), "caller")
+	defer span.End()
+	sc := span.SpanContext()
+
+	md := metadata.MD{}
+	tracing.Inject(ctx, tracing.MetadataCarrier(md))
+	expected := "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-01"
+	if actual := md.Get("traceparent"); len(actual) != 1 || actual[0] != expected {
+		t.Fatalf("expected %s, actual %v", expected, actual)
+	}
+	remote := tracing.RemoteSpanContextFromContext(tracing.Extract(context.Background(), tracing.MetadataCarrier(md)))
+	if remote.TraceID != sc.TraceID || remote.SpanID != sc.SpanID || !remote.Sampled {
+		t.Errorf("expected %+v, actual %+v", sc, remote)
+	}
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+	}
+	for _, tp := range invalid {
+		header := http.Header{}
+		header.Set(tracing.TraceparentHeader, tp)
+		if remote := tracing.RemoteSpanContextFromContext(tracing.Extract(context.Background(), tracing.HeaderCarrier(header))); remote.IsValid() {
+			t.Errorf("expected %q ignored, actual %+v", tp, remote)
+		}
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var body struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Kind         int    `json:"kind"`
+					Attributes   []struct {
+						Key   string                 `json:"key"`
+						Value map[string]interface{} `json:"value"`
+					} `json:"attributes"`
+					Status struct {
+						Code int `json:"code"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	var path string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer collector.Close()
+
+	exporter := tracing.NewOTLPExporter(collector.URL, "mixlunch-test", collector.Client())
+	tracing.SetTracer(tracing.NewTracer(exporter, 1))
+	defer tracing.SetTracer(nil)
+
+	ctx, root := tracing.StartKind(context.Background(), "/pb.MixLunch/GetUsersForMatching", tracing.Server)
+	_, child := tracing.Start(ctx, "partyservice.GetLastNPartiesOfAUser")
+	child.SetAttribute("n", 3)
+	child.RecordError(errors.New("failed"))
+	child.End()
+	root.End()
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if path != "/v1/traces" {
+		t.Errorf("expected /v1/traces, actual %s", path)
+	}
+	spans := body.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, actual %d", len(spans))
+	}
+	c, r := spans[0], spans[1]
+	if c.Name != "partyservice.GetLastNPartiesOfAUser" || c.Kind != 1 || c.Status.Code != 2 || c.TraceID != r.TraceID {
+		t.Errorf("unexpected child %+v", c)
+	}
+	if len(c.Attributes) != 1 || c.Attributes[0].Value["intValue"] != "3" {
+		t.Errorf("unexpected attributes %+v", c.Attributes)
+	}
+	if r.Kind != 2 || r.ParentSpanID != "" {
+		t.Errorf("unexpected root %+v", r)
+	}
+}
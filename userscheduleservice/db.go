@@ -1,23 +1,16 @@
 package userscheduleservice
 
 import (
+	"database/sql"
+
 	"github.com/jmoiron/sqlx"
 )
 
-type Config struct {
-	DSN string
-}
-
 type SqlDb struct {
 	*sqlx.DB
 }
 
-func ProvideDB(cfg *Config) SqlDb {
-	dsn := cfg.DSN
-	var err error
-	db, err := sqlx.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
-	}
-	return SqlDb{db}
+// ProvideDB provides the DB pool shared by the services
+func ProvideDB(db *sql.DB) SqlDb {
+	return SqlDb{sqlx.NewDb(db, "mysql")}
 }
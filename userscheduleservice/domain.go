@@ -1,6 +1,7 @@
 package userscheduleservice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/momotaro98/mixlunch-service-api/conventions"
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
 	"github.com/momotaro98/mixlunch-service-api/tagservice"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
 )
 
 type UserSchedules struct {
@@ -69,11 +71,11 @@ func validateFromDateTimeAndToDateTime(fromDateTime, toDateTime time.Time) error
 }
 
 type UserScheduleServer interface {
-	GetUserSchedulesByTimeRange(userId, beginDateTimeStr, endDateTimeStr string) (*UserSchedules, error)
-	GetEachUserSchedules(beginDateTimeStr, endDateTimeStr string) ([]*UserSchedules, error)
-	AddUserSchedule(userId string, usComm *UserScheduleForCommand) (*UserSchedules, error)
-	UpdateUserSchedule(userId string, usComm *UserScheduleForCommand) (*UserSchedules, error)
-	DeleteUserSchedule(userId string, targetDate time.Time) (*UserSchedules, error)
+	GetUserSchedulesByTimeRange(ctx context.Context, userId, beginDateTimeStr, endDateTimeStr string) (*UserSchedules, error)
+	GetEachUserSchedules(ctx context.Context, beginDateTimeStr, endDateTimeStr string) ([]*UserSchedules, error)
+	AddUserSchedule(ctx context.Context, userId string, usComm *UserScheduleForCommand) (*UserSchedules, error)
+	UpdateUserSchedule(ctx context.Context, userId string, usComm *UserScheduleForCommand) (*UserSchedules, error)
+	DeleteUserSchedule(ctx context.Context, userId string, targetDate time.Time) (*UserSchedules, error)
 }
 
 type realUserScheduleServer struct {
@@ -92,13 +94,13 @@ func ProvideUserScheduleServer(queryRepository IUserScheduleQueryRepository, upd
 
 // extractAScheduleDtoWithValidation returns DTO of one user schedule
 // with validation to check if there is only one user schedule in the specified day.
-func (s *realUserScheduleServer) extractAScheduleDtoWithValidation(userId string, dateTimeOfTheTargetDate time.Time) (*UserScheduleDto, error) {
+func (s *realUserScheduleServer) extractAScheduleDtoWithValidation(ctx context.Context, userId string, dateTimeOfTheTargetDate time.Time) (*UserScheduleDto, error) {
 	begin := time.Date(dateTimeOfTheTargetDate.Year(), dateTimeOfTheTargetDate.Month(), dateTimeOfTheTargetDate.Day(),
 		0, 0, 0, 0, time.Local)
 	end := time.Date(dateTimeOfTheTargetDate.Year(), dateTimeOfTheTargetDate.Month(), dateTimeOfTheTargetDate.Day(),
 		23, 59, 59, 999, time.Local)
 	// Try to retrieve the target user schedule and Validate
-	queriedDtosToValidateAndSpecify, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(begin, end, userId)
+	queriedDtosToValidateAndSpecify, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(ctx, begin, end, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -112,7 +114,9 @@ func (s *realUserScheduleServer) extractAScheduleDtoWithValidation(userId string
 	return queriedDtosToValidateAndSpecify[0], nil
 }
 
-func (s *realUserScheduleServer) GetUserSchedulesByTimeRange(userId, beginDateTimeStr, endDateTimeStr string) (*UserSchedules, error) {
+func (s *realUserScheduleServer) GetUserSchedulesByTimeRange(ctx context.Context, userId, beginDateTimeStr, endDateTimeStr string) (*UserSchedules, error) {
+	ctx, span := tracing.Start(ctx, "userscheduleservice.GetUserSchedulesByTimeRange")
+	defer span.End()
 	// Parse DateTime string to RFC3339 spec
 	beginDateTime, err := time.Parse(time.RFC3339, beginDateTimeStr)
 	if err != nil {
@@ -124,7 +128,7 @@ func (s *realUserScheduleServer) GetUserSchedulesByTimeRange(userId, beginDateTi
 	}
 
 	// Query Dto from DB through repository layer
-	dtos, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(beginDateTime, endDateTime, userId)
+	dtos, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(ctx, beginDateTime, endDateTime, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -140,7 +144,7 @@ func (s *realUserScheduleServer) GetUserSchedulesByTimeRange(userId, beginDateTi
 	// Assign to domain
 	for _, dto := range dtos {
 		// Query tags from tagservice
-		tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.All, dto.tagIds)
+		tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.All, dto.tagIds)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -155,7 +159,9 @@ func (s *realUserScheduleServer) GetUserSchedulesByTimeRange(userId, beginDateTi
 	return &uSchedules, nil
 }
 
-func (s *realUserScheduleServer) GetEachUserSchedules(beginDateTimeStr, endDateTimeStr string) ([]*UserSchedules, error) {
+func (s *realUserScheduleServer) GetEachUserSchedules(ctx context.Context, beginDateTimeStr, endDateTimeStr string) ([]*UserSchedules, error) {
+	ctx, span := tracing.Start(ctx, "userscheduleservice.GetEachUserSchedules")
+	defer span.End()
 	// Parse DateTime string to RFC3339 spec
 	beginDateTime, err := time.Parse(time.RFC3339, beginDateTimeStr)
 	if err != nil {
@@ -166,7 +172,7 @@ func (s *realUserScheduleServer) GetEachUserSchedules(beginDateTimeStr, endDateT
 		return nil, NewInvalidDateTimeFormatError(endDateTimeStr)
 	}
 	// Query Dto from DB through repository layer
-	dtos, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(beginDateTime, endDateTime, "")
+	dtos, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(ctx, beginDateTime, endDateTime, "")
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -194,7 +200,7 @@ func (s *realUserScheduleServer) GetEachUserSchedules(beginDateTimeStr, endDateT
 	var currentUserSchedule UserSchedules
 	for _, dto := range dtos {
 		// Query tags from tagservice
-		tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.All, dto.tagIds)
+		tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.All, dto.tagIds)
 		if err != nil {
 			return nil, stew.Wrap(err)
 		}
@@ -232,7 +238,9 @@ func (s *realUserScheduleServer) GetEachUserSchedules(beginDateTimeStr, endDateT
 	return ret, nil
 }
 
-func (s *realUserScheduleServer) AddUserSchedule(userId string, usComm *UserScheduleForCommand) (*UserSchedules, error) {
+func (s *realUserScheduleServer) AddUserSchedule(ctx context.Context, userId string, usComm *UserScheduleForCommand) (*UserSchedules, error) {
+	ctx, span := tracing.Start(ctx, "userscheduleservice.AddUserSchedule")
+	defer span.End()
 	// Validation
 	if err := ValidateUserSchedule(usComm); err != nil {
 		return nil, domainerror.NewValidationError(err)
@@ -250,7 +258,7 @@ func (s *realUserScheduleServer) AddUserSchedule(userId string, usComm *UserSche
 	endDateTime := time.Date(
 		usComm.FromDateTime.Year(), usComm.FromDateTime.Month(), usComm.FromDateTime.Day(),
 		23, 59, 59, 999, time.Local)
-	queriedDtosToValidate, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(beginDateTime, endDateTime, userId)
+	queriedDtosToValidate, err := s.userScheduleQueryRepository.QueryUserSchedulesWhereTimeRange(ctx, beginDateTime, endDateTime, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -259,7 +267,7 @@ func (s *realUserScheduleServer) AddUserSchedule(userId string, usComm *UserSche
 	}
 
 	// Add a user schedule
-	lastInsertedId, err := s.userScheduleCommandRepository.InsertUserSchedule(
+	lastInsertedId, err := s.userScheduleCommandRepository.InsertUserSchedule(ctx,
 		NewUserScheduleDtoForCommand(userId,
 			usComm.FromDateTime, usComm.ToDateTime,
 			usComm.TagIds,
@@ -270,7 +278,7 @@ func (s *realUserScheduleServer) AddUserSchedule(userId string, usComm *UserSche
 		return nil, stew.Wrap(err)
 	}
 	// Get the newly added user schedule to return
-	lastInsertedDto, err := s.userScheduleQueryRepository.QueryUserScheduleWhereId(lastInsertedId)
+	lastInsertedDto, err := s.userScheduleQueryRepository.QueryUserScheduleWhereId(ctx, lastInsertedId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -282,7 +290,7 @@ func (s *realUserScheduleServer) AddUserSchedule(userId string, usComm *UserSche
 	// UserId
 	uSchedules.UserId = lastInsertedDto.userId
 	// Tags of the schedule
-	tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.All, lastInsertedDto.tagIds)
+	tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.All, lastInsertedDto.tagIds)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -296,7 +304,9 @@ func (s *realUserScheduleServer) AddUserSchedule(userId string, usComm *UserSche
 	return &uSchedules, nil
 }
 
-func (s *realUserScheduleServer) UpdateUserSchedule(userId string, usComm *UserScheduleForCommand) (*UserSchedules, error) {
+func (s *realUserScheduleServer) UpdateUserSchedule(ctx context.Context, userId string, usComm *UserScheduleForCommand) (*UserSchedules, error) {
+	ctx, span := tracing.Start(ctx, "userscheduleservice.UpdateUserSchedule")
+	defer span.End()
 	// Validation
 	if err := ValidateUserSchedule(usComm); err != nil {
 		return nil, domainerror.NewValidationError(err)
@@ -308,13 +318,13 @@ func (s *realUserScheduleServer) UpdateUserSchedule(userId string, usComm *UserS
 	}
 
 	// Check if there is a user schedule in the day
-	targetDtoToUpdate, err := s.extractAScheduleDtoWithValidation(userId, usComm.FromDateTime)
+	targetDtoToUpdate, err := s.extractAScheduleDtoWithValidation(ctx, userId, usComm.FromDateTime)
 	if err != nil {
 		return nil, err
 	}
 
 	// Update the target user schedule
-	lastUpdatedId, err := s.userScheduleCommandRepository.UpdateUserSchedule(
+	lastUpdatedId, err := s.userScheduleCommandRepository.UpdateUserSchedule(ctx,
 		targetDtoToUpdate.userScheduleId,
 		NewUserScheduleDtoForCommand(userId,
 			usComm.FromDateTime, usComm.ToDateTime,
@@ -326,7 +336,7 @@ func (s *realUserScheduleServer) UpdateUserSchedule(userId string, usComm *UserS
 		return nil, stew.Wrap(err)
 	}
 	// Get the updated user schedule to return
-	lastUpdatedDto, err := s.userScheduleQueryRepository.QueryUserScheduleWhereId(lastUpdatedId)
+	lastUpdatedDto, err := s.userScheduleQueryRepository.QueryUserScheduleWhereId(ctx, lastUpdatedId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -337,7 +347,7 @@ func (s *realUserScheduleServer) UpdateUserSchedule(userId string, usComm *UserS
 	var uSchedules UserSchedules // variable to return
 	uSchedules.UserId = lastUpdatedDto.userId
 	// Tags of the schedule
-	tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.All, lastUpdatedDto.tagIds)
+	tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.All, lastUpdatedDto.tagIds)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -351,21 +361,23 @@ func (s *realUserScheduleServer) UpdateUserSchedule(userId string, usComm *UserS
 	return &uSchedules, nil
 }
 
-func (s *realUserScheduleServer) DeleteUserSchedule(userId string, targetDate time.Time) (*UserSchedules, error) {
+func (s *realUserScheduleServer) DeleteUserSchedule(ctx context.Context, userId string, targetDate time.Time) (*UserSchedules, error) {
+	ctx, span := tracing.Start(ctx, "userscheduleservice.DeleteUserSchedule")
+	defer span.End()
 	// Check if there is a user schedule in the day
-	targetDtoToDelete, err := s.extractAScheduleDtoWithValidation(userId, targetDate)
+	targetDtoToDelete, err := s.extractAScheduleDtoWithValidation(ctx, userId, targetDate)
 	if err != nil {
 		return nil, err
 	}
 
 	// Query Tags information before deleting
-	tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.All, targetDtoToDelete.tagIds)
+	tags, err := s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.All, targetDtoToDelete.tagIds)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 
 	// Delete the user schedule
-	err = s.userScheduleCommandRepository.DeleteUserSchedule(targetDtoToDelete.userScheduleId)
+	err = s.userScheduleCommandRepository.DeleteUserSchedule(ctx, targetDtoToDelete.userScheduleId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
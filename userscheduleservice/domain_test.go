@@ -1,6 +1,7 @@
 package userscheduleservice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -104,12 +105,12 @@ func TestGetUserSchedulesByTimeRange_SomeDTOs_ReturnModels(t *testing.T) {
 	// query mock
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return(makeSomeUserScheduleDtos(uid), nil)
 	// tag service mock
 	tagServiceMock := testmock.NewMockTagServer(mockCtrl)
 	tagServiceMock.EXPECT().
-		GetTagsByTagTypeAndTagIds(tagservice.All, gomock.Any()).
+		GetTagsByTagTypeAndTagIds(gomock.Any(), tagservice.All, gomock.Any()).
 		Return(makeRegularTags(), nil).
 		AnyTimes()
 	// Initialize mock
@@ -120,7 +121,7 @@ func TestGetUserSchedulesByTimeRange_SomeDTOs_ReturnModels(t *testing.T) {
 	)
 
 	// Act
-	uSchedules, _ := userScheduleServer.GetUserSchedulesByTimeRange(uid, beginDateTime, endDateTime)
+	uSchedules, _ := userScheduleServer.GetUserSchedulesByTimeRange(context.Background(), uid, beginDateTime, endDateTime)
 	// Assert
 	if uSchedules.UserId != uid {
 		t.Errorf("Test failed. Expected: %s', Actual: %s", uid, uSchedules.UserId)
@@ -144,7 +145,7 @@ func TestGetUserSchedulesByTimeRange_DateTimeFormatIsIncorrect_ReturnNil(t *test
 		testmock.NewMockTagServer(mockCtrl),
 	)
 	// Act
-	uSchedules, err := userScheduleServer.GetUserSchedulesByTimeRange(uid, beginDateTime, endDateTime)
+	uSchedules, err := userScheduleServer.GetUserSchedulesByTimeRange(context.Background(), uid, beginDateTime, endDateTime)
 	// Assert
 	if uSchedules != nil {
 		t.Errorf("Test failed. Expected: nil', Actual: %v", uSchedules)
@@ -164,7 +165,7 @@ func TestGetUserSchedulesByTimeRange_EmptyDots_ZeroValueUserSchedules(t *testing
 	defer mockCtrl.Finish()
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return([]*UserScheduleDto{}, nil) // Empty user is expected
 	userScheduleServer := ProvideUserScheduleServer(
 		userScheduleQueryRepositoryMock,
@@ -172,7 +173,7 @@ func TestGetUserSchedulesByTimeRange_EmptyDots_ZeroValueUserSchedules(t *testing
 		testmock.NewMockTagServer(mockCtrl),
 	)
 	// Act
-	uSchedules, _ := userScheduleServer.GetUserSchedulesByTimeRange(uid, beginDateTime, endDateTime)
+	uSchedules, _ := userScheduleServer.GetUserSchedulesByTimeRange(context.Background(), uid, beginDateTime, endDateTime)
 	// Assert
 	if uSchedules.UserId != uid {
 		t.Errorf("Test failed. Expected: %s', Actual: %s", uid, uSchedules.UserId)
@@ -192,12 +193,12 @@ func TestGetEachUserSchedules_RegularMultiUsersDtos_Expected(t *testing.T) {
 	// user-schedule service mock
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), ""). // Empty userId is expected
-		Return(makeMultipleUsersSchedulesDtos(), nil)                     // some users DTO
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), ""). // Empty userId is expected
+		Return(makeMultipleUsersSchedulesDtos(), nil)                                   // some users DTO
 	// tag service mock
 	tagServiceMock := testmock.NewMockTagServer(mockCtrl)
 	tagServiceMock.EXPECT().
-		GetTagsByTagTypeAndTagIds(tagservice.All, gomock.Any()).
+		GetTagsByTagTypeAndTagIds(gomock.Any(), tagservice.All, gomock.Any()).
 		Return(makeRegularTags(), nil).AnyTimes()
 	userScheduleServer := ProvideUserScheduleServer(
 		userScheduleQueryRepositoryMock,
@@ -205,7 +206,7 @@ func TestGetEachUserSchedules_RegularMultiUsersDtos_Expected(t *testing.T) {
 		tagServiceMock,
 	)
 	// Act
-	eachUserSchedules, _ := userScheduleServer.GetEachUserSchedules(beginDateTime, endDateTime)
+	eachUserSchedules, _ := userScheduleServer.GetEachUserSchedules(context.Background(), beginDateTime, endDateTime)
 	// Assert
 	if len(eachUserSchedules) != 3 { // since makeMultipleUsersSchedulesDtos makes 3 users schedules
 		t.Errorf("Test failed. Expected: %d', Actual: %d", 3, len(eachUserSchedules))
@@ -242,20 +243,20 @@ func TestAddUserSchedule_EverythingIsOk_NoError(t *testing.T) {
 	lastInsertedIdOfUserSchedule := anyInt64
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return([]*UserScheduleDto{}, nil) // Empty user is expected to avoid duplicate registering error
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserScheduleWhereId(lastInsertedIdOfUserSchedule).
+		QueryUserScheduleWhereId(gomock.Any(), lastInsertedIdOfUserSchedule).
 		Return(makeOneUserScheduleDto(), nil) // Registered schedule
 	//// Mock of Command repository
 	userScheduleCommandRepositoryMock := NewMockIUserScheduleCommandRepository(mockCtrl)
 	userScheduleCommandRepositoryMock.EXPECT().
-		InsertUserSchedule(gomock.Any()).
+		InsertUserSchedule(gomock.Any(), gomock.Any()).
 		Return(lastInsertedIdOfUserSchedule, nil)
 	// tag service mock
 	tagServiceMock := testmock.NewMockTagServer(mockCtrl)
 	tagServiceMock.EXPECT().
-		GetTagsByTagTypeAndTagIds(tagservice.All, gomock.Any()).
+		GetTagsByTagTypeAndTagIds(gomock.Any(), tagservice.All, gomock.Any()).
 		Return(makeRegularTags(), nil)
 	//// Initialize server with mocks
 	userScheduleServer := ProvideUserScheduleServer(
@@ -264,7 +265,7 @@ func TestAddUserSchedule_EverythingIsOk_NoError(t *testing.T) {
 		tagServiceMock,
 	)
 	// Act
-	uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+	uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 		&UserScheduleForCommand{
 			FromDateTime: fromDateTime,
 			ToDateTime:   toDateTime,
@@ -299,7 +300,7 @@ func TestAddUserSchedule_TimeRangeInvalidCases_ReturnError(t *testing.T) {
 		fromDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour+1, 30, 0, 0, time.Local) // After than toDateTime
 		toDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 0, 0, 0, time.Local)
 		// Act
-		uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+		uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 			&UserScheduleForCommand{
 				FromDateTime: fromDateTime,
 				ToDateTime:   toDateTime,
@@ -320,7 +321,7 @@ func TestAddUserSchedule_TimeRangeInvalidCases_ReturnError(t *testing.T) {
 		fromDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 30, 0, 0, time.Local)
 		toDateTime := time.Date(baseYear, baseMonth, baseDay+1, baseHour+1, 30, 0, 0, time.Local) // Different date from fromDateTime
 		// Act
-		uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+		uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 			&UserScheduleForCommand{
 				FromDateTime: fromDateTime,
 				ToDateTime:   toDateTime,
@@ -341,7 +342,7 @@ func TestAddUserSchedule_TimeRangeInvalidCases_ReturnError(t *testing.T) {
 		fromDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 0, 0, 0, time.Local)
 		toDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 59, 0, 0, time.Local) // Within 60 minutes
 		// Act
-		uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+		uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 			&UserScheduleForCommand{
 				FromDateTime: fromDateTime,
 				ToDateTime:   toDateTime,
@@ -369,7 +370,7 @@ func TestAddUserSchedule_DuplicateDayInASameDay_DuplicateInOneDayError(t *testin
 	defer mockCtrl.Finish()
 	userScheduleQueryRepository := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepository.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return(makeOnlyOneUserScheduleDtos(uid, anyInt64), nil) // Duplicate in a day
 	userScheduleServer := ProvideUserScheduleServer(
 		userScheduleQueryRepository,
@@ -377,7 +378,7 @@ func TestAddUserSchedule_DuplicateDayInASameDay_DuplicateInOneDayError(t *testin
 		testmock.NewMockTagServer(mockCtrl),
 	)
 	// Act
-	uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+	uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 		&UserScheduleForCommand{
 			FromDateTime: fromDateTime,
 			ToDateTime:   toDateTime,
@@ -408,25 +409,26 @@ func TestUpdateUserSchedule_EverythingIsOk_NoError(t *testing.T) {
 
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return(makeOnlyOneUserScheduleDtos(uid, queriedIdOfUserSchedule), nil) // Only one user is expected before update
 
 	userScheduleCommandRepositoryMock := NewMockIUserScheduleCommandRepository(mockCtrl)
 	userScheduleCommandRepositoryMock.EXPECT().
 		UpdateUserSchedule(
+			gomock.Any(),
 			queriedIdOfUserSchedule,
 			gomock.Any(),
 		).
 		Return(lastUpdatedIdOfUserSchedule, nil)
 
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserScheduleWhereId(lastUpdatedIdOfUserSchedule).
+		QueryUserScheduleWhereId(gomock.Any(), lastUpdatedIdOfUserSchedule).
 		Return(makeOneUserScheduleDto(), nil) // Get the user after update
 
 	// tag service mock
 	tagServiceMock := testmock.NewMockTagServer(mockCtrl)
 	tagServiceMock.EXPECT().
-		GetTagsByTagTypeAndTagIds(tagservice.All, gomock.Any()).
+		GetTagsByTagTypeAndTagIds(gomock.Any(), tagservice.All, gomock.Any()).
 		Return(makeRegularTags(), nil)
 
 	//// Initialize server with mocks
@@ -436,7 +438,7 @@ func TestUpdateUserSchedule_EverythingIsOk_NoError(t *testing.T) {
 		tagServiceMock,
 	)
 	// Act
-	uSchedules, err := userScheduleServer.UpdateUserSchedule(uid,
+	uSchedules, err := userScheduleServer.UpdateUserSchedule(context.Background(), uid,
 		&UserScheduleForCommand{
 			FromDateTime: fromDateTime,
 			ToDateTime:   toDateTime,
@@ -471,7 +473,7 @@ func TestUpdateUserSchedule_FromDateTimeIsAfterThanToDateTime_FromIsAfterToError
 		fromDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour+1, 30, 0, 0, time.Local) // After than toDateTime
 		toDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 0, 0, 0, time.Local)
 		// Act
-		uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+		uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 			&UserScheduleForCommand{
 				FromDateTime: fromDateTime,
 				ToDateTime:   toDateTime,
@@ -492,7 +494,7 @@ func TestUpdateUserSchedule_FromDateTimeIsAfterThanToDateTime_FromIsAfterToError
 		fromDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 30, 0, 0, time.Local)
 		toDateTime := time.Date(baseYear, baseMonth, baseDay+1, baseHour+1, 30, 0, 0, time.Local) // Different date from fromDateTime
 		// Act
-		uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+		uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 			&UserScheduleForCommand{
 				FromDateTime: fromDateTime,
 				ToDateTime:   toDateTime,
@@ -513,7 +515,7 @@ func TestUpdateUserSchedule_FromDateTimeIsAfterThanToDateTime_FromIsAfterToError
 		fromDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 0, 0, 0, time.Local)
 		toDateTime := time.Date(baseYear, baseMonth, baseDay, baseHour, 59, 0, 0, time.Local) // Within 60 minutes
 		// Act
-		uSchedules, err := userScheduleServer.AddUserSchedule(uid,
+		uSchedules, err := userScheduleServer.AddUserSchedule(context.Background(), uid,
 			&UserScheduleForCommand{
 				FromDateTime: fromDateTime,
 				ToDateTime:   toDateTime,
@@ -540,7 +542,7 @@ func TestUpdateUserSchedule_ThereAreMoreThanOneSchedulesInTheDay_Error(t *testin
 	defer mockCtrl.Finish()
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return(makeSomeUserScheduleDtos(uid), nil) // [Error] There are more than one user schedules before updating
 	//// Initialize server with mocks
 	userScheduleServer := ProvideUserScheduleServer(
@@ -549,7 +551,7 @@ func TestUpdateUserSchedule_ThereAreMoreThanOneSchedulesInTheDay_Error(t *testin
 		testmock.NewMockTagServer(mockCtrl),
 	)
 	// Act
-	uSchedules, err := userScheduleServer.UpdateUserSchedule(uid,
+	uSchedules, err := userScheduleServer.UpdateUserSchedule(context.Background(), uid,
 		&UserScheduleForCommand{
 			FromDateTime: fromDateTime,
 			ToDateTime:   toDateTime,
@@ -575,7 +577,7 @@ func TestUpdateUserSchedule_NoScheduleInTheDay_TheScheduleNotFoundError(t *testi
 	defer mockCtrl.Finish()
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return([]*UserScheduleDto{}, nil) // [Error] There's no user schedule before updating
 	//// Initialize server with mocks
 	userScheduleServer := ProvideUserScheduleServer(
@@ -584,7 +586,7 @@ func TestUpdateUserSchedule_NoScheduleInTheDay_TheScheduleNotFoundError(t *testi
 		testmock.NewMockTagServer(mockCtrl),
 	)
 	// Act
-	uSchedules, err := userScheduleServer.UpdateUserSchedule(uid,
+	uSchedules, err := userScheduleServer.UpdateUserSchedule(context.Background(), uid,
 		&UserScheduleForCommand{
 			FromDateTime: fromDateTime,
 			ToDateTime:   toDateTime,
@@ -612,18 +614,18 @@ func TestDeleteUserSchedule_EverythingIsOk_NoError(t *testing.T) {
 
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return(makeOnlyOneUserScheduleDtos(uid, queriedIdOfUserSchedule), nil) // Only one user is expected before update
 
 	userScheduleCommandRepositoryMock := NewMockIUserScheduleCommandRepository(mockCtrl)
 	userScheduleCommandRepositoryMock.EXPECT().
-		DeleteUserSchedule(queriedIdOfUserSchedule).
+		DeleteUserSchedule(gomock.Any(), queriedIdOfUserSchedule).
 		Return(nil)
 
 	// tag service mock
 	tagServiceMock := testmock.NewMockTagServer(mockCtrl)
 	tagServiceMock.EXPECT().
-		GetTagsByTagTypeAndTagIds(tagservice.All, gomock.Any()).
+		GetTagsByTagTypeAndTagIds(gomock.Any(), tagservice.All, gomock.Any()).
 		Return(makeRegularTags(), nil)
 
 	//// Initialize server with mocks
@@ -633,7 +635,7 @@ func TestDeleteUserSchedule_EverythingIsOk_NoError(t *testing.T) {
 		tagServiceMock,
 	)
 	// Act
-	uSchedules, err := userScheduleServer.DeleteUserSchedule(uid, targetDateTime)
+	uSchedules, err := userScheduleServer.DeleteUserSchedule(context.Background(), uid, targetDateTime)
 	// Assert
 	if err != nil {
 		t.Errorf("Test failed. Expected: no error', Actual: %s", err)
@@ -654,7 +656,7 @@ func TestDeleteUserSchedule_NoScheduleInTheDay_TheScheduleNotFoundError(t *testi
 	defer mockCtrl.Finish()
 	userScheduleQueryRepositoryMock := NewMockIUserScheduleQueryRepository(mockCtrl)
 	userScheduleQueryRepositoryMock.EXPECT().
-		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), uid).
+		QueryUserSchedulesWhereTimeRange(gomock.Any(), gomock.Any(), gomock.Any(), uid).
 		Return([]*UserScheduleDto{}, nil) // [Error] There's no user schedule before deleting
 	//// Initialize server with mocks
 	userScheduleServer := ProvideUserScheduleServer(
@@ -663,7 +665,7 @@ func TestDeleteUserSchedule_NoScheduleInTheDay_TheScheduleNotFoundError(t *testi
 		testmock.NewMockTagServer(mockCtrl),
 	)
 	// Act
-	uSchedules, err := userScheduleServer.DeleteUserSchedule(uid, targetDateTime)
+	uSchedules, err := userScheduleServer.DeleteUserSchedule(context.Background(), uid, targetDateTime)
 	// Assert
 	var e *TheScheduleNotFoundError
 	if !errors.As(err, &e) {
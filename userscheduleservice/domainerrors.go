@@ -1,7 +1,7 @@
 package userscheduleservice
 
 import (
-	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
@@ -16,6 +16,69 @@ const (
 	TimeRangeIsLessThanSpecifiedErrorCode
 )
 
+func init() {
+	domainerror.Register(domainerror.Definition{
+		Code:        InvalidDateTimeFormatCode,
+		Name:        "invalid_schedule_datetime_format",
+		Description: "The datetime of the user schedule is not in RFC3339.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "Specified datetime format is wrong. Use RFC3339 format. Your datetime: %s",
+			domainerror.Japanese: "日時の形式が正しくありません。RFC3339形式で指定してください。指定された日時: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        FromIsAfterToErrorCode,
+		Name:        "schedule_from_after_to",
+		Description: "The start of the user schedule is after its end.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "fromDateTime is after toDateTime of the user schedule. fromDateTime: %s, toDateTime: %s",
+			domainerror.Japanese: "予定の開始日時が終了日時より後になっています。fromDateTime: %s, toDateTime: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        DifferentDayFromAndToErrorCode,
+		Name:        "schedule_different_days",
+		Description: "The start and the end of the user schedule are on different days.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "Days fromDateTime and endDateTime of the user schedule are different. fromDateTime: %s, toDateTime: %s",
+			domainerror.Japanese: "予定の開始日時と終了日時が別の日になっています。fromDateTime: %s, toDateTime: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        DuplicateInOneDayErrorCode,
+		Name:        "duplicate_schedule_in_one_day",
+		Description: "The user already has a schedule on the day.",
+		HTTPStatus:  http.StatusConflict,
+		Messages: domainerror.Messages{
+			domainerror.English:  "There is already a user schedule in the day. The user schedule date: %s",
+			domainerror.Japanese: "その日には既に予定が登録されています。予定の日付: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        TheScheduleNotFoundErrorCode,
+		Name:        "schedule_not_found",
+		Description: "The user has no schedule on the day.",
+		HTTPStatus:  http.StatusNotFound,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The specified day doesn't have a user schedule. The specified date: %s",
+			domainerror.Japanese: "指定された日には予定がありません。指定された日付: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        TimeRangeIsLessThanSpecifiedErrorCode,
+		Name:        "schedule_time_range_too_short",
+		Description: "The user schedule is shorter than the minimum duration.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "Time range between fromDateTime and toDateTime must not be within %d minutes. fromDateTime: %s, toDateTime: %s",
+			domainerror.Japanese: "予定の開始日時と終了日時の間は%d分より長くしてください。fromDateTime: %s, toDateTime: %s",
+		},
+	})
+}
+
 // InvalidDateTimeFormat
 
 type InvalidDateTimeFormatError struct {
@@ -29,14 +92,21 @@ func NewInvalidDateTimeFormatError(specifiedDateTimeStr string) *InvalidDateTime
 }
 
 func (e *InvalidDateTimeFormatError) Error() string {
-	return fmt.Sprintf("Specified datetime format is wrong. Use RFC3339 format. Your datetime: %s",
-		e.SpecifiedDateTimeStr)
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *InvalidDateTimeFormatError) MessageArgs() []interface{} {
+	return []interface{}{e.SpecifiedDateTimeStr}
 }
 
 func (e *InvalidDateTimeFormatError) Code() domainerror.ErrorCode {
 	return InvalidDateTimeFormatCode
 }
 
+func (e *InvalidDateTimeFormatError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 // FromIsAfterToError
 
 type FromIsAfterToError struct {
@@ -52,15 +122,21 @@ func NewFromIsAfterToError(fromDate time.Time, toDate time.Time) *FromIsAfterToE
 }
 
 func (e *FromIsAfterToError) Error() string {
-	return fmt.Sprintf(
-		"fromDateTime is after toDateTime of the user schedule. fromDateTime: %s, toDateTime: %s",
-		e.FromDate.String(), e.ToDate.String())
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *FromIsAfterToError) MessageArgs() []interface{} {
+	return []interface{}{e.FromDate.String(), e.ToDate.String()}
 }
 
 func (e *FromIsAfterToError) Code() domainerror.ErrorCode {
 	return FromIsAfterToErrorCode
 }
 
+func (e *FromIsAfterToError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 // DifferentDayFromAndToError
 
 type DifferentDayFromAndToError struct {
@@ -76,15 +152,21 @@ func NewDifferentDayFromAndToError(fromDate time.Time, toDate time.Time) *Differ
 }
 
 func (e *DifferentDayFromAndToError) Error() string {
-	return fmt.Sprintf(
-		"Days fromDateTime and endDateTime of the user schedule are different. fromDateTime: %s, toDateTime: %s",
-		e.FromDate.String(), e.ToDate.String())
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *DifferentDayFromAndToError) MessageArgs() []interface{} {
+	return []interface{}{e.FromDate.String(), e.ToDate.String()}
 }
 
 func (e *DifferentDayFromAndToError) Code() domainerror.ErrorCode {
 	return DifferentDayFromAndToErrorCode
 }
 
+func (e *DifferentDayFromAndToError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 // DuplicateInOneDayError
 
 type DuplicateInOneDayError struct {
@@ -98,14 +180,21 @@ func NewDuplicateInOneDayError(targetDate time.Time) *DuplicateInOneDayError {
 }
 
 func (e *DuplicateInOneDayError) Error() string {
-	return fmt.Sprintf("There is already a user schedule in the day. The user schedule date: %s",
-		e.TargetDate.String())
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *DuplicateInOneDayError) MessageArgs() []interface{} {
+	return []interface{}{e.TargetDate.String()}
 }
 
 func (e *DuplicateInOneDayError) Code() domainerror.ErrorCode {
 	return DuplicateInOneDayErrorCode
 }
 
+func (e *DuplicateInOneDayError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 // TheScheduleNotFoundError
 
 type TheScheduleNotFoundError struct {
@@ -119,14 +208,21 @@ func NewTheScheduleNotFoundError(targetDate time.Time) *TheScheduleNotFoundError
 }
 
 func (e *TheScheduleNotFoundError) Error() string {
-	return fmt.Sprintf("The specified day doesn't have a user schedule. The specified date: %s",
-		e.TargetDate.String())
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *TheScheduleNotFoundError) MessageArgs() []interface{} {
+	return []interface{}{e.TargetDate.String()}
 }
 
 func (e *TheScheduleNotFoundError) Code() domainerror.ErrorCode {
 	return TheScheduleNotFoundErrorCode
 }
 
+func (e *TheScheduleNotFoundError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 // TimeRangeIsLessThanSpecifiedError
 
 type TimeRangeIsLessThanSpecifiedError struct {
@@ -142,11 +238,17 @@ func NewTimeRangeIsLessThanSpecifiedError(fromDate time.Time, toDate time.Time)
 }
 
 func (e *TimeRangeIsLessThanSpecifiedError) Error() string {
-	return fmt.Sprintf(
-		"Time range between fromDateTime and toDateTime must not be within %d minutes. fromDateTime: %s, toDateTime: %s",
-		regulatedTimeDurationMinutes, e.FromDate.String(), e.ToDate.String())
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *TimeRangeIsLessThanSpecifiedError) MessageArgs() []interface{} {
+	return []interface{}{regulatedTimeDurationMinutes, e.FromDate.String(), e.ToDate.String()}
 }
 
 func (e *TimeRangeIsLessThanSpecifiedError) Code() domainerror.ErrorCode {
 	return TimeRangeIsLessThanSpecifiedErrorCode
 }
+
+func (e *TimeRangeIsLessThanSpecifiedError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
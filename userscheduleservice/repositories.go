@@ -1,6 +1,7 @@
 package userscheduleservice
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -70,8 +71,8 @@ type UsTagsJoinedDto struct {
 
 // IUserScheduleQueryRepository is an interface for userschedules table
 type IUserScheduleQueryRepository interface {
-	QueryUserSchedulesWhereTimeRange(beginDateTime, endDateTime time.Time, userId string) ([]*UserScheduleDto, error)
-	QueryUserScheduleWhereId(userScheduleId int64) (*UserScheduleDto, error)
+	QueryUserSchedulesWhereTimeRange(ctx context.Context, beginDateTime, endDateTime time.Time, userId string) ([]*UserScheduleDto, error)
+	QueryUserScheduleWhereId(ctx context.Context, userScheduleId int64) (*UserScheduleDto, error)
 }
 
 var _ IUserScheduleQueryRepository = (*realUserScheduleQueryRepository)(nil)
@@ -86,7 +87,7 @@ func ProvideUserScheduleRepository(db SqlDb) IUserScheduleQueryRepository {
 	}
 }
 
-func (r *realUserScheduleQueryRepository) QueryUserSchedulesWhereTimeRange(beginDateTime, endDateTime time.Time, userId string) ([]*UserScheduleDto, error) {
+func (r *realUserScheduleQueryRepository) QueryUserSchedulesWhereTimeRange(ctx context.Context, beginDateTime, endDateTime time.Time, userId string) ([]*UserScheduleDto, error) {
 	var rows *sqlx.Rows
 	baseQuery := `
 		SELECT us.userScheduleId, us.userId,
@@ -103,9 +104,9 @@ func (r *realUserScheduleQueryRepository) QueryUserSchedulesWhereTimeRange(begin
 		err error
 	)
 	if userId != "" {
-		rows, err = r.db.Queryx(baseQuery+" AND userId = ? ORDER BY userId", beginDateTime, endDateTime, userId)
+		rows, err = r.db.QueryxContext(ctx, baseQuery+" AND userId = ? ORDER BY userId", beginDateTime, endDateTime, userId)
 	} else {
-		rows, err = r.db.Queryx(baseQuery, beginDateTime, endDateTime)
+		rows, err = r.db.QueryxContext(ctx, baseQuery, beginDateTime, endDateTime)
 	}
 	if err != nil {
 		return nil, stew.Wrap(err)
@@ -122,7 +123,7 @@ func (r *realUserScheduleQueryRepository) QueryUserSchedulesWhereTimeRange(begin
 	return r.compressJoinedDtos(joinedDtos), nil
 }
 
-func (r *realUserScheduleQueryRepository) QueryUserScheduleWhereId(userScheduleId int64) (*UserScheduleDto, error) {
+func (r *realUserScheduleQueryRepository) QueryUserScheduleWhereId(ctx context.Context, userScheduleId int64) (*UserScheduleDto, error) {
 	var query = `
 		SELECT us.userScheduleId, us.userId,
 			   us.fromDateTime, us.toDateTime,
@@ -134,7 +135,7 @@ func (r *realUserScheduleQueryRepository) QueryUserScheduleWhereId(userScheduleI
 		LEFT JOIN userscheduletags ust ON us.userScheduleId=ust.userScheduleId
 		WHERE us.userScheduleId = ?
 	`
-	rows, err := r.db.Queryx(query, userScheduleId)
+	rows, err := r.db.QueryxContext(ctx, query, userScheduleId)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -193,9 +194,9 @@ func (r *realUserScheduleQueryRepository) compressJoinedDtos(joinedDtos []*UsTag
 }
 
 type IUserScheduleCommandRepository interface {
-	InsertUserSchedule(dto *UserScheduleDto) (int64, error)
-	UpdateUserSchedule(userScheduleId int64, dto *UserScheduleDto) (int64, error)
-	DeleteUserSchedule(userScheduleId int64) error
+	InsertUserSchedule(ctx context.Context, dto *UserScheduleDto) (int64, error)
+	UpdateUserSchedule(ctx context.Context, userScheduleId int64, dto *UserScheduleDto) (int64, error)
+	DeleteUserSchedule(ctx context.Context, userScheduleId int64) error
 }
 
 var _ IUserScheduleCommandRepository = (*realUserScheduleCommandRepository)(nil)
@@ -210,14 +211,14 @@ func ProvideRealUserScheduleUpdateRepository(db SqlDb) IUserScheduleCommandRepos
 	}
 }
 
-func (r *realUserScheduleCommandRepository) InsertUserSchedule(dto *UserScheduleDto) (int64, error) {
-	tx, err := r.db.Begin()
+func (r *realUserScheduleCommandRepository) InsertUserSchedule(ctx context.Context, dto *UserScheduleDto) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, stew.Wrap(err)
 	}
 
 	// Insert into userschedules table
-	res, err := tx.Exec(`
+	res, err := tx.ExecContext(ctx, `
 		INSERT INTO userschedules
 		(userId, fromDateTime, toDateTime) VALUES (?, ?, ?)`,
 		dto.userId, dto.fromDateTime, dto.toDateTime)
@@ -232,7 +233,7 @@ func (r *realUserScheduleCommandRepository) InsertUserSchedule(dto *UserSchedule
 	}
 
 	// Insert into userschedulelocations table
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO userschedulelocations
 		(userScheduleId, latitude, longitude) VALUES (?, ?, ?)`,
 		lastInsertedUserScheduleId, dto.latitude, dto.longitude)
@@ -243,7 +244,7 @@ func (r *realUserScheduleCommandRepository) InsertUserSchedule(dto *UserSchedule
 
 	// Insert into userscheduletags table
 	for _, tagId := range dto.tagIds {
-		_, err = tx.Exec(`
+		_, err = tx.ExecContext(ctx, `
 			INSERT INTO userscheduletags
 			(userScheduleId, tagId) VALUES (?, ?)`,
 			lastInsertedUserScheduleId, tagId)
@@ -261,14 +262,14 @@ func (r *realUserScheduleCommandRepository) InsertUserSchedule(dto *UserSchedule
 	return lastInsertedUserScheduleId, nil
 }
 
-func (r *realUserScheduleCommandRepository) UpdateUserSchedule(userScheduleId int64, dto *UserScheduleDto) (int64, error) {
-	tx, err := r.db.Begin()
+func (r *realUserScheduleCommandRepository) UpdateUserSchedule(ctx context.Context, userScheduleId int64, dto *UserScheduleDto) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, stew.Wrap(err)
 	}
 
 	// Delete existing userscheduletags table
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		DELETE FROM userscheduletags
 		WHERE userScheduleId = ?`,
 		userScheduleId)
@@ -278,7 +279,7 @@ func (r *realUserScheduleCommandRepository) UpdateUserSchedule(userScheduleId in
 	}
 	// Insert into the updated userscheduletags table
 	for _, tagId := range dto.tagIds {
-		_, err = tx.Exec(`
+		_, err = tx.ExecContext(ctx, `
 			INSERT INTO userscheduletags
 			(userScheduleId, tagId) VALUES (?, ?)`,
 			userScheduleId, tagId)
@@ -289,7 +290,7 @@ func (r *realUserScheduleCommandRepository) UpdateUserSchedule(userScheduleId in
 	}
 
 	// Update userschedulelocations table
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		UPDATE userschedulelocations
 		SET latitude = ?, longitude = ? WHERE userScheduleId = ?`,
 		dto.latitude, dto.longitude, userScheduleId)
@@ -299,7 +300,7 @@ func (r *realUserScheduleCommandRepository) UpdateUserSchedule(userScheduleId in
 	}
 
 	// Update userschedules table
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		UPDATE userschedules
 		SET fromDateTime = ?, toDateTime = ? WHERE userScheduleId = ?`,
 		dto.fromDateTime, dto.toDateTime, userScheduleId)
@@ -316,14 +317,14 @@ func (r *realUserScheduleCommandRepository) UpdateUserSchedule(userScheduleId in
 	return userScheduleId, nil
 }
 
-func (r *realUserScheduleCommandRepository) DeleteUserSchedule(userScheduleId int64) error {
-	tx, err := r.db.Begin()
+func (r *realUserScheduleCommandRepository) DeleteUserSchedule(ctx context.Context, userScheduleId int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return stew.Wrap(err)
 	}
 
 	// userschedules
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		DELETE FROM userschedules
 		WHERE userScheduleId = ?`,
 		userScheduleId)
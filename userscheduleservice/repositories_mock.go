@@ -5,6 +5,7 @@
 package userscheduleservice
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 	time "time"
@@ -34,33 +35,33 @@ func (m *MockIUserScheduleQueryRepository) EXPECT() *MockIUserScheduleQueryRepos
 }
 
 // QueryUserSchedulesWhereTimeRange mocks base method
-func (m *MockIUserScheduleQueryRepository) QueryUserSchedulesWhereTimeRange(beginDateTime, endDateTime time.Time, userId string) ([]*UserScheduleDto, error) {
+func (m *MockIUserScheduleQueryRepository) QueryUserSchedulesWhereTimeRange(ctx context.Context, beginDateTime, endDateTime time.Time, userId string) ([]*UserScheduleDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryUserSchedulesWhereTimeRange", beginDateTime, endDateTime, userId)
+	ret := m.ctrl.Call(m, "QueryUserSchedulesWhereTimeRange", ctx, beginDateTime, endDateTime, userId)
 	ret0, _ := ret[0].([]*UserScheduleDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryUserSchedulesWhereTimeRange indicates an expected call of QueryUserSchedulesWhereTimeRange
-func (mr *MockIUserScheduleQueryRepositoryMockRecorder) QueryUserSchedulesWhereTimeRange(beginDateTime, endDateTime, userId interface{}) *gomock.Call {
+func (mr *MockIUserScheduleQueryRepositoryMockRecorder) QueryUserSchedulesWhereTimeRange(ctx, beginDateTime, endDateTime, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserSchedulesWhereTimeRange", reflect.TypeOf((*MockIUserScheduleQueryRepository)(nil).QueryUserSchedulesWhereTimeRange), beginDateTime, endDateTime, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserSchedulesWhereTimeRange", reflect.TypeOf((*MockIUserScheduleQueryRepository)(nil).QueryUserSchedulesWhereTimeRange), ctx, beginDateTime, endDateTime, userId)
 }
 
 // QueryUserScheduleWhereId mocks base method
-func (m *MockIUserScheduleQueryRepository) QueryUserScheduleWhereId(userScheduleId int64) (*UserScheduleDto, error) {
+func (m *MockIUserScheduleQueryRepository) QueryUserScheduleWhereId(ctx context.Context, userScheduleId int64) (*UserScheduleDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryUserScheduleWhereId", userScheduleId)
+	ret := m.ctrl.Call(m, "QueryUserScheduleWhereId", ctx, userScheduleId)
 	ret0, _ := ret[0].(*UserScheduleDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryUserScheduleWhereId indicates an expected call of QueryUserScheduleWhereId
-func (mr *MockIUserScheduleQueryRepositoryMockRecorder) QueryUserScheduleWhereId(userScheduleId interface{}) *gomock.Call {
+func (mr *MockIUserScheduleQueryRepositoryMockRecorder) QueryUserScheduleWhereId(ctx, userScheduleId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserScheduleWhereId", reflect.TypeOf((*MockIUserScheduleQueryRepository)(nil).QueryUserScheduleWhereId), userScheduleId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserScheduleWhereId", reflect.TypeOf((*MockIUserScheduleQueryRepository)(nil).QueryUserScheduleWhereId), ctx, userScheduleId)
 }
 
 // MockIUserScheduleCommandRepository is a mock of IUserScheduleCommandRepository interface
@@ -87,45 +88,45 @@ func (m *MockIUserScheduleCommandRepository) EXPECT() *MockIUserScheduleCommandR
 }
 
 // InsertUserSchedule mocks base method
-func (m *MockIUserScheduleCommandRepository) InsertUserSchedule(dto *UserScheduleDto) (int64, error) {
+func (m *MockIUserScheduleCommandRepository) InsertUserSchedule(ctx context.Context, dto *UserScheduleDto) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUserSchedule", dto)
+	ret := m.ctrl.Call(m, "InsertUserSchedule", ctx, dto)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InsertUserSchedule indicates an expected call of InsertUserSchedule
-func (mr *MockIUserScheduleCommandRepositoryMockRecorder) InsertUserSchedule(dto interface{}) *gomock.Call {
+func (mr *MockIUserScheduleCommandRepositoryMockRecorder) InsertUserSchedule(ctx, dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserSchedule", reflect.TypeOf((*MockIUserScheduleCommandRepository)(nil).InsertUserSchedule), dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserSchedule", reflect.TypeOf((*MockIUserScheduleCommandRepository)(nil).InsertUserSchedule), ctx, dto)
 }
 
 // UpdateUserSchedule mocks base method
-func (m *MockIUserScheduleCommandRepository) UpdateUserSchedule(userScheduleId int64, dto *UserScheduleDto) (int64, error) {
+func (m *MockIUserScheduleCommandRepository) UpdateUserSchedule(ctx context.Context, userScheduleId int64, dto *UserScheduleDto) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUserSchedule", userScheduleId, dto)
+	ret := m.ctrl.Call(m, "UpdateUserSchedule", ctx, userScheduleId, dto)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateUserSchedule indicates an expected call of UpdateUserSchedule
-func (mr *MockIUserScheduleCommandRepositoryMockRecorder) UpdateUserSchedule(userScheduleId, dto interface{}) *gomock.Call {
+func (mr *MockIUserScheduleCommandRepositoryMockRecorder) UpdateUserSchedule(ctx, userScheduleId, dto interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSchedule", reflect.TypeOf((*MockIUserScheduleCommandRepository)(nil).UpdateUserSchedule), userScheduleId, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserSchedule", reflect.TypeOf((*MockIUserScheduleCommandRepository)(nil).UpdateUserSchedule), ctx, userScheduleId, dto)
 }
 
 // DeleteUserSchedule mocks base method
-func (m *MockIUserScheduleCommandRepository) DeleteUserSchedule(userScheduleId int64) error {
+func (m *MockIUserScheduleCommandRepository) DeleteUserSchedule(ctx context.Context, userScheduleId int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUserSchedule", userScheduleId)
+	ret := m.ctrl.Call(m, "DeleteUserSchedule", ctx, userScheduleId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUserSchedule indicates an expected call of DeleteUserSchedule
-func (mr *MockIUserScheduleCommandRepositoryMockRecorder) DeleteUserSchedule(userScheduleId interface{}) *gomock.Call {
+func (mr *MockIUserScheduleCommandRepositoryMockRecorder) DeleteUserSchedule(ctx, userScheduleId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSchedule", reflect.TypeOf((*MockIUserScheduleCommandRepository)(nil).DeleteUserSchedule), userScheduleId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSchedule", reflect.TypeOf((*MockIUserScheduleCommandRepository)(nil).DeleteUserSchedule), ctx, userScheduleId)
 }
@@ -5,6 +5,7 @@
 package testmock
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	tagservice "github.com/momotaro98/mixlunch-service-api/tagservice"
 	reflect "reflect"
@@ -34,31 +35,31 @@ func (m *MockTagServer) EXPECT() *MockTagServerMockRecorder {
 }
 
 // GetTagsByTagType mocks base method
-func (m *MockTagServer) GetTagsByTagType(tagType tagservice.TagType) ([]*tagservice.CategoryTags, error) {
+func (m *MockTagServer) GetTagsByTagType(ctx context.Context, tagType tagservice.TagType) ([]*tagservice.CategoryTags, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTagsByTagType", tagType)
+	ret := m.ctrl.Call(m, "GetTagsByTagType", ctx, tagType)
 	ret0, _ := ret[0].([]*tagservice.CategoryTags)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTagsByTagType indicates an expected call of GetTagsByTagType
-func (mr *MockTagServerMockRecorder) GetTagsByTagType(tagType interface{}) *gomock.Call {
+func (mr *MockTagServerMockRecorder) GetTagsByTagType(ctx, tagType interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByTagType", reflect.TypeOf((*MockTagServer)(nil).GetTagsByTagType), tagType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByTagType", reflect.TypeOf((*MockTagServer)(nil).GetTagsByTagType), ctx, tagType)
 }
 
 // GetTagsByTagTypeAndTagIds mocks base method
-func (m *MockTagServer) GetTagsByTagTypeAndTagIds(tagType tagservice.TagType, tagIds []uint16) ([]*tagservice.CategoryTags, error) {
+func (m *MockTagServer) GetTagsByTagTypeAndTagIds(ctx context.Context, tagType tagservice.TagType, tagIds []uint16) ([]*tagservice.CategoryTags, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTagsByTagTypeAndTagIds", tagType, tagIds)
+	ret := m.ctrl.Call(m, "GetTagsByTagTypeAndTagIds", ctx, tagType, tagIds)
 	ret0, _ := ret[0].([]*tagservice.CategoryTags)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTagsByTagTypeAndTagIds indicates an expected call of GetTagsByTagTypeAndTagIds
-func (mr *MockTagServerMockRecorder) GetTagsByTagTypeAndTagIds(tagType, tagIds interface{}) *gomock.Call {
+func (mr *MockTagServerMockRecorder) GetTagsByTagTypeAndTagIds(ctx, tagType, tagIds interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByTagTypeAndTagIds", reflect.TypeOf((*MockTagServer)(nil).GetTagsByTagTypeAndTagIds), tagType, tagIds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByTagTypeAndTagIds", reflect.TypeOf((*MockTagServer)(nil).GetTagsByTagTypeAndTagIds), ctx, tagType, tagIds)
 }
@@ -4,20 +4,11 @@ import (
 	"database/sql"
 )
 
-type Config struct {
-	DSN string
-}
-
 type SqlDb struct {
 	*sql.DB
 }
 
-func ProvideDB(cfg *Config) SqlDb {
-	dsn := cfg.DSN
-	var err error
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		panic(err)
-	}
+// ProvideDB provides the DB pool shared by the services
+func ProvideDB(db *sql.DB) SqlDb {
 	return SqlDb{db}
 }
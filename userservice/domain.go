@@ -1,6 +1,7 @@
 package userservice
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"github.com/momotaro98/mixlunch-service-api/conventions"
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
 	"github.com/momotaro98/mixlunch-service-api/tagservice"
+	"github.com/momotaro98/mixlunch-service-api/tracing"
 	"github.com/momotaro98/mixlunch-service-api/utils"
 )
 
@@ -78,10 +80,14 @@ type (
 )
 
 type UserServer interface {
-	GetUserByUserId(userId string) (*User, error)
-	GetUserPublicByUserId(userId string) (*UserPublic, error)
-	RegisterUser(newUser *UserForCommand) (*User, error)
-	RegisterUserBlock(newUserBlock *UserBlockForCommand) ([]*UserBlockForQuery, error)
+	GetUserByUserId(ctx context.Context, userId string) (*User, error)
+	GetUserPublicByUserId(ctx context.Context, userId string) (*UserPublic, error)
+	RegisterUser(ctx context.Context, newUser *UserForCommand) (*User, error)
+	UpdateUser(ctx context.Context, user *UserForCommand) (*User, error)
+	PatchUser(ctx context.Context, userId string, update *UserForUpdate) (*User, error)
+	RegisterUserBlock(ctx context.Context, newUserBlock *UserBlockForCommand) ([]*UserBlockForQuery, error)
+	DeleteUserBlock(ctx context.Context, userBlock *UserBlockForCommand) (*UserBlockForCommand, error)
+	GetUserBlocks(ctx context.Context, query *UserBlockPageQuery) (*UserBlockPage, error)
 }
 
 type realUserServer struct {
@@ -102,9 +108,11 @@ func ProvideUserServer(userQueryRepository IUserQueryRepository,
 
 // GetUserByUserId does query User info by user ID.
 // If the user is not in DB, return (nil, nil)
-func (s *realUserServer) GetUserByUserId(userId string) (*User, error) {
+func (s *realUserServer) GetUserByUserId(ctx context.Context, userId string) (*User, error) {
+	ctx, span := tracing.Start(ctx, "userservice.GetUserByUserId")
+	defer span.End()
 	var user User
-	uDto, err := s.userQueryRepository.QueryUserFullByUsingUserId(userId)
+	uDto, err := s.userQueryRepository.QueryUserFullByUsingUserId(ctx, userId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -143,8 +151,8 @@ func (s *realUserServer) GetUserByUserId(userId string) (*User, error) {
 	}
 
 	// Query tags for user tags
-	user.InterestTags, err = s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.Interest, uDto.usertags)
-	user.SkillTags, err = s.tagServer.GetTagsByTagTypeAndTagIds(tagservice.Skill, uDto.usertags)
+	user.InterestTags, err = s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.Interest, uDto.usertags)
+	user.SkillTags, err = s.tagServer.GetTagsByTagTypeAndTagIds(ctx, tagservice.Skill, uDto.usertags)
 
 	// Blocking Users
 	if uDto.blockingUsers == nil {
@@ -160,8 +168,10 @@ func (s *realUserServer) GetUserByUserId(userId string) (*User, error) {
 
 // GetUserByUserId does query User with simple model info by user ID.
 // If the user is not in DB, return (nil, nil)
-func (s *realUserServer) GetUserPublicByUserId(userId string) (*UserPublic, error) {
-	user, err := s.GetUserByUserId(userId)
+func (s *realUserServer) GetUserPublicByUserId(ctx context.Context, userId string) (*UserPublic, error) {
+	ctx, span := tracing.Start(ctx, "userservice.GetUserPublicByUserId")
+	defer span.End()
+	user, err := s.GetUserByUserId(ctx, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -185,52 +195,19 @@ func (s *realUserServer) GetUserPublicByUserId(userId string) (*UserPublic, erro
 	return userPublic, nil
 }
 
-func (s *realUserServer) RegisterUser(newUser *UserForCommand) (*User, error) {
+func (s *realUserServer) RegisterUser(ctx context.Context, newUser *UserForCommand) (*User, error) {
+	ctx, span := tracing.Start(ctx, "userservice.RegisterUser")
+	defer span.End()
 	// Validation
 	if err := Validate(newUser); err != nil {
 		return nil, domainerror.NewValidationError(err)
 	}
 
-	// Map from user domain model to DTO with Validation
-	var uDto UserCommandDto
-	uDto.userId = newUser.UserId
-	uDto.name = newUser.Name
-	uDto.email = newUser.Email
-	uDto.nickName = utils.NewNullString(newUser.NickName)
-	uDto.sex = newUser.Sex
-	uDto.birthday, _ = utils.MakeDateTimeFromStringDate(newUser.Birthday)
-	uDto.photoUrl = utils.NewNullString(newUser.PhotoUrl)
-	{
-		// Location
-		uDto.latitude = newUser.Location.Latitude
-		uDto.longitude = newUser.Location.Longitude
-	}
-	uDto.positionId = utils.NewNullInt32(int32(newUser.PositionId))
-	uDto.academicBackground = utils.NewNullString(newUser.AcademicBackground)
-	uDto.company = utils.NewNullString(newUser.Company)
-	uDto.selfIntroduction = utils.NewNullString(newUser.SelfIntroduction)
-	{
-		userlangs := make([]string, 0, len(newUser.Languages))
-		for _, l := range newUser.Languages {
-			userlangs = append(userlangs, string(l))
-		}
-		uDto.userlangs = userlangs
-	}
-	uDto.occupationIDs = newUser.OccupationIDs
-	{
-		// InterestTags and SkillTags
-		var usertags []uint16
-		for _, tagId := range newUser.InterestTagIds {
-			usertags = append(usertags, tagId)
-		}
-		for _, tagId := range newUser.SkillTagIds {
-			usertags = append(usertags, tagId)
-		}
-		uDto.usertags = usertags
-	}
+	// Map from user domain model to DTO
+	uDto := newUserCommandDto(newUser)
 
 	// Add the new user into DB
-	err := s.userCommandRepository.InsertUserInfo(&uDto)
+	err := s.userCommandRepository.InsertUserInfo(ctx, uDto)
 	if err != nil {
 		var repoErr RepositoryError
 		if errors.As(err, &repoErr) {
@@ -243,7 +220,7 @@ func (s *realUserServer) RegisterUser(newUser *UserForCommand) (*User, error) {
 	}
 
 	// Query the new user
-	if registeredUser, err := s.GetUserByUserId(uDto.userId); err == nil {
+	if registeredUser, err := s.GetUserByUserId(ctx, uDto.userId); err == nil {
 		if registeredUser == nil { // No user is too irregular case since registering succeeded
 			return nil, errors.New(fmt.Sprintf("User was created but could not get the information. user id: %s", uDto.userId))
 		}
@@ -252,6 +229,287 @@ func (s *realUserServer) RegisterUser(newUser *UserForCommand) (*User, error) {
 	return nil, stew.Wrap(err)
 }
 
+// UserForUpdate is a user struct to update a part of user info. A nil field keeps the current value,
+// and an empty list clears the current values.
+// The merged user is validated as UserForCommand.
+type UserForUpdate struct {
+	Name               *string               `json:"name"`
+	Email              *string               `json:"email"`
+	NickName           *string               `json:"nick_name"`
+	Sex                *string               `json:"sex"`
+	Birthday           *string               `json:"birthday"`
+	PhotoUrl           *string               `json:"photo_url"`
+	Location           *conventions.Location `json:"location"`
+	PositionId         *uint8                `json:"position_id"`
+	AcademicBackground *string               `json:"academic_background"`
+	Company            *string               `json:"company"`
+	SelfIntroduction   *string               `json:"self_introduction"`
+	Languages          []Language            `json:"languages"`
+	OccupationIDs      []uint8               `json:"occupation_ids"`
+	InterestTagIds     []uint16              `json:"interest_tag_ids"`
+	SkillTagIds        []uint16              `json:"skill_tag_ids"`
+}
+
+// UpdateUser replaces the user info with the given one, which is validated as RegisterUser does.
+// The tags of the other types than the interest and the skill are kept as they are not in UserForCommand.
+// If the user is not in DB, return UserNotFoundError
+func (s *realUserServer) UpdateUser(ctx context.Context, user *UserForCommand) (*User, error) {
+	ctx, span := tracing.Start(ctx, "userservice.UpdateUser")
+	defer span.End()
+	// Validation
+	if err := Validate(user); err != nil {
+		return nil, domainerror.NewValidationError(err)
+	}
+
+	// The tag master is loaded before the transaction not to wait for another connection in it
+	tagTypes, err := s.getUserTagTypes(ctx)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+
+	// Update the user in DB
+	err = s.userCommandRepository.UpdateUserInfo(ctx, user.UserId, func(current *UserFullQueryDto) (*UserCommandDto, error) {
+		_, otherTagIds := newUserForCommand(current, tagTypes)
+		uDto := newUserCommandDto(user)
+		uDto.usertags = append(uDto.usertags, otherTagIds...)
+		return uDto, nil
+	})
+	if err != nil {
+		return nil, updateUserInfoError(user.UserId, err)
+	}
+
+	return s.getUpdatedUser(ctx, user.UserId)
+}
+
+// PatchUser updates the fields of the user info given by UserForUpdate and keeps the others.
+// The current user info is read and merged in the transaction of the update not to lose another update.
+// If the user is not in DB, return UserNotFoundError
+func (s *realUserServer) PatchUser(ctx context.Context, userId string, update *UserForUpdate) (*User, error) {
+	ctx, span := tracing.Start(ctx, "userservice.PatchUser")
+	defer span.End()
+	// The tag master is loaded before the transaction not to wait for another connection in it
+	tagTypes, err := s.getUserTagTypes(ctx)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	err = s.userCommandRepository.UpdateUserInfo(ctx, userId, func(current *UserFullQueryDto) (*UserCommandDto, error) {
+		user, otherTagIds := newUserForCommand(current, tagTypes)
+		mergeUserForUpdate(user, update)
+
+		// Validation
+		if err := Validate(user); err != nil {
+			return nil, domainerror.NewValidationError(err)
+		}
+
+		uDto := newUserCommandDto(user)
+		// The tags of the other types than the interest and the skill are not in the patch
+		uDto.usertags = append(uDto.usertags, otherTagIds...)
+		return uDto, nil
+	})
+	if err != nil {
+		return nil, updateUserInfoError(userId, err)
+	}
+
+	return s.getUpdatedUser(ctx, userId)
+}
+
+// mergeUserForUpdate overwrites the user with the given fields of the update
+func mergeUserForUpdate(user *UserForCommand, update *UserForUpdate) {
+	if update.Name != nil {
+		user.Name = *update.Name
+	}
+	if update.Email != nil {
+		user.Email = *update.Email
+	}
+	if update.NickName != nil {
+		user.NickName = *update.NickName
+	}
+	if update.Sex != nil {
+		user.Sex = *update.Sex
+	}
+	if update.Birthday != nil {
+		user.Birthday = *update.Birthday
+	}
+	if update.PhotoUrl != nil {
+		user.PhotoUrl = *update.PhotoUrl
+	}
+	if update.Location != nil {
+		user.Location = *update.Location
+	}
+	if update.PositionId != nil {
+		user.PositionId = *update.PositionId
+	}
+	if update.AcademicBackground != nil {
+		user.AcademicBackground = *update.AcademicBackground
+	}
+	if update.Company != nil {
+		user.Company = *update.Company
+	}
+	if update.SelfIntroduction != nil {
+		user.SelfIntroduction = *update.SelfIntroduction
+	}
+	if update.Languages != nil {
+		user.Languages = update.Languages
+	}
+	if update.OccupationIDs != nil {
+		user.OccupationIDs = update.OccupationIDs
+	}
+	if update.InterestTagIds != nil {
+		user.InterestTagIds = update.InterestTagIds
+	}
+	if update.SkillTagIds != nil {
+		user.SkillTagIds = update.SkillTagIds
+	}
+}
+
+// updateUserInfoError maps the error of updating the user info in DB to the domain errors
+func updateUserInfoError(userId string, err error) error {
+	var domainErr domainerror.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewUserNotFoundError(userId)
+	}
+	var repoErr RepositoryError
+	if errors.As(err, &repoErr) {
+		switch repoErr.(type) {
+		case *NoReferenceRowError:
+			return NewUnknownUserReferenceError(userId)
+		}
+	}
+	return stew.Wrap(err)
+}
+
+// getUpdatedUser queries the user just updated
+func (s *realUserServer) getUpdatedUser(ctx context.Context, userId string) (*User, error) {
+	updatedUser, err := s.GetUserByUserId(ctx, userId)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	if updatedUser == nil { // The user was deleted just after the update
+		return nil, NewUserNotFoundError(userId)
+	}
+	return updatedUser, nil
+}
+
+// userTagTypes is the tag IDs of the interest and the skill tags in the tag master
+type userTagTypes struct {
+	interest map[uint16]bool
+	skill    map[uint16]bool
+}
+
+// getUserTagTypes loads the tag IDs of the interest and the skill tags
+func (s *realUserServer) getUserTagTypes(ctx context.Context) (*userTagTypes, error) {
+	interestTags, err := s.tagServer.GetTagsByTagType(ctx, tagservice.Interest)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	skillTags, err := s.tagServer.GetTagsByTagType(ctx, tagservice.Skill)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	tagTypes := &userTagTypes{interest: make(map[uint16]bool), skill: make(map[uint16]bool)}
+	for _, tagId := range tagIdsOf(interestTags) {
+		tagTypes.interest[tagId] = true
+	}
+	for _, tagId := range tagIdsOf(skillTags) {
+		tagTypes.skill[tagId] = true
+	}
+	return tagTypes, nil
+}
+
+// newUserForCommand maps the current user info to UserForCommand.
+// The tags of the other types than the interest and the skill are returned separately.
+func newUserForCommand(uDto *UserFullQueryDto, tagTypes *userTagTypes) (*UserForCommand, []uint16) {
+	user := &UserForCommand{
+		UserId:             uDto.userId,
+		Name:               uDto.name,
+		Email:              uDto.email,
+		NickName:           uDto.nickName.String,
+		Sex:                uDto.sex,
+		Birthday:           uDto.birthday.Format("2006-01-02"),
+		PhotoUrl:           uDto.photoUrl.String,
+		Location:           conventions.Location{Latitude: uDto.latitude, Longitude: uDto.longitude},
+		PositionId:         uint8(uDto.positionId.Int32),
+		AcademicBackground: uDto.academicBackground.String,
+		Company:            uDto.company.String,
+		SelfIntroduction:   uDto.selfIntroduction.String,
+	}
+	for _, l := range uDto.userlangs {
+		user.Languages = append(user.Languages, Language(l))
+	}
+	for _, oID := range uDto.useroccupations {
+		user.OccupationIDs = append(user.OccupationIDs, uint8(oID))
+	}
+
+	// Split the user tags into the interest tags, the skill tags and the others
+	var otherTagIds []uint16
+	for _, tagId := range uDto.usertags {
+		switch {
+		case tagTypes.interest[tagId]:
+			user.InterestTagIds = append(user.InterestTagIds, tagId)
+		case tagTypes.skill[tagId]:
+			user.SkillTagIds = append(user.SkillTagIds, tagId)
+		default:
+			otherTagIds = append(otherTagIds, tagId)
+		}
+	}
+
+	return user, otherTagIds
+}
+
+func tagIdsOf(categoryTagsList []*tagservice.CategoryTags) []uint16 {
+	var tagIds []uint16
+	for _, ct := range categoryTagsList {
+		for _, t := range ct.Tags {
+			tagIds = append(tagIds, t.TagId)
+		}
+	}
+	return tagIds
+}
+
+// newUserCommandDto maps from user domain model to DTO
+func newUserCommandDto(user *UserForCommand) *UserCommandDto {
+	var uDto UserCommandDto
+	uDto.userId = user.UserId
+	uDto.name = user.Name
+	uDto.email = user.Email
+	uDto.nickName = utils.NewNullString(user.NickName)
+	uDto.sex = user.Sex
+	uDto.birthday, _ = utils.MakeDateTimeFromStringDate(user.Birthday)
+	uDto.photoUrl = utils.NewNullString(user.PhotoUrl)
+	{
+		// Location
+		uDto.latitude = user.Location.Latitude
+		uDto.longitude = user.Location.Longitude
+	}
+	uDto.positionId = utils.NewNullInt32(int32(user.PositionId))
+	uDto.academicBackground = utils.NewNullString(user.AcademicBackground)
+	uDto.company = utils.NewNullString(user.Company)
+	uDto.selfIntroduction = utils.NewNullString(user.SelfIntroduction)
+	{
+		userlangs := make([]string, 0, len(user.Languages))
+		for _, l := range user.Languages {
+			userlangs = append(userlangs, string(l))
+		}
+		uDto.userlangs = userlangs
+	}
+	uDto.occupationIDs = user.OccupationIDs
+	{
+		// InterestTags and SkillTags
+		var usertags []uint16
+		for _, tagId := range user.InterestTagIds {
+			usertags = append(usertags, tagId)
+		}
+		for _, tagId := range user.SkillTagIds {
+			usertags = append(usertags, tagId)
+		}
+		uDto.usertags = usertags
+	}
+	return &uDto
+}
+
 type UserBlockForCommand struct {
 	Blocker string `json:"blocker" validate:"required"`
 	Blockee string `json:"blockee" validate:"required"`
@@ -263,7 +521,9 @@ type UserBlockForQuery struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-func (s *realUserServer) RegisterUserBlock(newUserBlock *UserBlockForCommand) ([]*UserBlockForQuery, error) {
+func (s *realUserServer) RegisterUserBlock(ctx context.Context, newUserBlock *UserBlockForCommand) ([]*UserBlockForQuery, error) {
+	ctx, span := tracing.Start(ctx, "userservice.RegisterUserBlock")
+	defer span.End()
 	// Validation
 	if err := Validate(newUserBlock); err != nil {
 		return nil, domainerror.NewValidationError(err)
@@ -275,7 +535,7 @@ func (s *realUserServer) RegisterUserBlock(newUserBlock *UserBlockForCommand) ([
 		blockee: newUserBlock.Blockee,
 	}
 
-	if err := s.userCommandRepository.InsertUserBlock(&ubDto); err != nil {
+	if err := s.userCommandRepository.InsertUserBlock(ctx, &ubDto); err != nil {
 		var repoErr RepositoryError
 		if errors.As(err, &repoErr) {
 			switch repoErr.(type) {
@@ -288,7 +548,7 @@ func (s *realUserServer) RegisterUserBlock(newUserBlock *UserBlockForCommand) ([
 		return nil, stew.Wrap(err)
 	}
 
-	ubOfTheBlocker, err := s.userQueryRepository.QueryUserBlockWhereBlocker(newUserBlock.Blocker)
+	ubOfTheBlocker, err := s.userQueryRepository.QueryUserBlockWhereBlocker(ctx, newUserBlock.Blocker)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -307,3 +567,71 @@ func (s *realUserServer) RegisterUserBlock(newUserBlock *UserBlockForCommand) ([
 
 	return []*UserBlockForQuery{}, nil
 }
+
+// DeleteUserBlock unblocks the blockee, who can be matched with the blocker again from the next matching.
+// If the blocker doesn't block the blockee, return UserBlockNotFoundError
+func (s *realUserServer) DeleteUserBlock(ctx context.Context, userBlock *UserBlockForCommand) (*UserBlockForCommand, error) {
+	ctx, span := tracing.Start(ctx, "userservice.DeleteUserBlock")
+	defer span.End()
+	// Validation
+	if err := Validate(userBlock); err != nil {
+		return nil, domainerror.NewValidationError(err)
+	}
+
+	var ubDto = UserBlockCommandDto{
+		blocker: userBlock.Blocker,
+		blockee: userBlock.Blockee,
+	}
+	if err := s.userCommandRepository.DeleteUserBlock(ctx, &ubDto); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewUserBlockNotFoundError(userBlock.Blocker, userBlock.Blockee)
+		}
+		return nil, stew.Wrap(err)
+	}
+	return userBlock, nil
+}
+
+// UserBlockPageQuery is the page of the blocks of the blocker to be got
+type UserBlockPageQuery struct {
+	Blocker string `json:"blocker" validate:"required"`
+	Offset  int    `json:"offset" validate:"min=0"`
+	Limit   int    `json:"limit" validate:"min=1,max=100"`
+}
+
+// UserBlockPage is a page of the blocks from the newest.
+// NextOffset is the offset of the next page, which is null on the last page.
+type UserBlockPage struct {
+	Blocks     []*UserBlockForQuery `json:"blocks"`
+	NextOffset *int                 `json:"next_offset"`
+}
+
+// GetUserBlocks gets a page of the blocks of the blocker
+func (s *realUserServer) GetUserBlocks(ctx context.Context, query *UserBlockPageQuery) (*UserBlockPage, error) {
+	ctx, span := tracing.Start(ctx, "userservice.GetUserBlocks")
+	defer span.End()
+	// Validation
+	if err := Validate(query); err != nil {
+		return nil, domainerror.NewValidationError(err)
+	}
+
+	// Querying one more block to know whether the next page exists
+	ubDtos, err := s.userQueryRepository.QueryUserBlockPageWhereBlocker(ctx, query.Blocker, query.Offset, query.Limit+1)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+
+	page := &UserBlockPage{Blocks: make([]*UserBlockForQuery, 0, len(ubDtos))}
+	if len(ubDtos) > query.Limit {
+		ubDtos = ubDtos[:query.Limit]
+		nextOffset := query.Offset + query.Limit
+		page.NextOffset = &nextOffset
+	}
+	for _, ubDto := range ubDtos {
+		page.Blocks = append(page.Blocks, &UserBlockForQuery{
+			Blocker:   ubDto.blocker,
+			Blockee:   ubDto.blockee,
+			CreatedAt: ubDto.createdAt,
+		})
+	}
+	return page, nil
+}
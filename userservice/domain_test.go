@@ -1,12 +1,16 @@
 package userservice
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/momotaro98/stew"
 
 	"github.com/momotaro98/mixlunch-service-api/conventions"
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
@@ -61,15 +65,15 @@ func TestGetUserByUserId_RegularAllCase_Success(t *testing.T) {
 	// Mock for query repository
 	userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
 	userQueryRepositoryMock.EXPECT().
-		QueryUserFullByUsingUserId(uid).
+		QueryUserFullByUsingUserId(gomock.Any(), uid).
 		Return(regularUserFullQueryDto, nil)
 	// Mock for tag service
 	tagServerMock := mock.NewMockTagServer(mockCtrl)
 	// GetTagsByTagTypeAndTagIds method is called twice in GeUserByUserId method
 	gomock.InOrder(
-		tagServerMock.EXPECT().GetTagsByTagTypeAndTagIds(tagservice.Interest, nil).
+		tagServerMock.EXPECT().GetTagsByTagTypeAndTagIds(gomock.Any(), tagservice.Interest, nil).
 			Return([]*tagservice.CategoryTags{}, nil),
-		tagServerMock.EXPECT().GetTagsByTagTypeAndTagIds(tagservice.Skill, nil).
+		tagServerMock.EXPECT().GetTagsByTagTypeAndTagIds(gomock.Any(), tagservice.Skill, nil).
 			Return([]*tagservice.CategoryTags{}, nil),
 	)
 	// Mock for command repository
@@ -79,7 +83,7 @@ func TestGetUserByUserId_RegularAllCase_Success(t *testing.T) {
 	userServer := ProvideUserServer(userQueryRepositoryMock, tagServerMock, userCommandRepositoryMock)
 
 	// Act
-	actUser, err := userServer.GetUserByUserId(uid)
+	actUser, err := userServer.GetUserByUserId(context.Background(), uid)
 	// Assert
 	if err != nil {
 		t.Failed()
@@ -104,7 +108,7 @@ func TestRegisterUser_ValidateErrors(t *testing.T) {
 
 	testValidate := func(t *testing.T, userServer UserServer, user *UserForCommand) {
 		// Act
-		_, err := userServer.RegisterUser(user)
+		_, err := userServer.RegisterUser(context.Background(), user)
 		// Assert
 		if err == nil {
 			t.Errorf("Test failed. Expected: There's an error.', Actual: No error")
@@ -317,7 +321,7 @@ func TestRegisterUser_ValidateAsRequired(t *testing.T) {
 
 	testValidateAsRequired := func(t *testing.T, userServer UserServer, user *UserForCommand) {
 		// Act
-		_, err := userServer.RegisterUser(user)
+		_, err := userServer.RegisterUser(context.Background(), user)
 		// Assert
 		if err == nil {
 			t.Errorf("Test failed. Expected: There's an error.', Actual: No error")
@@ -392,6 +396,39 @@ func TestRegisterUser_ValidateAsRequired(t *testing.T) {
 	})
 }
 
+func TestRegisterUser_ValidateErrors_FieldErrors(t *testing.T) {
+	// Prepare mock
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
+	tagServerMock := mock.NewMockTagServer(mockCtrl)
+	userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+	userServer := ProvideUserServer(userQueryRepositoryMock, tagServerMock, userCommandRepositoryMock)
+
+	// Arrange
+	user := genRegularUserForCommand()
+	user.Name = strings.Repeat("A", 201)
+	user.Email = ""
+	user.Location.Latitude = 91
+	user.Languages = []Language{"en", "fra"}
+	// Act
+	_, err := userServer.RegisterUser(context.Background(), user)
+	// Assert
+	ve, ok := err.(*domainerror.ValidationError)
+	if !ok {
+		t.Fatalf("Test failed. Expected: ValidationError', Actual: %v", err)
+	}
+	expected := []domainerror.FieldError{
+		{Field: "name", Rule: "max", Param: "200", Message: "name must be at most 200 in length"},
+		{Field: "email", Rule: "required", Message: "email is required"},
+		{Field: "location.latitude", Rule: "lte", Param: "90.0", Message: "location.latitude must be 90.0 or less"},
+		{Field: "languages[1]", Rule: "len", Param: "2", Message: "languages[1] must be 2 in length"},
+	}
+	if actual := ve.FieldErrors(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Test failed. Expected: %+v, Actual: %+v", expected, actual)
+	}
+}
+
 func TestRegisterUser_MapToDto(t *testing.T) {
 	// Prepare mock
 	mockCtrl := gomock.NewController(t)
@@ -399,11 +436,11 @@ func TestRegisterUser_MapToDto(t *testing.T) {
 	// No mock methods are called because this test case is for validation before calling mock methods
 	userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
 	userQueryRepositoryMock.EXPECT().
-		QueryUserFullByUsingUserId(gomock.Any()).
+		QueryUserFullByUsingUserId(gomock.Any(), gomock.Any()).
 		Return(&UserFullQueryDto{}, nil)
 	tagServerMock := mock.NewMockTagServer(mockCtrl)
 	tagServerMock.EXPECT().
-		GetTagsByTagTypeAndTagIds(gomock.Any(), gomock.Any()).
+		GetTagsByTagTypeAndTagIds(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return([]*tagservice.CategoryTags{
 			{}, {},
 		}, nil).
@@ -411,14 +448,14 @@ func TestRegisterUser_MapToDto(t *testing.T) {
 	userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
 	var actUserCommand *UserCommandDto
 	userCommandRepositoryMock.EXPECT().
-		InsertUserInfo(gomock.Any()).
-		Do(func(uDto *UserCommandDto) {
+		InsertUserInfo(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, uDto *UserCommandDto) {
 			actUserCommand = uDto
 		}).
 		Return(nil)
 	userServer := ProvideUserServer(userQueryRepositoryMock, tagServerMock, userCommandRepositoryMock)
 	// Act
-	_, err := userServer.RegisterUser(genRegularUserForCommand())
+	_, err := userServer.RegisterUser(context.Background(), genRegularUserForCommand())
 	// Assert
 	if err != nil {
 		t.Errorf("expected: %+v, got: %+v", nil, err)
@@ -467,10 +504,10 @@ func TestRegisterUserBlock(t *testing.T) {
 		// Mock
 		userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
 		userQueryRepositoryMock.EXPECT().
-			QueryUserBlockWhereBlocker(uid).
+			QueryUserBlockWhereBlocker(gomock.Any(), uid).
 			Return(regularUserBlockForQuery, nil)
 		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
-		userCommandRepositoryMock.EXPECT().InsertUserBlock(&UserBlockCommandDto{
+		userCommandRepositoryMock.EXPECT().InsertUserBlock(gomock.Any(), &UserBlockCommandDto{
 			blocker: uid,
 			blockee: aBlockee,
 		}).Return(nil)
@@ -481,7 +518,7 @@ func TestRegisterUserBlock(t *testing.T) {
 			Blockee: aBlockee,
 		}
 		// Act
-		ret, err := userServer.RegisterUserBlock(inputUserBlock)
+		ret, err := userServer.RegisterUserBlock(context.Background(), inputUserBlock)
 		// Assert
 		if err != nil {
 			t.Errorf("expected: nil, actual: %+v", err)
@@ -501,7 +538,7 @@ func TestRegisterUserBlock(t *testing.T) {
 
 	testValidateAsRequired := func(t *testing.T, userServer UserServer, input *UserBlockForCommand) {
 		// Act
-		_, err := userServer.RegisterUserBlock(input)
+		_, err := userServer.RegisterUserBlock(context.Background(), input)
 		// Assert
 		if err == nil {
 			t.Errorf("Test failed. Expected: There's an error.', Actual: No error")
@@ -543,3 +580,323 @@ func TestRegisterUserBlock(t *testing.T) {
 		testValidateAsRequired(t, userServer, input)
 	})
 }
+
+// updateUserInfoWith calls merge with the current user as the repository does in the transaction
+func updateUserInfoWith(current *UserFullQueryDto, merged **UserCommandDto) func(context.Context, string, func(*UserFullQueryDto) (*UserCommandDto, error)) error {
+	return func(_ context.Context, _ string, merge func(*UserFullQueryDto) (*UserCommandDto, error)) error {
+		uDto, err := merge(current)
+		*merged = uDto
+		return err
+	}
+}
+
+// expectUserTagTypes expects the tag master of the interest tags 1 to 3 and the skill tags 8 to 10
+func expectUserTagTypes(tagServerMock *mock.MockTagServer) {
+	category := tagservice.NewCategory(1, "Programming")
+	tagServerMock.EXPECT().
+		GetTagsByTagType(gomock.Any(), tagservice.Interest).
+		Return([]*tagservice.CategoryTags{
+			tagservice.NewCategoryTags(category, []*tagservice.SmallTag{
+				tagservice.NewSmallTag(1, "Go"), tagservice.NewSmallTag(2, "Python"), tagservice.NewSmallTag(3, "Ruby")}),
+		}, nil)
+	tagServerMock.EXPECT().
+		GetTagsByTagType(gomock.Any(), tagservice.Skill).
+		Return([]*tagservice.CategoryTags{
+			tagservice.NewCategoryTags(category, []*tagservice.SmallTag{
+				tagservice.NewSmallTag(8, "Rust"), tagservice.NewSmallTag(9, "Java"), tagservice.NewSmallTag(10, "C")}),
+		}, nil)
+}
+
+func TestUpdateUser(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
+		userQueryRepositoryMock.EXPECT().
+			QueryUserFullByUsingUserId(gomock.Any(), uid).
+			Return(&UserFullQueryDto{userId: uid}, nil)
+		tagServerMock := mock.NewMockTagServer(mockCtrl)
+		expectUserTagTypes(tagServerMock)
+		tagServerMock.EXPECT().
+			GetTagsByTagTypeAndTagIds(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*tagservice.CategoryTags{}, nil).
+			Times(2)
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		var actUserCommand *UserCommandDto
+		// The current user has the interest tag 2, the skill tag 9 and the tag 20 of another type
+		userCommandRepositoryMock.EXPECT().
+			UpdateUserInfo(gomock.Any(), uid, gomock.Any()).
+			DoAndReturn(updateUserInfoWith(&UserFullQueryDto{userId: uid, usertags: []uint16{2, 9, 20}}, &actUserCommand))
+		userServer := ProvideUserServer(userQueryRepositoryMock, tagServerMock, userCommandRepositoryMock)
+		// Act
+		user, err := userServer.UpdateUser(context.Background(), genRegularUserForCommand())
+		// Assert
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if user.UserId != uid {
+			t.Errorf("expected: %s, actual: %s", uid, user.UserId)
+		}
+		// The tag of another type is kept
+		if expected := []uint16{1, 2, 3, 8, 9, 10, 20}; !reflect.DeepEqual(actUserCommand.usertags, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, actUserCommand.usertags)
+		}
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		// No mock methods are called
+		userServer := ProvideUserServer(
+			NewMockIUserQueryRepository(mockCtrl),
+			mock.NewMockTagServer(mockCtrl),
+			NewMockIUserCommandRepository(mockCtrl),
+		)
+		user := genRegularUserForCommand()
+		user.SelfIntroduction = "Too short"
+		_, err := userServer.UpdateUser(context.Background(), user)
+		if _, ok := err.(*domainerror.ValidationError); !ok {
+			t.Errorf("expected: ValidationError, actual: %v", err)
+		}
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		tagServerMock := mock.NewMockTagServer(mockCtrl)
+		expectUserTagTypes(tagServerMock)
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		userCommandRepositoryMock.EXPECT().
+			UpdateUserInfo(gomock.Any(), uid, gomock.Any()).
+			Return(stew.Wrap(sql.ErrNoRows))
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), tagServerMock, userCommandRepositoryMock)
+		_, err := userServer.UpdateUser(context.Background(), genRegularUserForCommand())
+		if _, ok := err.(*UserNotFoundError); !ok {
+			t.Errorf("expected: UserNotFoundError, actual: %v", err)
+		}
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		tagServerMock := mock.NewMockTagServer(mockCtrl)
+		expectUserTagTypes(tagServerMock)
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		userCommandRepositoryMock.EXPECT().
+			UpdateUserInfo(gomock.Any(), uid, gomock.Any()).
+			Return(NewNoReferenceRowError(errors.New("Cannot add or update a child row")))
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), tagServerMock, userCommandRepositoryMock)
+		_, err := userServer.UpdateUser(context.Background(), genRegularUserForCommand())
+		if _, ok := err.(*UnknownUserReferenceError); !ok {
+			t.Errorf("expected: UnknownUserReferenceError, actual: %v", err)
+		}
+	})
+}
+
+func TestPatchUser(t *testing.T) {
+	var currentUserDto = &UserFullQueryDto{
+		userId:           uid,
+		name:             "David John",
+		email:            "a@a.com",
+		sex:              "1",
+		birthday:         time.Date(1992, 4, 4, 0, 0, 0, 0, time.UTC),
+		latitude:         35.681236,
+		longitude:        139.767125,
+		positionId:       sql.NullInt32{Int32: 2, Valid: true},
+		company:          sql.NullString{String: "Microsoft, Inc.", Valid: true},
+		selfIntroduction: sql.NullString{String: "Hello, I'm John. Nice to meet you! I look forward to seeing you guys!", Valid: true},
+		userlangs:        []string{English},
+		useroccupations:  []uint8{3},
+		usertags:         []uint16{1, 8, 20},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("only the given fields are updated", func(t *testing.T) {
+		// Arrange
+		userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
+		userQueryRepositoryMock.EXPECT().
+			QueryUserFullByUsingUserId(gomock.Any(), uid).
+			Return(currentUserDto, nil)
+		tagServerMock := mock.NewMockTagServer(mockCtrl)
+		// The tag master loaded before the transaction, which has a tag not of the user
+		tagServerMock.EXPECT().
+			GetTagsByTagType(gomock.Any(), tagservice.Interest).
+			Return([]*tagservice.CategoryTags{
+				tagservice.NewCategoryTags(tagservice.NewCategory(1, "Programming"), []*tagservice.SmallTag{tagservice.NewSmallTag(1, "Go"), tagservice.NewSmallTag(2, "Python")}),
+			}, nil)
+		tagServerMock.EXPECT().
+			GetTagsByTagType(gomock.Any(), tagservice.Skill).
+			Return([]*tagservice.CategoryTags{
+				tagservice.NewCategoryTags(tagservice.NewCategory(1, "Programming"), []*tagservice.SmallTag{tagservice.NewSmallTag(8, "Rust")}),
+			}, nil)
+		// The updated user
+		tagServerMock.EXPECT().
+			GetTagsByTagTypeAndTagIds(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*tagservice.CategoryTags{}, nil).
+			Times(2)
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		var actUserCommand *UserCommandDto
+		userCommandRepositoryMock.EXPECT().
+			UpdateUserInfo(gomock.Any(), uid, gomock.Any()).
+			DoAndReturn(updateUserInfoWith(currentUserDto, &actUserCommand))
+		userServer := ProvideUserServer(userQueryRepositoryMock, tagServerMock, userCommandRepositoryMock)
+		nickName := "Josh"
+		company := ""
+		// Act
+		_, err := userServer.PatchUser(context.Background(), uid, &UserForUpdate{
+			NickName:       &nickName,
+			Company:        &company,
+			InterestTagIds: []uint16{},
+		})
+		// Assert
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if actUserCommand.name != "David John" || actUserCommand.nickName.String != nickName || actUserCommand.company.Valid {
+			t.Errorf("unexpected user %+v", actUserCommand)
+		}
+		if actUserCommand.birthday != currentUserDto.birthday || actUserCommand.positionId.Int32 != 2 {
+			t.Errorf("unexpected user %+v", actUserCommand)
+		}
+		if !reflect.DeepEqual(actUserCommand.userlangs, []string{English}) || !reflect.DeepEqual(actUserCommand.occupationIDs, []uint8{3}) {
+			t.Errorf("unexpected user %+v", actUserCommand)
+		}
+		// The interest tags are cleared, and the skill tags and the tags of the other types are kept
+		if expected := []uint16{8, 20}; !reflect.DeepEqual(actUserCommand.usertags, expected) {
+			t.Errorf("expected: %v, actual: %v", expected, actUserCommand.usertags)
+		}
+	})
+
+	t.Run("merged user is validated", func(t *testing.T) {
+		tagServerMock := mock.NewMockTagServer(mockCtrl)
+		tagServerMock.EXPECT().
+			GetTagsByTagType(gomock.Any(), gomock.Any()).
+			Return([]*tagservice.CategoryTags{}, nil).
+			Times(2)
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		var actUserCommand *UserCommandDto
+		userCommandRepositoryMock.EXPECT().
+			UpdateUserInfo(gomock.Any(), uid, gomock.Any()).
+			DoAndReturn(updateUserInfoWith(currentUserDto, &actUserCommand))
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), tagServerMock, userCommandRepositoryMock)
+		// Clearing the required occupations
+		_, err := userServer.PatchUser(context.Background(), uid, &UserForUpdate{OccupationIDs: []uint8{}})
+		if _, ok := err.(*domainerror.ValidationError); !ok {
+			t.Errorf("expected: ValidationError, actual: %v", err)
+		}
+		if actUserCommand != nil {
+			t.Errorf("expected: no update, actual: %+v", actUserCommand)
+		}
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		userCommandRepositoryMock.EXPECT().
+			UpdateUserInfo(gomock.Any(), uid, gomock.Any()).
+			Return(stew.Wrap(sql.ErrNoRows))
+		tagServerMock := mock.NewMockTagServer(mockCtrl)
+		tagServerMock.EXPECT().
+			GetTagsByTagType(gomock.Any(), gomock.Any()).
+			Return([]*tagservice.CategoryTags{}, nil).
+			Times(2)
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), tagServerMock, userCommandRepositoryMock)
+		_, err := userServer.PatchUser(context.Background(), uid, &UserForUpdate{})
+		if _, ok := err.(*UserNotFoundError); !ok {
+			t.Errorf("expected: UserNotFoundError, actual: %v", err)
+		}
+	})
+}
+
+func TestDeleteUserBlock(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	var input = &UserBlockForCommand{
+		Blocker: uid,
+		Blockee: "blockee-user",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		userCommandRepositoryMock.EXPECT().DeleteUserBlock(gomock.Any(), &UserBlockCommandDto{
+			blocker: uid,
+			blockee: "blockee-user",
+		}).Return(nil)
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), mock.NewMockTagServer(mockCtrl), userCommandRepositoryMock)
+		ret, err := userServer.DeleteUserBlock(context.Background(), input)
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if *ret != *input {
+			t.Errorf("expected: %+v, actual: %+v", input, ret)
+		}
+	})
+
+	t.Run("not blocked", func(t *testing.T) {
+		userCommandRepositoryMock := NewMockIUserCommandRepository(mockCtrl)
+		userCommandRepositoryMock.EXPECT().DeleteUserBlock(gomock.Any(), gomock.Any()).Return(stew.Wrap(sql.ErrNoRows))
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), mock.NewMockTagServer(mockCtrl), userCommandRepositoryMock)
+		_, err := userServer.DeleteUserBlock(context.Background(), input)
+		if _, ok := err.(*UserBlockNotFoundError); !ok {
+			t.Errorf("expected: UserBlockNotFoundError, actual: %v", err)
+		}
+	})
+
+	t.Run("blockee is not set. required validation error", func(t *testing.T) {
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), mock.NewMockTagServer(mockCtrl), NewMockIUserCommandRepository(mockCtrl))
+		_, err := userServer.DeleteUserBlock(context.Background(), &UserBlockForCommand{Blocker: uid})
+		if _, ok := err.(*domainerror.ValidationError); !ok {
+			t.Errorf("expected: ValidationError, actual: %v", err)
+		}
+	})
+}
+
+func TestGetUserBlocks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	createdAt := time.Date(2020, 7, 17, 1, 2, 3, 0, time.UTC)
+	blocks := []*UserBlockQueryDto{
+		{blocker: uid, blockee: "user-blocked-1", createdAt: createdAt},
+		{blocker: uid, blockee: "user-blocked-2", createdAt: createdAt},
+		{blocker: uid, blockee: "user-blocked-3", createdAt: createdAt},
+	}
+
+	t.Run("the page followed by the next page", func(t *testing.T) {
+		userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
+		userQueryRepositoryMock.EXPECT().
+			QueryUserBlockPageWhereBlocker(gomock.Any(), uid, 4, 3).
+			Return(blocks, nil)
+		userServer := ProvideUserServer(userQueryRepositoryMock, mock.NewMockTagServer(mockCtrl), NewMockIUserCommandRepository(mockCtrl))
+		page, err := userServer.GetUserBlocks(context.Background(), &UserBlockPageQuery{Blocker: uid, Offset: 4, Limit: 2})
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if len(page.Blocks) != 2 || page.Blocks[1].Blockee != "user-blocked-2" || page.Blocks[1].CreatedAt != createdAt {
+			t.Errorf("unexpected blocks %+v", page.Blocks)
+		}
+		if page.NextOffset == nil || *page.NextOffset != 6 {
+			t.Errorf("expected: 6, actual: %v", page.NextOffset)
+		}
+	})
+
+	t.Run("the last page", func(t *testing.T) {
+		userQueryRepositoryMock := NewMockIUserQueryRepository(mockCtrl)
+		userQueryRepositoryMock.EXPECT().
+			QueryUserBlockPageWhereBlocker(gomock.Any(), uid, 0, 21).
+			Return(blocks, nil)
+		userServer := ProvideUserServer(userQueryRepositoryMock, mock.NewMockTagServer(mockCtrl), NewMockIUserCommandRepository(mockCtrl))
+		page, err := userServer.GetUserBlocks(context.Background(), &UserBlockPageQuery{Blocker: uid, Limit: 20})
+		if err != nil {
+			t.Fatalf("expected: nil, actual: %+v", err)
+		}
+		if len(page.Blocks) != 3 || page.NextOffset != nil {
+			t.Errorf("unexpected page %+v", page)
+		}
+	})
+
+	t.Run("limit is out of range. validation error", func(t *testing.T) {
+		userServer := ProvideUserServer(NewMockIUserQueryRepository(mockCtrl), mock.NewMockTagServer(mockCtrl), NewMockIUserCommandRepository(mockCtrl))
+		for _, limit := range []int{0, 101} {
+			_, err := userServer.GetUserBlocks(context.Background(), &UserBlockPageQuery{Blocker: uid, Limit: limit})
+			if _, ok := err.(*domainerror.ValidationError); !ok {
+				t.Errorf("expected: ValidationError for %d, actual: %v", limit, err)
+			}
+		}
+	})
+}
@@ -1,7 +1,7 @@
 package userservice
 
 import (
-	"fmt"
+	"net/http"
 
 	"github.com/momotaro98/mixlunch-service-api/domainerror"
 )
@@ -10,8 +10,74 @@ const (
 	DuplicateUserRegisterErrorCode domainerror.ErrorCode = iota + 301
 	DuplicateUserBlockRegisterErrorCode
 	InconsistencyUserBlockErrorCode
+	UserNotFoundErrorCode
+	UnknownUserReferenceErrorCode
+	UserBlockNotFoundErrorCode
 )
 
+func init() {
+	domainerror.Register(domainerror.Definition{
+		Code:        DuplicateUserRegisterErrorCode,
+		Name:        "duplicate_user",
+		Description: "The user is already registered.",
+		HTTPStatus:  http.StatusConflict,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The user is already in DB. User ID: %s",
+			domainerror.Japanese: "このユーザーは既に登録されています。User ID: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        DuplicateUserBlockRegisterErrorCode,
+		Name:        "duplicate_user_block",
+		Description: "The blocker already blocks the blockee.",
+		HTTPStatus:  http.StatusConflict,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The user blocker pair is already in DB. Blocker User ID: %s, Blockee User ID: %s",
+			domainerror.Japanese: "このユーザーは既にブロックされています。Blocker User ID: %s, Blockee User ID: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        InconsistencyUserBlockErrorCode,
+		Name:        "inconsistent_user_block",
+		Description: "The blocker or the blockee of the block doesn't exist.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The user blocker request has inconsistency. Check Blocker User ID: %s, Blockee User ID: %s",
+			domainerror.Japanese: "ブロックのリクエストに不整合があります。Blocker User ID: %s, Blockee User ID: %s を確認してください",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        UserNotFoundErrorCode,
+		Name:        "user_not_found",
+		Description: "The user is not registered.",
+		HTTPStatus:  http.StatusNotFound,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The user is not registered. User ID: %s",
+			domainerror.Japanese: "このユーザーは登録されていません。User ID: %s",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        UnknownUserReferenceErrorCode,
+		Name:        "unknown_user_reference",
+		Description: "The position, an occupation or a tag of the user doesn't exist.",
+		HTTPStatus:  http.StatusBadRequest,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The position, an occupation or a tag of the user doesn't exist. Check position_id, occupation_ids, interest_tag_ids and skill_tag_ids of User ID: %s",
+			domainerror.Japanese: "存在しないポジション、職種またはタグが指定されています。User ID: %s の position_id, occupation_ids, interest_tag_ids, skill_tag_ids を確認してください",
+		},
+	})
+	domainerror.Register(domainerror.Definition{
+		Code:        UserBlockNotFoundErrorCode,
+		Name:        "user_block_not_found",
+		Description: "The blocker doesn't block the blockee.",
+		HTTPStatus:  http.StatusNotFound,
+		Messages: domainerror.Messages{
+			domainerror.English:  "The user blocker pair is not in DB. Blocker User ID: %s, Blockee User ID: %s",
+			domainerror.Japanese: "このユーザーはブロックされていません。Blocker User ID: %s, Blockee User ID: %s",
+		},
+	})
+}
+
 type DuplicateUserRegisterError struct {
 	userId string
 }
@@ -25,14 +91,21 @@ func NewDuplicateUserRegisterError(userId string) *DuplicateUserRegisterError {
 }
 
 func (e *DuplicateUserRegisterError) Error() string {
-	return fmt.Sprintf("The user is already in DB. User ID: %s",
-		e.userId)
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *DuplicateUserRegisterError) MessageArgs() []interface{} {
+	return []interface{}{e.userId}
 }
 
 func (e *DuplicateUserRegisterError) Code() domainerror.ErrorCode {
 	return DuplicateUserRegisterErrorCode
 }
 
+func (e *DuplicateUserRegisterError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 type DuplicateUserBlockRegisterError struct {
 	blocker string
 	blockee string
@@ -48,14 +121,21 @@ func NewDuplicateUserBlockRegisterError(blocker, blockee string) *DuplicateUserB
 }
 
 func (e *DuplicateUserBlockRegisterError) Error() string {
-	return fmt.Sprintf("The user blocker pair is already in DB. Blocker User ID: %s, Blockee User ID: %s",
-		e.blocker, e.blockee)
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *DuplicateUserBlockRegisterError) MessageArgs() []interface{} {
+	return []interface{}{e.blocker, e.blockee}
 }
 
 func (e *DuplicateUserBlockRegisterError) Code() domainerror.ErrorCode {
 	return DuplicateUserBlockRegisterErrorCode
 }
 
+func (e *DuplicateUserBlockRegisterError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 type InconsistencyUserBlockError struct {
 	blocker string
 	blockee string
@@ -71,14 +151,107 @@ func NewInconsistencyUserBlockError(blocker, blockee string) *InconsistencyUserB
 }
 
 func (e *InconsistencyUserBlockError) Error() string {
-	return fmt.Sprintf("The user blocker request has inconsistency. Check Blocker User ID: %s, Blockee User ID: %s",
-		e.blocker, e.blockee)
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *InconsistencyUserBlockError) MessageArgs() []interface{} {
+	return []interface{}{e.blocker, e.blockee}
 }
 
 func (e *InconsistencyUserBlockError) Code() domainerror.ErrorCode {
 	return InconsistencyUserBlockErrorCode
 }
 
+func (e *InconsistencyUserBlockError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
+type UserNotFoundError struct {
+	userId string
+}
+
+var _ domainerror.DomainError = (*UserNotFoundError)(nil)
+
+func NewUserNotFoundError(userId string) *UserNotFoundError {
+	return &UserNotFoundError{
+		userId: userId,
+	}
+}
+
+func (e *UserNotFoundError) Error() string {
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *UserNotFoundError) MessageArgs() []interface{} {
+	return []interface{}{e.userId}
+}
+
+func (e *UserNotFoundError) Code() domainerror.ErrorCode {
+	return UserNotFoundErrorCode
+}
+
+func (e *UserNotFoundError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
+type UnknownUserReferenceError struct {
+	userId string
+}
+
+var _ domainerror.DomainError = (*UnknownUserReferenceError)(nil)
+
+func NewUnknownUserReferenceError(userId string) *UnknownUserReferenceError {
+	return &UnknownUserReferenceError{
+		userId: userId,
+	}
+}
+
+func (e *UnknownUserReferenceError) Error() string {
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *UnknownUserReferenceError) MessageArgs() []interface{} {
+	return []interface{}{e.userId}
+}
+
+func (e *UnknownUserReferenceError) Code() domainerror.ErrorCode {
+	return UnknownUserReferenceErrorCode
+}
+
+func (e *UnknownUserReferenceError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
+
 // TODO: Add out of master ID scope (location ID, tag ID)
 
 // TODO: Add Parse error (birthday, email?, languages?)
+
+type UserBlockNotFoundError struct {
+	blocker string
+	blockee string
+}
+
+var _ domainerror.DomainError = (*UserBlockNotFoundError)(nil)
+
+func NewUserBlockNotFoundError(blocker, blockee string) *UserBlockNotFoundError {
+	return &UserBlockNotFoundError{
+		blocker: blocker,
+		blockee: blockee,
+	}
+}
+
+func (e *UserBlockNotFoundError) Error() string {
+	return domainerror.Message(e, domainerror.English)
+}
+
+func (e *UserBlockNotFoundError) MessageArgs() []interface{} {
+	return []interface{}{e.blocker, e.blockee}
+}
+
+func (e *UserBlockNotFoundError) Code() domainerror.ErrorCode {
+	return UserBlockNotFoundErrorCode
+}
+
+func (e *UserBlockNotFoundError) HTTPStatus() int {
+	return domainerror.HTTPStatusOf(e.Code())
+}
@@ -1,8 +1,11 @@
 package userservice
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -19,6 +22,7 @@ type UserFullQueryDto struct {
 	photoUrl           sql.NullString
 	latitude           float64
 	longitude          float64
+	positionId         sql.NullInt32
 	positionName       sql.NullString
 	academicBackground sql.NullString
 	company            sql.NullString
@@ -30,8 +34,9 @@ type UserFullQueryDto struct {
 }
 
 type IUserQueryRepository interface {
-	QueryUserFullByUsingUserId(userId string) (*UserFullQueryDto, error)
-	QueryUserBlockWhereBlocker(blocker string) ([]*UserBlockQueryDto, error)
+	QueryUserFullByUsingUserId(ctx context.Context, userId string) (*UserFullQueryDto, error)
+	QueryUserBlockWhereBlocker(ctx context.Context, blocker string) ([]*UserBlockQueryDto, error)
+	QueryUserBlockPageWhereBlocker(ctx context.Context, blocker string, offset, limit int) ([]*UserBlockQueryDto, error)
 }
 
 var _ IUserQueryRepository = (*realUserQueryRepository)(nil)
@@ -46,7 +51,7 @@ func ProvideUserQueryRepository(db SqlDb) IUserQueryRepository {
 	}
 }
 
-func (r *realUserQueryRepository) QueryUserFullByUsingUserId(userId string) (*UserFullQueryDto, error) {
+func (r *realUserQueryRepository) QueryUserFullByUsingUserId(ctx context.Context, userId string) (*UserFullQueryDto, error) {
 	queryAUser := `SELECT u.userId
 			,u.name
 			,u.email
@@ -54,6 +59,7 @@ func (r *realUserQueryRepository) QueryUserFullByUsingUserId(userId string) (*Us
 			,u.sex
 			,u.birthday
 			,u.photoUrl
+			,u.positionId
 			,p.name AS positionName
 			,u.academicBackground
 			,u.company
@@ -62,9 +68,9 @@ func (r *realUserQueryRepository) QueryUserFullByUsingUserId(userId string) (*Us
 		LEFT JOIN positions AS p ON u.positionId=p.positionId
 		WHERE u.userId = ?`
 	var u UserFullQueryDto
-	if err := r.db.QueryRow(queryAUser, userId).Scan(
+	if err := r.db.QueryRowContext(ctx, queryAUser, userId).Scan(
 		&u.userId, &u.name, &u.email, &u.nickName, &u.sex,
-		&u.birthday, &u.photoUrl, &u.positionName,
+		&u.birthday, &u.photoUrl, &u.positionId, &u.positionName,
 		&u.academicBackground, &u.company, &u.selfIntroduction); err != nil {
 		return nil, err
 	}
@@ -72,31 +78,31 @@ func (r *realUserQueryRepository) QueryUserFullByUsingUserId(userId string) (*Us
 	var err error
 
 	// userlocations
-	u.latitude, u.longitude, err = r.queryUserLocation(userId)
+	u.latitude, u.longitude, err = r.queryUserLocation(ctx, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 
 	// userlangs
-	u.userlangs, err = r.queryUserLangs(userId)
+	u.userlangs, err = r.queryUserLangs(ctx, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 
 	// useroccupations
-	u.useroccupations, err = r.queryUserOccupations(userId)
+	u.useroccupations, err = r.queryUserOccupations(ctx, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 
 	// usertags
-	u.usertags, err = r.queryUserTags(userId)
+	u.usertags, err = r.queryUserTags(ctx, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
 
 	// blocking users
-	userBlockedQDto, err := r.QueryUserBlockWhereBlocker(userId)
+	userBlockedQDto, err := r.QueryUserBlockWhereBlocker(ctx, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
 	}
@@ -107,7 +113,7 @@ func (r *realUserQueryRepository) QueryUserFullByUsingUserId(userId string) (*Us
 	return &u, nil
 }
 
-func (r *realUserQueryRepository) queryUserLocation(userId string) (latitude, longitude float64, err error) {
+func (r *realUserQueryRepository) queryUserLocation(ctx context.Context, userId string) (latitude, longitude float64, err error) {
 	var loc = struct {
 		lat float64
 		lng float64
@@ -115,7 +121,7 @@ func (r *realUserQueryRepository) queryUserLocation(userId string) (latitude, lo
 		lat: latitude,
 		lng: longitude,
 	}
-	if err := r.db.QueryRow(`
+	if err := r.db.QueryRowContext(ctx, `
 		SELECT latitude, longitude
 		FROM userlocations
 		WHERE userId = ?`, userId).Scan(
@@ -126,8 +132,8 @@ func (r *realUserQueryRepository) queryUserLocation(userId string) (latitude, lo
 	return loc.lat, loc.lng, nil
 }
 
-func (r *realUserQueryRepository) queryUserLangs(userId string) (langs []string, err error) {
-	rows, err := r.db.Query(`SELECT lang FROM userlangs
+func (r *realUserQueryRepository) queryUserLangs(ctx context.Context, userId string) (langs []string, err error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT lang FROM userlangs
 		WHERE userId = ?`, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
@@ -142,14 +148,14 @@ func (r *realUserQueryRepository) queryUserLangs(userId string) (langs []string,
 	return langs, nil
 }
 
-func (r *realUserQueryRepository) queryUserOccupations(userId string) (occupations []uint8, err error) {
+func (r *realUserQueryRepository) queryUserOccupations(ctx context.Context, userId string) (occupations []uint8, err error) {
 	// [Note] For now, occupations table master is not needed for API since
 	//        Front side has the occupation master instead.
-	//rows, err := r.db.Query(`SELECT o.name AS occupationName
+	//rows, err := r.db.QueryContext(ctx, `SELECT o.name AS occupationName
 	//	FROM useroccupations AS uo
 	//	INNER JOIN occupations AS o ON uo.occupationId=o.occupationId
 	//	WHERE uo.userId = ?`, userId)
-	rows, err := r.db.Query(`SELECT uo.occupationId AS occupationName
+	rows, err := r.db.QueryContext(ctx, `SELECT uo.occupationId AS occupationName
 		FROM useroccupations AS uo
 		WHERE uo.userId = ?`, userId)
 	if err != nil {
@@ -166,8 +172,8 @@ func (r *realUserQueryRepository) queryUserOccupations(userId string) (occupatio
 	return occupations, nil
 }
 
-func (r *realUserQueryRepository) queryUserTags(userId string) (tagIds []uint16, err error) {
-	rows, err := r.db.Query(`SELECT tagId FROM usertags
+func (r *realUserQueryRepository) queryUserTags(ctx context.Context, userId string) (tagIds []uint16, err error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT tagId FROM usertags
 		WHERE userId = ?`, userId)
 	if err != nil {
 		return nil, stew.Wrap(err)
@@ -188,8 +194,8 @@ type UserBlockQueryDto struct {
 	createdAt time.Time
 }
 
-func (r *realUserQueryRepository) QueryUserBlockWhereBlocker(blocker string) ([]*UserBlockQueryDto, error) {
-	rows, err := r.db.Query(`
+func (r *realUserQueryRepository) QueryUserBlockWhereBlocker(ctx context.Context, blocker string) ([]*UserBlockQueryDto, error) {
+	rows, err := r.db.QueryContext(ctx, `
 		SELECT blocker, blockee, createdAt FROM userblocklists
 		WHERE blocker = ?`, blocker)
 	if err != nil {
@@ -206,6 +212,32 @@ func (r *realUserQueryRepository) QueryUserBlockWhereBlocker(blocker string) ([]
 	return ret, nil
 }
 
+// QueryUserBlockPageWhereBlocker queries the blocks of the blocker from the newest, skipping offset blocks.
+// The blockee breaks the tie of the blocks at the same time to keep the pages stable.
+func (r *realUserQueryRepository) QueryUserBlockPageWhereBlocker(ctx context.Context, blocker string, offset, limit int) ([]*UserBlockQueryDto, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT blocker, blockee, createdAt FROM userblocklists
+		WHERE blocker = ?
+		ORDER BY createdAt DESC, blockee
+		LIMIT ? OFFSET ?`, blocker, limit, offset)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	defer rows.Close()
+	var ret []*UserBlockQueryDto
+	for rows.Next() {
+		var qDto UserBlockQueryDto
+		if err := rows.Scan(&qDto.blocker, &qDto.blockee, &qDto.createdAt); err != nil {
+			return nil, stew.Wrap(err)
+		}
+		ret = append(ret, &qDto)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, stew.Wrap(err)
+	}
+	return ret, nil
+}
+
 type UserCommandDto struct {
 	userId             string
 	name               string
@@ -226,8 +258,10 @@ type UserCommandDto struct {
 }
 
 type IUserCommandRepository interface {
-	InsertUserInfo(user *UserCommandDto) error
-	InsertUserBlock(userBlock *UserBlockCommandDto) error
+	InsertUserInfo(ctx context.Context, user *UserCommandDto) error
+	UpdateUserInfo(ctx context.Context, userId string, merge func(current *UserFullQueryDto) (*UserCommandDto, error)) error
+	InsertUserBlock(ctx context.Context, userBlock *UserBlockCommandDto) error
+	DeleteUserBlock(ctx context.Context, userBlock *UserBlockCommandDto) error
 }
 
 var _ IUserCommandRepository = (*realUserCommandRepository)(nil)
@@ -242,20 +276,20 @@ func ProvideUserCommandRepository(db SqlDb) IUserCommandRepository {
 	}
 }
 
-func (r *realUserCommandRepository) InsertUserInfo(u *UserCommandDto) error {
-	tx, err := r.db.Begin()
+func (r *realUserCommandRepository) InsertUserInfo(ctx context.Context, u *UserCommandDto) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return stew.Wrap(err)
 	}
 
 	// users table
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO users (userId, name, email, nickName, sex, birthday, photoUrl, positionId, academicBackground, company, selfIntroduction)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, u.userId, u.name, u.email, u.nickName, u.sex, u.birthday, u.photoUrl, u.positionId, u.academicBackground, u.company, u.selfIntroduction)
 	if err != nil {
 		if err := tx.Rollback(); err != nil {
-			panic(err)
+			return stew.Wrap(err)
 		}
 		var e *mysql.MySQLError
 		if errors.As(err, &e) {
@@ -268,26 +302,26 @@ func (r *realUserCommandRepository) InsertUserInfo(u *UserCommandDto) error {
 	}
 
 	// userlocations table
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO userlocations (userId, latitude, longitude)
 		VALUES (?, ?, ?)
 		`, u.userId, u.latitude, u.longitude)
 	if err != nil {
 		if err := tx.Rollback(); err != nil {
-			panic(err)
+			return stew.Wrap(err)
 		}
 		return stew.Wrap(err)
 	}
 
 	// userlangs table
 	for _, l := range u.userlangs {
-		_, err := tx.Exec(`
+		_, err := tx.ExecContext(ctx, `
 			INSERT INTO userlangs (userId, lang)
 			VALUES (?, ?)
 		`, u.userId, l)
 		if err != nil {
 			if err := tx.Rollback(); err != nil {
-				panic(err)
+				return stew.Wrap(err)
 			}
 			return err
 		}
@@ -295,13 +329,13 @@ func (r *realUserCommandRepository) InsertUserInfo(u *UserCommandDto) error {
 
 	// useroccupations table
 	for _, oID := range u.occupationIDs {
-		_, err := tx.Exec(`
+		_, err := tx.ExecContext(ctx, `
 			INSERT INTO useroccupations (userId, occupationId)
 			VALUES (?, ?)
         `, u.userId, oID)
 		if err != nil {
 			if err := tx.Rollback(); err != nil {
-				panic(err)
+				return stew.Wrap(err)
 			}
 			return stew.Wrap(err)
 		}
@@ -309,39 +343,273 @@ func (r *realUserCommandRepository) InsertUserInfo(u *UserCommandDto) error {
 
 	// usertags table
 	for _, tagId := range u.usertags {
-		_, err := tx.Exec(`
+		_, err := tx.ExecContext(ctx, `
 			INSERT INTO usertags (userId, tagId)
 			VALUES (?, ?)
 		`, u.userId, tagId)
 		if err != nil {
 			if err := tx.Rollback(); err != nil {
-				panic(err)
+				return stew.Wrap(err)
 			}
 			return stew.Wrap(err)
 		}
 	}
 
+	// A failed commit has already finished the transaction so that it needs no rollback
 	if err := tx.Commit(); err != nil {
+		return stew.Wrap(err)
+	}
+	return nil
+}
+
+// UpdateUserInfo locks and reads the current user, and updates the user with the one made by merge in a transaction
+// so that no other update is lost between the read and the update.
+// The user and its location are updated, and the languages, the occupations and the tags are replaced
+// with the ones of the merged DTO by the differences.
+// The error of merge is returned as it is. It returns sql.ErrNoRows when the user isn't registered,
+// and NoReferenceRowError for an unknown position, occupation or tag.
+func (r *realUserCommandRepository) UpdateUserInfo(ctx context.Context, userId string, merge func(current *UserFullQueryDto) (*UserCommandDto, error)) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return stew.Wrap(err)
+	}
+
+	err = func() error {
+		current, err := queryUserForUpdate(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+		u, err := merge(current)
+		if err != nil {
+			return err
+		}
+		return updateUserInfo(ctx, tx, u)
+	}()
+	if err != nil {
 		if err := tx.Rollback(); err != nil {
-			panic(err)
+			return stew.Wrap(err)
+		}
+		return err
+	}
+
+	// A failed commit has already finished the transaction so that it needs no rollback
+	if err := tx.Commit(); err != nil {
+		return stew.Wrap(err)
+	}
+	return nil
+}
+
+// queryUserForUpdate queries the user with its location, languages, occupations and tags locking them.
+// The position name and the blocking users are not queried as they are not updated.
+func queryUserForUpdate(ctx context.Context, tx *sql.Tx, userId string) (*UserFullQueryDto, error) {
+	var (
+		u                   UserFullQueryDto
+		latitude, longitude sql.NullFloat64
+	)
+	if err := tx.QueryRowContext(ctx, `
+		SELECT u.userId, u.name, u.email, u.nickName, u.sex, u.birthday, u.photoUrl, u.positionId,
+			u.academicBackground, u.company, u.selfIntroduction, l.latitude, l.longitude
+		FROM users AS u
+		LEFT JOIN userlocations AS l ON u.userId = l.userId
+		WHERE u.userId = ? FOR UPDATE
+		`, userId).Scan(
+		&u.userId, &u.name, &u.email, &u.nickName, &u.sex, &u.birthday, &u.photoUrl, &u.positionId,
+		&u.academicBackground, &u.company, &u.selfIntroduction, &latitude, &longitude); err != nil {
+		return nil, stew.Wrap(err)
+	}
+	u.latitude, u.longitude = latitude.Float64, longitude.Float64
+
+	var err error
+	if u.userlangs, err = queryChildValues(ctx, tx, userlangsTable, userId); err != nil {
+		return nil, err
+	}
+	occupationIDs, err := queryChildValues(ctx, tx, useroccupationsTable, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range occupationIDs {
+		oID, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		u.useroccupations = append(u.useroccupations, uint8(oID))
+	}
+	tagIds, err := queryChildValues(ctx, tx, usertagsTable, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range tagIds {
+		tagId, err := strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return nil, stew.Wrap(err)
+		}
+		u.usertags = append(u.usertags, uint16(tagId))
+	}
+	return &u, nil
+}
+
+func updateUserInfo(ctx context.Context, tx *sql.Tx, u *UserCommandDto) error {
+	// Locking the user not to interleave the replacements of the child tables with another update
+	var userId string
+	if err := tx.QueryRowContext(ctx, `
+		SELECT userId FROM users
+		WHERE userId = ? FOR UPDATE
+		`, u.userId).Scan(&userId); err != nil {
+		return stew.Wrap(err)
+	}
+
+	// users table
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users
+		SET name = ?, email = ?, nickName = ?, sex = ?, birthday = ?, photoUrl = ?, positionId = ?, academicBackground = ?, company = ?, selfIntroduction = ?
+		WHERE userId = ?
+		`, u.name, u.email, u.nickName, u.sex, u.birthday, u.photoUrl, u.positionId, u.academicBackground, u.company, u.selfIntroduction, u.userId); err != nil {
+		var e *mysql.MySQLError
+		if errors.As(err, &e) && e.Number == RepoErrCodeMapToRDBMS[NoReferenceRowErrorCode] {
+			return NewNoReferenceRowError(err)
 		}
 		return stew.Wrap(err)
 	}
+
+	// userlocations table, which the users registered without a location don't have
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO userlocations (userId, latitude, longitude)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE latitude = VALUES(latitude), longitude = VALUES(longitude)
+		`, u.userId, u.latitude, u.longitude); err != nil {
+		return stew.Wrap(err)
+	}
+
+	// userlangs, useroccupations and usertags tables
+	occupationIDs := make([]string, 0, len(u.occupationIDs))
+	for _, oID := range u.occupationIDs {
+		occupationIDs = append(occupationIDs, strconv.Itoa(int(oID)))
+	}
+	tagIds := make([]string, 0, len(u.usertags))
+	for _, tagId := range u.usertags {
+		tagIds = append(tagIds, strconv.Itoa(int(tagId)))
+	}
+	for _, c := range []struct {
+		table  childTable
+		values []string
+	}{
+		{userlangsTable, u.userlangs},
+		{useroccupationsTable, occupationIDs},
+		{usertagsTable, tagIds},
+	} {
+		if err := replaceChildRows(ctx, tx, c.table, u.userId, c.values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// childTable is a table of the values of a user, whose primary key is the user and the value
+type childTable struct {
+	name   string
+	column string
+}
+
+var (
+	userlangsTable       = childTable{name: "userlangs", column: "lang"}
+	useroccupationsTable = childTable{name: "useroccupations", column: "occupationId"}
+	usertagsTable        = childTable{name: "usertags", column: "tagId"}
+)
+
+// replaceChildRows makes the values of the user in the table the given ones by deleting and inserting
+// only the differences, which keeps createdAt of the unchanged rows.
+// It returns NoReferenceRowError when a value isn't in the master table, e.g. an unknown tag ID.
+func replaceChildRows(ctx context.Context, tx *sql.Tx, t childTable, userId string, values []string) error {
+	current, err := queryChildValues(ctx, tx, t, userId)
+	if err != nil {
+		return err
+	}
+
+	deleted, inserted := diffChildValues(current, values)
+	for _, v := range deleted {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE userId = ? AND %s = ?
+			`, t.name, t.column), userId, v); err != nil {
+			return stew.Wrap(err)
+		}
+	}
+	for _, v := range inserted {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO %s (userId, %s)
+			VALUES (?, ?)
+			`, t.name, t.column), userId, v); err != nil {
+			var e *mysql.MySQLError
+			if errors.As(err, &e) && e.Number == RepoErrCodeMapToRDBMS[NoReferenceRowErrorCode] {
+				return NewNoReferenceRowError(err)
+			}
+			return stew.Wrap(err)
+		}
+	}
 	return nil
 }
 
+// queryChildValues queries the values of the user in the table locking them
+func queryChildValues(ctx context.Context, tx *sql.Tx, t childTable, userId string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE userId = ? FOR UPDATE
+		`, t.column, t.name), userId)
+	if err != nil {
+		return nil, stew.Wrap(err)
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, stew.Wrap(err)
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, stew.Wrap(err)
+	}
+	return values, nil
+}
+
+// diffChildValues returns the current values not desired and the desired values not current in order.
+// The duplicated desired values are inserted once.
+func diffChildValues(current, desired []string) (deleted, inserted []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, v := range current {
+		currentSet[v] = true
+	}
+	desiredSet := make(map[string]bool, len(desired))
+	for _, v := range desired {
+		if desiredSet[v] {
+			continue
+		}
+		desiredSet[v] = true
+		if !currentSet[v] {
+			inserted = append(inserted, v)
+		}
+	}
+	for _, v := range current {
+		if !desiredSet[v] {
+			deleted = append(deleted, v)
+		}
+	}
+	return deleted, inserted
+}
+
 type UserBlockCommandDto struct {
 	blocker string
 	blockee string
 }
 
-func (r *realUserCommandRepository) InsertUserBlock(ub *UserBlockCommandDto) error {
-	tx, err := r.db.Begin()
+func (r *realUserCommandRepository) InsertUserBlock(ctx context.Context, ub *UserBlockCommandDto) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return stew.Wrap(err)
 	}
 
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO userblocklists (blocker, blockee)
 		VALUES (?, ?)
 		`, ub.blocker, ub.blockee)
@@ -361,12 +629,29 @@ func (r *realUserCommandRepository) InsertUserBlock(ub *UserBlockCommandDto) err
 		return stew.Wrap(err)
 	}
 
+	// A failed commit has already finished the transaction so that it needs no rollback
 	if err := tx.Commit(); err != nil {
-		if err := tx.Rollback(); err != nil {
-			panic(err)
-		}
 		return stew.Wrap(err)
 	}
 
 	return nil
 }
+
+// DeleteUserBlock deletes the block. It returns sql.ErrNoRows when the blocker doesn't block the blockee.
+func (r *realUserCommandRepository) DeleteUserBlock(ctx context.Context, ub *UserBlockCommandDto) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM userblocklists
+		WHERE blocker = ? AND blockee = ?
+		`, ub.blocker, ub.blockee)
+	if err != nil {
+		return stew.Wrap(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return stew.Wrap(err)
+	}
+	if n == 0 {
+		return stew.Wrap(sql.ErrNoRows)
+	}
+	return nil
+}
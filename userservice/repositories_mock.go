@@ -5,6 +5,7 @@
 package userservice
 
 import (
+	context "context"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -33,33 +34,48 @@ func (m *MockIUserQueryRepository) EXPECT() *MockIUserQueryRepositoryMockRecorde
 }
 
 // QueryUserFullByUsingUserId mocks base method
-func (m *MockIUserQueryRepository) QueryUserFullByUsingUserId(userId string) (*UserFullQueryDto, error) {
+func (m *MockIUserQueryRepository) QueryUserFullByUsingUserId(ctx context.Context, userId string) (*UserFullQueryDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryUserFullByUsingUserId", userId)
+	ret := m.ctrl.Call(m, "QueryUserFullByUsingUserId", ctx, userId)
 	ret0, _ := ret[0].(*UserFullQueryDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryUserFullByUsingUserId indicates an expected call of QueryUserFullByUsingUserId
-func (mr *MockIUserQueryRepositoryMockRecorder) QueryUserFullByUsingUserId(userId interface{}) *gomock.Call {
+func (mr *MockIUserQueryRepositoryMockRecorder) QueryUserFullByUsingUserId(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserFullByUsingUserId", reflect.TypeOf((*MockIUserQueryRepository)(nil).QueryUserFullByUsingUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserFullByUsingUserId", reflect.TypeOf((*MockIUserQueryRepository)(nil).QueryUserFullByUsingUserId), ctx, userId)
 }
 
 // QueryUserBlockWhereBlocker mocks base method
-func (m *MockIUserQueryRepository) QueryUserBlockWhereBlocker(blocker string) ([]*UserBlockQueryDto, error) {
+func (m *MockIUserQueryRepository) QueryUserBlockWhereBlocker(ctx context.Context, blocker string) ([]*UserBlockQueryDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryUserBlockWhereBlocker", blocker)
+	ret := m.ctrl.Call(m, "QueryUserBlockWhereBlocker", ctx, blocker)
 	ret0, _ := ret[0].([]*UserBlockQueryDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryUserBlockWhereBlocker indicates an expected call of QueryUserBlockWhereBlocker
-func (mr *MockIUserQueryRepositoryMockRecorder) QueryUserBlockWhereBlocker(blocker interface{}) *gomock.Call {
+func (mr *MockIUserQueryRepositoryMockRecorder) QueryUserBlockWhereBlocker(ctx, blocker interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserBlockWhereBlocker", reflect.TypeOf((*MockIUserQueryRepository)(nil).QueryUserBlockWhereBlocker), blocker)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserBlockWhereBlocker", reflect.TypeOf((*MockIUserQueryRepository)(nil).QueryUserBlockWhereBlocker), ctx, blocker)
+}
+
+// QueryUserBlockPageWhereBlocker mocks base method
+func (m *MockIUserQueryRepository) QueryUserBlockPageWhereBlocker(ctx context.Context, blocker string, offset, limit int) ([]*UserBlockQueryDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryUserBlockPageWhereBlocker", ctx, blocker, offset, limit)
+	ret0, _ := ret[0].([]*UserBlockQueryDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryUserBlockPageWhereBlocker indicates an expected call of QueryUserBlockPageWhereBlocker
+func (mr *MockIUserQueryRepositoryMockRecorder) QueryUserBlockPageWhereBlocker(ctx, blocker, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUserBlockPageWhereBlocker", reflect.TypeOf((*MockIUserQueryRepository)(nil).QueryUserBlockPageWhereBlocker), ctx, blocker, offset, limit)
 }
 
 // MockIUserCommandRepository is a mock of IUserCommandRepository interface
@@ -86,29 +102,57 @@ func (m *MockIUserCommandRepository) EXPECT() *MockIUserCommandRepositoryMockRec
 }
 
 // InsertUserInfo mocks base method
-func (m *MockIUserCommandRepository) InsertUserInfo(user *UserCommandDto) error {
+func (m *MockIUserCommandRepository) InsertUserInfo(ctx context.Context, user *UserCommandDto) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUserInfo", user)
+	ret := m.ctrl.Call(m, "InsertUserInfo", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InsertUserInfo indicates an expected call of InsertUserInfo
-func (mr *MockIUserCommandRepositoryMockRecorder) InsertUserInfo(user interface{}) *gomock.Call {
+func (mr *MockIUserCommandRepositoryMockRecorder) InsertUserInfo(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserInfo", reflect.TypeOf((*MockIUserCommandRepository)(nil).InsertUserInfo), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserInfo", reflect.TypeOf((*MockIUserCommandRepository)(nil).InsertUserInfo), ctx, user)
+}
+
+// UpdateUserInfo mocks base method
+func (m *MockIUserCommandRepository) UpdateUserInfo(ctx context.Context, userId string, merge func(*UserFullQueryDto) (*UserCommandDto, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserInfo", ctx, userId, merge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserInfo indicates an expected call of UpdateUserInfo
+func (mr *MockIUserCommandRepositoryMockRecorder) UpdateUserInfo(ctx, userId, merge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserInfo", reflect.TypeOf((*MockIUserCommandRepository)(nil).UpdateUserInfo), ctx, userId, merge)
 }
 
 // InsertUserBlock mocks base method
-func (m *MockIUserCommandRepository) InsertUserBlock(userBlock *UserBlockCommandDto) error {
+func (m *MockIUserCommandRepository) InsertUserBlock(ctx context.Context, userBlock *UserBlockCommandDto) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertUserBlock", userBlock)
+	ret := m.ctrl.Call(m, "InsertUserBlock", ctx, userBlock)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InsertUserBlock indicates an expected call of InsertUserBlock
-func (mr *MockIUserCommandRepositoryMockRecorder) InsertUserBlock(userBlock interface{}) *gomock.Call {
+func (mr *MockIUserCommandRepositoryMockRecorder) InsertUserBlock(ctx, userBlock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserBlock", reflect.TypeOf((*MockIUserCommandRepository)(nil).InsertUserBlock), ctx, userBlock)
+}
+
+// DeleteUserBlock mocks base method
+func (m *MockIUserCommandRepository) DeleteUserBlock(ctx context.Context, userBlock *UserBlockCommandDto) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserBlock", ctx, userBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserBlock indicates an expected call of DeleteUserBlock
+func (mr *MockIUserCommandRepositoryMockRecorder) DeleteUserBlock(ctx, userBlock interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserBlock", reflect.TypeOf((*MockIUserCommandRepository)(nil).InsertUserBlock), userBlock)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserBlock", reflect.TypeOf((*MockIUserCommandRepository)(nil).DeleteUserBlock), ctx, userBlock)
 }
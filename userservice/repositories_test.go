@@ -0,0 +1,29 @@
+package userservice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffChildValues(t *testing.T) {
+	tests := []struct {
+		name             string
+		current, desired []string
+		deleted          []string
+		inserted         []string
+	}{
+		{name: "no change", current: []string{"en", "ja"}, desired: []string{"ja", "en"}},
+		{name: "replaced", current: []string{"en", "ja"}, desired: []string{"ja", "fr"}, deleted: []string{"en"}, inserted: []string{"fr"}},
+		{name: "cleared", current: []string{"1", "2"}, desired: []string{}, deleted: []string{"1", "2"}},
+		{name: "new", current: nil, desired: []string{"3", "1"}, inserted: []string{"3", "1"}},
+		{name: "duplicated desired values", current: []string{"1"}, desired: []string{"2", "1", "2"}, inserted: []string{"2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted, inserted := diffChildValues(tt.current, tt.desired)
+			if !reflect.DeepEqual(deleted, tt.deleted) || !reflect.DeepEqual(inserted, tt.inserted) {
+				t.Errorf("expected: %v %v, actual: %v %v", tt.deleted, tt.inserted, deleted, inserted)
+			}
+		})
+	}
+}
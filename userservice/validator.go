@@ -2,6 +2,8 @@ package userservice
 
 import (
 	"github.com/go-playground/validator/v10"
+
+	"github.com/momotaro98/mixlunch-service-api/domainerror"
 )
 
 var (
@@ -10,6 +12,7 @@ var (
 
 func init() {
 	validate = validator.New()
+	validate.RegisterTagNameFunc(domainerror.JSONTagName)
 	//if err := validate.RegisterValidation("custom_tag_name", func(fl validator.FieldLevel) bool {
 	//	return false
 	//}); err != nil {